@@ -0,0 +1,197 @@
+package jupag
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// alertEventBuffer is the number of alert events buffered on the Events channel before they are dropped.
+const alertEventBuffer = 64
+
+// AlertComparison is the condition of an alert rule on the price.
+type AlertComparison string
+
+const (
+	AlertAbove AlertComparison = "above" // fires when the price reaches the threshold or more
+	AlertBelow AlertComparison = "below" // fires when the price reaches the threshold or less
+)
+
+// AlertRule is a price alert.
+type AlertRule struct {
+	Mint       string
+	Comparison AlertComparison
+	Threshold  float64 // USD price, or in the vs token of the alerts
+	// HysteresisPct is how far past the threshold, in percent of it, the price must come back before the rule
+	// fires again, so a price hovering around the threshold doesn't flap, default: 0.5.
+	HysteresisPct float64
+	Callback      func(AlertEvent) // optional, called on the polling goroutine and must not block
+}
+
+// AlertEvent is a fired alert, or a polling error when Err is set.
+type AlertEvent struct {
+	Time   time.Time
+	RuleID string
+	Rule   AlertRule
+	Price  Price
+	Err    error
+}
+
+type alertState struct {
+	rule  AlertRule
+	armed bool
+}
+
+// Alerts polls the prices of the mints of its rules and fires the rules whose threshold is crossed,
+// see NewAlerts. It is safe for concurrent use.
+type Alerts struct {
+	client  *JupagImpl
+	vsToken string
+	events  chan AlertEvent
+
+	mu     sync.Mutex
+	rules  map[string]*alertState
+	nextID int
+}
+
+// NewAlerts returns alerts on the USD prices, or on the prices in vsToken when set. Rules are added with Add
+// and evaluated by Run.
+func (c *JupagImpl) NewAlerts(vsToken string) *Alerts {
+	return &Alerts{
+		client:  c,
+		vsToken: vsToken,
+		events:  make(chan AlertEvent, alertEventBuffer),
+		rules:   make(map[string]*alertState),
+	}
+}
+
+// Add registers a rule and returns its id. The rule fires on the first poll if its condition already holds.
+func (a *Alerts) Add(rule AlertRule) (string, error) {
+	if rule.Mint == "" {
+		return "", errors.New("alert mint is required")
+	}
+	if rule.Comparison != AlertAbove && rule.Comparison != AlertBelow {
+		return "", errors.New("alert comparison must be above or below")
+	}
+	if rule.HysteresisPct <= 0 {
+		rule.HysteresisPct = 0.5
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.nextID++
+	id := strconv.Itoa(a.nextID)
+	a.rules[id] = &alertState{rule: rule, armed: true}
+	return id, nil
+}
+
+// Remove unregisters a rule.
+func (a *Alerts) Remove(id string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.rules, id)
+}
+
+// Events returns the channel receiving the fired alerts and the polling errors. Events are dropped when
+// it lags behind. It is closed when Run returns.
+func (a *Alerts) Events() <-chan AlertEvent {
+	return a.events
+}
+
+// Run polls the prices every interval and fires the rules until ctx is done. The interval defaults to 10s when
+// not positive.
+func (a *Alerts) Run(ctx context.Context, interval time.Duration) {
+	defer close(a.events)
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		a.poll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll fetches the prices of the rule mints and evaluates the rules.
+func (a *Alerts) poll(ctx context.Context) {
+	a.mu.Lock()
+	mints := make([]string, 0, len(a.rules))
+	for _, s := range a.rules {
+		mints = append(mints, s.rule.Mint)
+	}
+	a.mu.Unlock()
+	if len(mints) == 0 {
+		return
+	}
+
+	prices, err := a.client.batchPrices(ctx, mints, PriceParams{VsToken: a.vsToken})
+	now := time.Now()
+	if err != nil {
+		if ctx.Err() == nil {
+			a.emit(AlertEvent{Time: now, Err: err})
+		}
+		return
+	}
+
+	var fired []AlertEvent
+	a.mu.Lock()
+	for id, s := range a.rules {
+		p, ok := prices[s.rule.Mint]
+		if !ok {
+			continue
+		}
+		if s.evaluate(p.decimal().Float64()) {
+			fired = append(fired, AlertEvent{Time: now, RuleID: id, Rule: s.rule, Price: p})
+		}
+	}
+	a.mu.Unlock()
+
+	for _, e := range fired {
+		if e.Rule.Callback != nil {
+			e.Rule.Callback(e)
+		}
+		a.emit(e)
+	}
+}
+
+// evaluate reports whether the rule fires at the price. A fired rule is rearmed once the price is back
+// past the threshold by the hysteresis.
+func (s *alertState) evaluate(price float64) bool {
+	band := s.rule.Threshold * s.rule.HysteresisPct / 100
+	switch s.rule.Comparison {
+	case AlertAbove:
+		if s.armed && price >= s.rule.Threshold {
+			s.armed = false
+			return true
+		}
+		if !s.armed && price < s.rule.Threshold-band {
+			s.armed = true
+		}
+	case AlertBelow:
+		if s.armed && price <= s.rule.Threshold {
+			s.armed = false
+			return true
+		}
+		if !s.armed && price > s.rule.Threshold+band {
+			s.armed = true
+		}
+	}
+	return false
+}
+
+func (a *Alerts) emit(e AlertEvent) {
+	select {
+	case a.events <- e:
+	default:
+	}
+}
@@ -0,0 +1,230 @@
+package jupag
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Amount is a raw token amount backed by a big.Int, so high-supply tokens never overflow.
+// It unmarshals from a JSON string or number and marshals to a JSON string, as the API expects.
+// The zero value is 0. Arithmetic methods return new values and never modify the receiver.
+type Amount struct {
+	v *big.Int
+}
+
+// NewAmount returns an Amount from an uint64.
+func NewAmount(v uint64) Amount {
+	return Amount{v: new(big.Int).SetUint64(v)}
+}
+
+// NewAmountFromBig returns an Amount from a big.Int, the value is copied.
+func NewAmountFromBig(v *big.Int) Amount {
+	if v == nil {
+		return Amount{}
+	}
+	return Amount{v: new(big.Int).Set(v)}
+}
+
+// ParseAmount parses a base 10 integer amount.
+func ParseAmount(s string) (Amount, error) {
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return Amount{}, fmt.Errorf("invalid amount %q", s)
+	}
+	return Amount{v: v}, nil
+}
+
+func (a Amount) big() *big.Int {
+	if a.v == nil {
+		return new(big.Int)
+	}
+	return a.v
+}
+
+// BigInt returns a copy of the amount as a big.Int.
+func (a Amount) BigInt() *big.Int {
+	return new(big.Int).Set(a.big())
+}
+
+// Uint64 returns the amount as an uint64, it reports false when the amount doesn't fit.
+func (a Amount) Uint64() (uint64, bool) {
+	v := a.big()
+	if !v.IsUint64() {
+		return 0, false
+	}
+	return v.Uint64(), true
+}
+
+// MustUint64 returns the amount as an uint64, saturating at math.MaxUint64 and 0.
+func (a Amount) MustUint64() uint64 {
+	v := a.big()
+	if v.Sign() < 0 {
+		return 0
+	}
+	if !v.IsUint64() {
+		return math.MaxUint64
+	}
+	return v.Uint64()
+}
+
+// Float64 returns the nearest float64 value of the amount.
+func (a Amount) Float64() float64 {
+	f, _ := new(big.Float).SetInt(a.big()).Float64()
+	return f
+}
+
+// String returns the base 10 representation of the amount.
+func (a Amount) String() string {
+	return a.big().String()
+}
+
+// IsZero reports whether the amount is 0.
+func (a Amount) IsZero() bool {
+	return a.big().Sign() == 0
+}
+
+// Cmp compares two amounts and returns -1, 0 or +1.
+func (a Amount) Cmp(b Amount) int {
+	return a.big().Cmp(b.big())
+}
+
+// Add returns a + b.
+func (a Amount) Add(b Amount) Amount {
+	return Amount{v: new(big.Int).Add(a.big(), b.big())}
+}
+
+// Sub returns a - b.
+func (a Amount) Sub(b Amount) Amount {
+	return Amount{v: new(big.Int).Sub(a.big(), b.big())}
+}
+
+// Mul returns a * b.
+func (a Amount) Mul(b Amount) Amount {
+	return Amount{v: new(big.Int).Mul(a.big(), b.big())}
+}
+
+// MulDiv returns a * num / den, rounded down. It returns 0 when den is 0.
+func (a Amount) MulDiv(num, den uint64) Amount {
+	if den == 0 {
+		return Amount{}
+	}
+	v := new(big.Int).Mul(a.big(), new(big.Int).SetUint64(num))
+	return Amount{v: v.Quo(v, new(big.Int).SetUint64(den))}
+}
+
+// ApplyBps returns the amount scaled by (10000 - bps) / 10000, e.g. the minimum received for a slippage.
+func (a Amount) ApplyBps(bps uint64) Amount {
+	if bps >= 10000 {
+		return Amount{}
+	}
+	return a.MulDiv(10000-bps, 10000)
+}
+
+// MarshalJSON encodes the amount as a JSON string.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON decodes the amount from a JSON string or number, null leaves it unchanged and "" is 0.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+	s, ok, err := jsonNumberText(data)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		*a = Amount{}
+		return nil
+	}
+
+	v, err := ParseAmount(s)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+// SumAmounts returns the sum of the given amounts.
+func SumAmounts(amounts ...Amount) Amount {
+	total := new(big.Int)
+	for _, a := range amounts {
+		total.Add(total, a.big())
+	}
+	return Amount{v: total}
+}
+
+// ToUIAmount converts a raw amount to UI units, e.g. 1500000000 lamports to 1.5 SOL.
+func ToUIAmount(raw uint64, decimals uint8) float64 {
+	return float64(raw) / math.Pow10(int(decimals))
+}
+
+// FromUIAmount converts an UI amount to a raw amount, e.g. 1.5 SOL to 1500000000 lamports.
+// It returns an error when the amount has more fractional digits than decimals or overflows uint64.
+func FromUIAmount(ui float64, decimals uint8) (uint64, error) {
+	if ui < 0 || math.IsNaN(ui) || math.IsInf(ui, 0) {
+		return 0, fmt.Errorf("invalid ui amount %v", ui)
+	}
+
+	raw, err := ParseUIAmount(strconv.FormatFloat(ui, 'f', -1, 64), decimals)
+	if err != nil {
+		return 0, err
+	}
+	v, ok := raw.Uint64()
+	if !ok {
+		return 0, fmt.Errorf("ui amount %v overflows uint64", ui)
+	}
+	return v, nil
+}
+
+// ParseUIAmount parses a decimal string in UI units into a raw amount without floating point rounding.
+func ParseUIAmount(s string, decimals uint8) (Amount, error) {
+	whole, frac, _ := strings.Cut(strings.TrimSpace(s), ".")
+	if whole == "" {
+		whole = "0"
+	}
+	if strings.HasPrefix(whole, "-") || strings.HasPrefix(whole, "+") {
+		return Amount{}, fmt.Errorf("invalid ui amount %q", s)
+	}
+	if len(frac) > int(decimals) {
+		return Amount{}, fmt.Errorf("ui amount %q has more than %d decimals", s, decimals)
+	}
+	frac += strings.Repeat("0", int(decimals)-len(frac))
+
+	a, err := ParseAmount(whole + frac)
+	if err != nil {
+		return Amount{}, fmt.Errorf("invalid ui amount %q", s)
+	}
+	return a, nil
+}
+
+// FormatUIAmount formats a raw amount in UI units without floating point rounding, e.g. "1.5".
+func FormatUIAmount(raw Amount, decimals uint8) string {
+	s := raw.String()
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	if len(s) <= int(decimals) {
+		s = strings.Repeat("0", int(decimals)-len(s)+1) + s
+	}
+
+	whole, frac := s[:len(s)-int(decimals)], strings.TrimRight(s[len(s)-int(decimals):], "0")
+	if frac != "" {
+		whole += "." + frac
+	}
+	if neg {
+		whole = "-" + whole
+	}
+	return whole
+}
+
+// pow10 returns 10^n.
+func pow10(n uint8) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
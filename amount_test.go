@@ -0,0 +1,76 @@
+package jupag
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAmountUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string // value after decoding onto 7
+		wantErr bool
+	}{
+		{in: `"1000000"`, want: "1000000"},
+		{in: `1000000`, want: "1000000"},
+		{in: `"340282366920938463463374607431768211455"`, want: "340282366920938463463374607431768211455"},
+		{in: `0`, want: "0"},
+		{in: `null`, want: "7"},
+		{in: `""`, want: "0"},
+		{in: `" 42 "`, want: "42"},
+		{in: `" "`, want: "0"},
+		{in: `"1.5"`, wantErr: true},
+		{in: `1e6`, wantErr: true},
+		{in: `"abc"`, wantErr: true},
+		{in: `true`, wantErr: true},
+	}
+	for _, tt := range tests {
+		a := NewAmount(7)
+		err := json.Unmarshal([]byte(tt.in), &a)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Amount %s: error = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err == nil && a.String() != tt.want {
+			t.Errorf("Amount %s = %s, want %s", tt.in, a, tt.want)
+		}
+	}
+
+	b, err := json.Marshal(NewAmount(42))
+	if err != nil || string(b) != `"42"` {
+		t.Errorf(`Marshal(NewAmount(42)) = %s, %v, want "42"`, b, err)
+	}
+}
+
+func TestUIAmount(t *testing.T) {
+	tests := []struct {
+		ui       string
+		decimals uint8
+		raw      string // empty when invalid
+		format   string
+	}{
+		{"1.5", 9, "1500000000", "1.5"},
+		{"0.000001", 6, "1", "0.000001"},
+		{"42", 0, "42", "42"},
+		{".5", 2, "50", "0.5"},
+		{"1.2345", 2, "", ""},
+		{"-1", 6, "", ""},
+		{"1e3", 6, "", ""},
+	}
+	for _, tt := range tests {
+		a, err := ParseUIAmount(tt.ui, tt.decimals)
+		if tt.raw == "" {
+			if err == nil {
+				t.Errorf("ParseUIAmount(%q, %d) = %s, want an error", tt.ui, tt.decimals, a)
+			}
+			continue
+		}
+		if err != nil || a.String() != tt.raw {
+			t.Errorf("ParseUIAmount(%q, %d) = %s, %v, want %s", tt.ui, tt.decimals, a, err, tt.raw)
+			continue
+		}
+		if got := FormatUIAmount(a, tt.decimals); got != tt.format {
+			t.Errorf("FormatUIAmount(%s, %d) = %q, want %q", a, tt.decimals, got, tt.format)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package jupag
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBodySize is the maximum number of bytes of an error response body kept in an APIError.
+const maxErrorBodySize = 4 << 10
+
+// APIError is returned when the API responds with an unexpected status code.
+// Message and Code are set when the body is a JSON error, Body holds the raw body capped at 4 KiB.
+type APIError struct {
+	StatusCode int
+	Message    string
+	Code       string
+	Body       []byte
+	Truncated  bool // the body was larger than the cap
+}
+
+func (e *APIError) Error() string {
+	switch {
+	case e.Message != "" && e.Code != "":
+		return fmt.Sprintf("unexpected status code: %d: %s (%s)", e.StatusCode, e.Message, e.Code)
+	case e.Message != "":
+		return fmt.Sprintf("unexpected status code: %d: %s", e.StatusCode, e.Message)
+	case len(e.Body) > 0:
+		body := strings.TrimSpace(string(e.Body))
+		if e.Truncated {
+			body += "..."
+		}
+		return fmt.Sprintf("unexpected status code: %d: %s", e.StatusCode, body)
+	default:
+		return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+	}
+}
+
+// newAPIError reads the capped body of an unexpected response, the caller still closes the body.
+func newAPIError(resp *http.Response) *APIError {
+	e := &APIError{StatusCode: resp.StatusCode}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize+1))
+	if len(body) > maxErrorBodySize {
+		body, e.Truncated = body[:maxErrorBodySize], true
+	}
+	e.Body = body
+
+	var payload struct {
+		Error     any    `json:"error"`
+		Message   string `json:"message"`
+		ErrorCode string `json:"errorCode"`
+		Code      any    `json:"code"`
+	}
+	if json.Unmarshal(body, &payload) != nil {
+		return e
+	}
+
+	switch v := payload.Error.(type) {
+	case string:
+		e.Message = v
+	case map[string]any:
+		if msg, ok := v["message"].(string); ok {
+			e.Message = msg
+		}
+	}
+	if e.Message == "" {
+		e.Message = payload.Message
+	}
+	e.Code = payload.ErrorCode
+	if e.Code == "" && payload.Code != nil {
+		e.Code = fmt.Sprint(payload.Code)
+	}
+	return e
+}
@@ -0,0 +1,327 @@
+package jupag
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+)
+
+// APIVersion is a generation of the quote and swap endpoints, whose request and response shapes differ.
+type APIVersion string
+
+const (
+	// APIVersionV4 is the legacy quote-proxy API: routes with market infos in a data envelope, swaps of a route.
+	APIVersionV4 APIVersion = "v4"
+	// APIVersionV6 is the v6 swap API and its swap/v1 successor, also served by the self-hosted swap api: a single
+	// quote object with a route plan, swaps of the quote object.
+	APIVersionV6 APIVersion = "v6"
+)
+
+const (
+	defaultAPIURL = "https://api.jup.ag"
+	quoteAPIV4URL = "https://quote-api.jup.ag/v4"
+)
+
+// v6Paths are the paths of the swap/v1 endpoints on the hosted API.
+var v6Paths = map[Endpoint]string{
+	EndpointQuote:            "/swap/v1/quote",
+	EndpointSwap:             "/swap/v1/swap",
+	EndpointSwapInstructions: "/swap/v1/swap-instructions",
+}
+
+// applyAPIVersion sets the default version, v6 when self-hosted and v4 otherwise. An explicit version also
+// points the swap endpoints not configured otherwise to the hosted API of the version.
+func (c *JupagImpl) applyAPIVersion() {
+	if c.version == "" {
+		c.version = APIVersionV4
+		if c.selfHosted {
+			c.version = APIVersionV6
+		}
+		return
+	}
+	if c.selfHosted {
+		return
+	}
+
+	switch c.version {
+	case APIVersionV4:
+		if _, ok := c.baseURLs[APISwap]; !ok && c.apiUrl == defaultAPIURL {
+			c.baseURLs[APISwap] = quoteAPIV4URL
+		}
+	case APIVersionV6:
+		for e, path := range v6Paths {
+			if _, ok := c.paths[e]; !ok {
+				c.paths[e] = path
+			}
+		}
+	}
+}
+
+// quoteParamsV6 are the query parameters of a v6 quote request.
+type quoteParamsV6 struct {
+	InputMint           string `url:"inputMint"`
+	OutputMint          string `url:"outputMint"`
+	Amount              uint64 `url:"amount"`
+	SwapMode            string `url:"swapMode,omitempty"`
+	SlippageBps         uint64 `url:"slippageBps,omitempty"`
+	PlatformFeeBps      uint64 `url:"platformFeeBps,omitempty"`
+	OnlyDirectRoutes    bool   `url:"onlyDirectRoutes,omitempty"`
+	AsLegacyTransaction bool   `url:"asLegacyTransaction,omitempty"`
+	MaxAccounts         uint64 `url:"maxAccounts,omitempty"`
+}
+
+// quoteQuery returns the query parameters of a quote request in the shape of the API version.
+func (c *JupagImpl) quoteQuery(params QuoteParams) any {
+	if c.version != APIVersionV6 {
+		return params
+	}
+	return quoteParamsV6{
+		InputMint:           params.InputMint,
+		OutputMint:          params.OutputMint,
+		Amount:              params.Amount,
+		SwapMode:            params.SwapMode,
+		SlippageBps:         params.SlippageBps,
+		PlatformFeeBps:      params.FeeBps,
+		OnlyDirectRoutes:    params.OnlyDirectRoutes,
+		AsLegacyTransaction: params.AsLegacyTransaction,
+		MaxAccounts:         params.MaxAccounts,
+	}
+}
+
+// quoteV6 is the quote object of the v6 API.
+type quoteV6 struct {
+	InputMint            string         `json:"inputMint"`
+	InAmount             Amount         `json:"inAmount"`
+	OutputMint           string         `json:"outputMint"`
+	OutAmount            Amount         `json:"outAmount"`
+	OtherAmountThreshold Amount         `json:"otherAmountThreshold"`
+	SwapMode             string         `json:"swapMode"`
+	SlippageBps          int64          `json:"slippageBps"`
+	PlatformFee          *platformFeeV6 `json:"platformFee"`
+	PriceImpactPct       Decimal        `json:"priceImpactPct"`
+	RoutePlan            []routeStepV6  `json:"routePlan"`
+	ContextSlot          uint64         `json:"contextSlot"`
+}
+
+type platformFeeV6 struct {
+	Amount Amount `json:"amount"`
+	FeeBps int64  `json:"feeBps"`
+}
+
+type routeStepV6 struct {
+	SwapInfo struct {
+		AmmKey     string `json:"ammKey"`
+		Label      string `json:"label"`
+		InputMint  string `json:"inputMint"`
+		OutputMint string `json:"outputMint"`
+		InAmount   Amount `json:"inAmount"`
+		OutAmount  Amount `json:"outAmount"`
+		FeeAmount  Amount `json:"feeAmount"`
+		FeeMint    string `json:"feeMint"`
+	} `json:"swapInfo"`
+	Percent int `json:"percent"`
+}
+
+// route converts the quote to a Route, keeping the raw quote for the swap request.
+func (q quoteV6) route(raw json.RawMessage) Route {
+	route := Route{
+		InAmount:             q.InAmount,
+		OutAmount:            q.OutAmount,
+		PriceImpactPct:       q.PriceImpactPct.Float64(),
+		Amount:               q.InAmount,
+		SlippageBps:          q.SlippageBps,
+		OtherAmountThreshold: q.OtherAmountThreshold,
+		SwapMode:             q.SwapMode,
+		ContextSlot:          q.ContextSlot,
+		raw:                  raw,
+
+		PriceImpactPctDecimal: q.PriceImpactPct,
+	}
+	if q.SwapMode == SwapModeExactOut {
+		route.Amount = q.OutAmount
+	}
+
+	for _, step := range q.RoutePlan {
+		info := step.SwapInfo
+		market := MarketInfo{
+			ID:         info.AmmKey,
+			Label:      info.Label,
+			InputMint:  info.InputMint,
+			OutputMint: info.OutputMint,
+			InAmount:   info.InAmount,
+			OutAmount:  info.OutAmount,
+		}
+		if !info.FeeAmount.IsZero() {
+			market.LpFee = &Fee{Amount: info.FeeAmount, Mint: info.FeeMint}
+			if !info.InAmount.IsZero() && info.FeeMint == info.InputMint {
+				market.LpFee.PctDecimal = ratDecimal(new(big.Rat).SetFrac(info.FeeAmount.BigInt(), info.InAmount.BigInt()))
+				market.LpFee.Pct = market.LpFee.PctDecimal.Float64()
+			}
+		}
+		route.MarketInfos = append(route.MarketInfos, market)
+	}
+	if q.PlatformFee != nil && len(route.MarketInfos) > 0 && !q.PlatformFee.Amount.IsZero() {
+		last := &route.MarketInfos[len(route.MarketInfos)-1]
+		pct := ratDecimal(big.NewRat(q.PlatformFee.FeeBps, 10000))
+		last.PlatformFee = &Fee{Amount: q.PlatformFee.Amount, Mint: last.OutputMint, Pct: pct.Float64(), PctDecimal: pct}
+	}
+	return route
+}
+
+// newQuoteV6 rebuilds the quote object of a route without raw quote, e.g. decoded from JSON or quoted with v4.
+// Each market is a step of the whole amount, as the markets of a v4 route are its hops.
+func newQuoteV6(r Route) quoteV6 {
+	q := quoteV6{
+		InAmount:             r.InAmount,
+		OutAmount:            r.OutAmount,
+		OtherAmountThreshold: r.OtherAmountThreshold,
+		SwapMode:             r.SwapMode,
+		SlippageBps:          r.SlippageBps,
+		PriceImpactPct:       exactDecimal(r.PriceImpactPctDecimal, r.PriceImpactPct),
+		ContextSlot:          r.ContextSlot,
+		RoutePlan:            make([]routeStepV6, 0, len(r.MarketInfos)),
+	}
+	if q.SwapMode == "" {
+		q.SwapMode = SwapModeExactIn
+	}
+	for _, m := range r.MarketInfos {
+		var step routeStepV6
+		step.Percent = 100
+		step.SwapInfo.AmmKey = m.ID
+		step.SwapInfo.Label = m.Label
+		step.SwapInfo.InputMint = m.InputMint
+		step.SwapInfo.OutputMint = m.OutputMint
+		step.SwapInfo.InAmount = m.InAmount
+		step.SwapInfo.OutAmount = m.OutAmount
+		if m.LpFee != nil {
+			step.SwapInfo.FeeAmount = m.LpFee.Amount
+			step.SwapInfo.FeeMint = m.LpFee.Mint
+		}
+		q.RoutePlan = append(q.RoutePlan, step)
+	}
+	if n := len(r.MarketInfos); n > 0 {
+		q.InputMint = r.MarketInfos[0].InputMint
+		q.OutputMint = r.MarketInfos[n-1].OutputMint
+		if fee := r.MarketInfos[n-1].PlatformFee; fee != nil {
+			bps, _ := new(big.Rat).Mul(exactDecimal(fee.PctDecimal, fee.Pct).Rat(), big.NewRat(10000, 1)).Float64()
+			q.PlatformFee = &platformFeeV6{Amount: fee.Amount, FeeBps: int64(bps + 0.5)}
+		}
+	}
+	return q
+}
+
+// parseQuoteV6 decodes the quote object of a v6 quote response into a single route response.
+func parseQuoteV6(resp *http.Response) (QuoteResponse, error) {
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+
+	var raw json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	var q quoteV6
+	if err := json.Unmarshal(raw, &q); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return QuoteResponse{q.route(raw)}, nil
+}
+
+// swapRequestV6 is the swap request of the v6 API.
+type swapRequestV6 struct {
+	QuoteResponse                 any              `json:"quoteResponse"`
+	UserPublicKey                 string           `json:"userPublicKey"`
+	WrapAndUnwrapSol              *bool            `json:"wrapAndUnwrapSol,omitempty"`
+	FeeAccount                    string           `json:"feeAccount,omitempty"`
+	AsLegacyTransaction           *bool            `json:"asLegacyTransaction,omitempty"`
+	ComputeUnitPriceMicroLamports FeeSetting       `json:"computeUnitPriceMicroLamports,omitempty"`
+	PrioritizationFeeLamports     FeeSetting       `json:"prioritizationFeeLamports,omitempty"`
+	DestinationTokenAccount       string           `json:"destinationTokenAccount,omitempty"`
+	TrackingAccount               string           `json:"trackingAccount,omitempty"`
+	BlockhashSlotsToExpiry        uint64           `json:"blockhashSlotsToExpiry,omitempty"`
+	CorrectLastValidBlockHeight   bool             `json:"correctLastValidBlockHeight,omitempty"`
+	DynamicSlippage               *DynamicSlippage `json:"dynamicSlippage,omitempty"`
+}
+
+// swapPayload returns the body of a swap request in the shape of the API version. The v6 requests send the raw
+// quote of the route back as is, or a quote object rebuilt from the route without one.
+func (c *JupagImpl) swapPayload(params SwapParams) any {
+	if params.TrackingAccount == "" {
+		params.TrackingAccount = c.trackingAccount
+	}
+	if c.version != APIVersionV6 {
+		return c.swapRequestV4(params)
+	}
+	var quote any = params.Route.raw
+	if params.Route.raw == nil {
+		quote = newQuoteV6(params.Route)
+	}
+	return swapRequestV6{
+		QuoteResponse:                 quote,
+		UserPublicKey:                 params.UserPublicKey,
+		WrapAndUnwrapSol:              params.WrapUnwrapSol,
+		FeeAccount:                    params.FeeAccount,
+		AsLegacyTransaction:           params.AsLegacyTransaction,
+		ComputeUnitPriceMicroLamports: params.ComputeUnitPriceMicroLamports,
+		PrioritizationFeeLamports:     params.PrioritizationFeeLamports,
+		DestinationTokenAccount:       params.DestinationTokenAccount,
+		TrackingAccount:               params.TrackingAccount,
+		BlockhashSlotsToExpiry:        params.BlockhashSlotsToExpiry,
+		CorrectLastValidBlockHeight:   params.CorrectLastValidBlockHeight,
+		DynamicSlippage:               params.DynamicSlippage,
+	}
+}
+
+// WrapSolKey is the JSON key of SwapParams.WrapUnwrapSol in the v4 swap requests, the v6 requests always use
+// wrapAndUnwrapSol.
+type WrapSolKey int
+
+const (
+	// WrapSolKeyCompat sends both keys, so the option takes effect on the legacy and the current endpoints.
+	WrapSolKeyCompat WrapSolKey = iota
+	// WrapSolKeyLegacy sends wrapUnwrapSOL only, the key of the legacy quote-api v4.
+	WrapSolKeyLegacy
+	// WrapSolKeyCurrent sends wrapAndUnwrapSol only, the key of the current endpoints.
+	WrapSolKeyCurrent
+)
+
+// swapRequestV4 is the swap request of the v4 API, the swap params with the wrap SOL key of the client.
+// The outer wrap SOL fields shadow the one of the params.
+type swapRequestV4 struct {
+	SwapParams
+	WrapUnwrapSol    *bool `json:"wrapUnwrapSOL,omitempty"`
+	WrapAndUnwrapSol *bool `json:"wrapAndUnwrapSol,omitempty"`
+}
+
+func (c *JupagImpl) swapRequestV4(params SwapParams) swapRequestV4 {
+	req := swapRequestV4{SwapParams: params}
+	if c.wrapSolKey != WrapSolKeyCurrent {
+		req.WrapUnwrapSol = params.WrapUnwrapSol
+	}
+	if c.wrapSolKey != WrapSolKeyLegacy {
+		req.WrapAndUnwrapSol = params.WrapUnwrapSol
+	}
+	return req
+}
+
+// UnmarshalJSON decodes the swap params, the wrap SOL option from either the wrapUnwrapSOL or the
+// wrapAndUnwrapSol key.
+func (p *SwapParams) UnmarshalJSON(data []byte) error {
+	type swapParams SwapParams
+	var v struct {
+		swapParams
+		WrapAndUnwrapSol *bool `json:"wrapAndUnwrapSol"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*p = SwapParams(v.swapParams)
+	if p.WrapUnwrapSol == nil {
+		p.WrapUnwrapSol = v.WrapAndUnwrapSol
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package jupag
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ArbitrageConfig configures ScanArbitrage.
+type ArbitrageConfig struct {
+	BaseAmounts   map[string]uint64 // required; raw amount to cycle per start mint, e.g. 1 SOL and 100 USDC
+	MinProfitBps  float64           // minimum profit of the reported cycles, default: 10
+	Interval      time.Duration     // interval between scans, default: 10s
+	Triangular    bool              // also scans A→B→C→A cycles, besides A→B→A
+	MaxCandidates int               // intermediate mints considered per start mint, in routes map order, default: 10
+	Concurrency   int               // maximum number of concurrent quote requests, default: 8
+}
+
+// ArbitrageOpportunity is a profitable cycle found by ScanArbitrage. Either Err is set, or the cycle.
+type ArbitrageOpportunity struct {
+	Time      time.Time
+	Path      []string // mints of the cycle, starting and ending with the start mint
+	Routes    []Route  // best route of each leg, in order, to build the swaps
+	Quotes    []QuoteResponse
+	InAmount  Amount // raw amount of the start mint
+	OutAmount Amount // raw amount of the start mint received at the end of the cycle
+	ProfitBps float64
+	Err       error // scan error, e.g. the routes map couldn't be fetched
+}
+
+// ScanArbitrage scans the A→B→A cycles, and A→B→C→A ones when cfg.Triangular is set, of the start mints of
+// cfg.BaseAmounts every interval. The cycles are taken from the direct routes map and quoted leg by leg with
+// batched quotes, each leg swapping the out amount of the previous one. The cycles whose profit is at least
+// cfg.MinProfitBps are sent on the returned channel, which is closed when ctx is done.
+// Quotes are not atomic: the opportunity may be gone by the time the swaps land.
+func (c *JupagImpl) ScanArbitrage(ctx context.Context, cfg ArbitrageConfig) <-chan ArbitrageOpportunity {
+	if cfg.MinProfitBps == 0 {
+		cfg.MinProfitBps = 10
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = 10 * time.Second
+	}
+	if cfg.MaxCandidates <= 0 {
+		cfg.MaxCandidates = 10
+	}
+	opportunities := make(chan ArbitrageOpportunity, 16)
+
+	go func() {
+		defer close(opportunities)
+
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			found, err := c.scanArbitrage(ctx, cfg)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				found = []ArbitrageOpportunity{{Time: time.Now(), Err: err}}
+			}
+			for _, o := range found {
+				select {
+				case opportunities <- o:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return opportunities
+}
+
+// arbitrageCycle is a cycle being quoted.
+type arbitrageCycle struct {
+	path   []string
+	quotes []QuoteResponse
+	routes []Route
+	amount uint64 // input amount of the next leg
+	failed bool
+}
+
+// scanArbitrage quotes all the cycles once and returns the profitable ones.
+func (c *JupagImpl) scanArbitrage(ctx context.Context, cfg ArbitrageConfig) ([]ArbitrageOpportunity, error) {
+	if len(cfg.BaseAmounts) == 0 {
+		return nil, errors.New("arbitrage base amounts are required")
+	}
+	routesMap, err := c.routesMap(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var cycles []*arbitrageCycle
+	for base, amount := range cfg.BaseAmounts {
+		candidates := arbitrageCandidates(&routesMap, base, cfg.MaxCandidates)
+		for _, b := range candidates {
+			cycles = append(cycles, &arbitrageCycle{path: []string{base, b, base}, amount: amount})
+			if !cfg.Triangular {
+				continue
+			}
+			for _, m := range candidates {
+				if m != b && routesMap.HasRoute(b, m) {
+					cycles = append(cycles, &arbitrageCycle{path: []string{base, b, m, base}, amount: amount})
+				}
+			}
+		}
+	}
+
+	// quote the legs of all the cycles step by step, sharing identical legs
+	for leg := 0; ; leg++ {
+		var (
+			params []QuoteParams
+			index  = make(map[QuoteParams]int)
+		)
+		for _, cycle := range cycles {
+			if cycle.failed || leg+1 >= len(cycle.path) {
+				continue
+			}
+			p := QuoteParams{InputMint: cycle.path[leg], OutputMint: cycle.path[leg+1], Amount: cycle.amount, SwapMode: SwapModeExactIn}
+			if _, ok := index[p]; !ok {
+				index[p] = len(params)
+				params = append(params, p)
+			}
+		}
+		if len(params) == 0 {
+			break
+		}
+
+		results := c.QuoteAll(ctx, params, QuoteAllOptions{Concurrency: cfg.Concurrency})
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		for _, cycle := range cycles {
+			if cycle.failed || leg+1 >= len(cycle.path) {
+				continue
+			}
+			p := QuoteParams{InputMint: cycle.path[leg], OutputMint: cycle.path[leg+1], Amount: cycle.amount, SwapMode: SwapModeExactIn}
+			result := results[index[p]]
+			route, err := result.Quotes.GetBestRoute()
+			out, ok := route.OutAmount.Uint64()
+			if result.Err != nil || err != nil || !ok || out == 0 {
+				cycle.failed = true
+				continue
+			}
+			cycle.quotes = append(cycle.quotes, result.Quotes)
+			cycle.routes = append(cycle.routes, route)
+			cycle.amount = out
+		}
+	}
+
+	now := time.Now()
+	var found []ArbitrageOpportunity
+	for _, cycle := range cycles {
+		if cycle.failed {
+			continue
+		}
+		in := cycle.routes[0].InAmount
+		out := cycle.routes[len(cycle.routes)-1].OutAmount
+		if in.IsZero() {
+			continue
+		}
+		profit := (out.Float64() - in.Float64()) / in.Float64() * 10000
+		if profit < cfg.MinProfitBps {
+			continue
+		}
+		found = append(found, ArbitrageOpportunity{
+			Time:      now,
+			Path:      cycle.path,
+			Routes:    cycle.routes,
+			Quotes:    cycle.quotes,
+			InAmount:  in,
+			OutAmount: out,
+			ProfitBps: profit,
+		})
+	}
+	return found, nil
+}
+
+// arbitrageCandidates returns up to n mints reachable from base and back with a direct route.
+func arbitrageCandidates(routesMap *IndexedRoutesMap, base string, n int) []string {
+	var candidates []string
+	for _, mint := range routesMap.GetRoutesForMint(base) {
+		if len(candidates) >= n {
+			break
+		}
+		if mint != base && routesMap.HasRoute(mint, base) {
+			candidates = append(candidates, mint)
+		}
+	}
+	return candidates
+}
@@ -0,0 +1,177 @@
+package jupag
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+var ErrNoProgramAddress = errors.New("no valid program address found")
+
+const (
+	maxSeeds      = 16
+	maxSeedLength = 32
+)
+
+// curve25519 field prime and edwards d constant, to check that a program address isn't a valid public key.
+var (
+	curveP = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+	curveD = func() *big.Int {
+		d := new(big.Int).ModInverse(big.NewInt(121666), curveP)
+		d.Mul(d, big.NewInt(-121665))
+		return d.Mod(d, curveP)
+	}()
+)
+
+// CreateProgramAddress derives a program address from seeds, it fails when the address is on the ed25519 curve.
+func CreateProgramAddress(seeds [][]byte, program PublicKey) (PublicKey, error) {
+	if len(seeds) > maxSeeds {
+		return PublicKey{}, fmt.Errorf("too many seeds: %d, max %d", len(seeds), maxSeeds)
+	}
+	h := sha256.New()
+	for _, seed := range seeds {
+		if len(seed) > maxSeedLength {
+			return PublicKey{}, fmt.Errorf("seed of %d bytes is longer than %d", len(seed), maxSeedLength)
+		}
+		h.Write(seed)
+	}
+	h.Write(program[:])
+	h.Write([]byte("ProgramDerivedAddress"))
+
+	var pk PublicKey
+	copy(pk[:], h.Sum(nil))
+	if isOnCurve(pk) {
+		return PublicKey{}, ErrNoProgramAddress
+	}
+	return pk, nil
+}
+
+// FindProgramAddress returns the program address of seeds with the highest bump seed that is off the curve.
+func FindProgramAddress(seeds [][]byte, program PublicKey) (PublicKey, uint8, error) {
+	withBump := append(append([][]byte(nil), seeds...), nil)
+	for bump := 255; bump >= 0; bump-- {
+		withBump[len(seeds)] = []byte{byte(bump)}
+		pk, err := CreateProgramAddress(withBump, program)
+		if err == nil {
+			return pk, uint8(bump), nil
+		}
+		if !errors.Is(err, ErrNoProgramAddress) {
+			return PublicKey{}, 0, err
+		}
+	}
+	return PublicKey{}, 0, ErrNoProgramAddress
+}
+
+// isOnCurve reports whether the key decompresses to an ed25519 point, i.e. (y²-1)/(dy²+1) is a square.
+func isOnCurve(pk PublicKey) bool {
+	le := pk
+	le[31] &= 0x7f
+	for i, j := 0, len(le)-1; i < j; i, j = i+1, j-1 {
+		le[i], le[j] = le[j], le[i]
+	}
+	y := new(big.Int).SetBytes(le[:])
+	y2 := new(big.Int).Mul(y, y)
+	y2.Mod(y2, curveP)
+
+	u := new(big.Int).Sub(y2, big.NewInt(1))
+	v := new(big.Int).Mul(curveD, y2)
+	v.Add(v, big.NewInt(1))
+	x2 := new(big.Int).ModInverse(v.Mod(v, curveP), curveP)
+	x2.Mul(x2, u)
+	x2.Mod(x2, curveP)
+	if x2.Sign() == 0 {
+		return true
+	}
+
+	exp := new(big.Int).Rsh(new(big.Int).Sub(curveP, big.NewInt(1)), 1)
+	return new(big.Int).Exp(x2, exp, curveP).Cmp(big.NewInt(1)) == 0
+}
+
+// DeriveATA returns the associated token account of owner for a mint of the Token program.
+func DeriveATA(owner, mint PublicKey) (PublicKey, error) {
+	return DeriveATAWithProgram(owner, mint, TokenProgramID)
+}
+
+// DeriveATAWithProgram returns the associated token account of owner for a mint of the given token program,
+// e.g. Token2022ProgramID.
+func DeriveATAWithProgram(owner, mint, tokenProgram PublicKey) (PublicKey, error) {
+	ata, _, err := FindProgramAddress([][]byte{owner[:], tokenProgram[:], mint[:]}, AssociatedTokenProgramID)
+	if err != nil {
+		return PublicKey{}, fmt.Errorf("failed to derive associated token account: %w", err)
+	}
+	return ata, nil
+}
+
+// tokenPrograms caches the token program owning each mint.
+type tokenPrograms struct {
+	mu       sync.RWMutex
+	programs map[string]PublicKey
+}
+
+// TokenProgram returns the token program owning a mint, Token or Token-2022, from the RPC node.
+// Results are cached forever since a mint never changes program.
+func (c *JupagImpl) TokenProgram(ctx context.Context, mint string) (PublicKey, error) {
+	if mint == MintSOL {
+		return TokenProgramID, nil
+	}
+	c.tokenPrograms.mu.RLock()
+	program, ok := c.tokenPrograms.programs[mint]
+	c.tokenPrograms.mu.RUnlock()
+	if ok {
+		return program, nil
+	}
+	if c.rpc == nil {
+		return PublicKey{}, ErrNoRPC
+	}
+
+	var info struct {
+		Value *struct {
+			Owner PublicKey `json:"owner"`
+		} `json:"value"`
+	}
+	params := []any{mint, map[string]any{"encoding": "base64", "dataSlice": map[string]int{"offset": 0, "length": 0}}}
+	if err := c.rpc.Call(ctx, "getAccountInfo", params, &info); err != nil {
+		return PublicKey{}, fmt.Errorf("failed to get mint account: %w", err)
+	}
+	if info.Value == nil {
+		return PublicKey{}, fmt.Errorf("mint account %s not found", mint)
+	}
+	if info.Value.Owner != TokenProgramID && info.Value.Owner != Token2022ProgramID {
+		return PublicKey{}, fmt.Errorf("account %s is not a mint, owned by %s", mint, info.Value.Owner)
+	}
+
+	c.tokenPrograms.mu.Lock()
+	if c.tokenPrograms.programs == nil {
+		c.tokenPrograms.programs = make(map[string]PublicKey)
+	}
+	c.tokenPrograms.programs[mint] = info.Value.Owner
+	c.tokenPrograms.mu.Unlock()
+
+	return info.Value.Owner, nil
+}
+
+// AssociatedTokenAccount returns the associated token account of owner for a mint, resolving the token program of
+// the mint with TokenProgram.
+func (c *JupagImpl) AssociatedTokenAccount(ctx context.Context, owner, mint string) (string, error) {
+	ownerKey, err := ParsePublicKey(owner)
+	if err != nil {
+		return "", err
+	}
+	mintKey, err := ParsePublicKey(mint)
+	if err != nil {
+		return "", err
+	}
+	program, err := c.TokenProgram(ctx, mint)
+	if err != nil {
+		return "", err
+	}
+
+	ata, err := DeriveATAWithProgram(ownerKey, mintKey, program)
+	if err != nil {
+		return "", err
+	}
+	return ata.String(), nil
+}
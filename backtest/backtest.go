@@ -0,0 +1,249 @@
+// Package backtest replays quote and price streams recorded with jupag.Recorder through a strategy, reporting
+// the hypothetical fills, fees and PnL. Strategies receive the same types as with the live client.
+//
+//	f, _ := os.Open("quotes.jsonl")
+//	records, _ := backtest.ReadRecords(f)
+//	report := backtest.Run(records, backtest.Config{Balances: balances, Decimals: decimals}, backtest.Strategy{
+//		OnQuote: func(s *backtest.State, params jupag.QuoteParams, quote jupag.QuoteResponse) *jupag.Route {
+//			if quote[0].EffectivePrice() > target {
+//				return &quote[0]
+//			}
+//			return nil
+//		},
+//	})
+package backtest
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	jupag "github.com/ipanardian/go-jup-ag"
+)
+
+// defaultFeeLamports is the network fee of a fill by default, the base fee of a single signature.
+const defaultFeeLamports = 5000
+
+// Config configures a backtest.
+type Config struct {
+	Balances map[string]jupag.Amount // initial raw balances by mint
+	Decimals map[string]uint8        // decimals by mint, required to value the balances
+	// FeeLamports is the network and priority fee charged in SOL on each fill, default: 5000.
+	FeeLamports uint64
+	// SlippageBps is the hypothetical slippage of the fills on the quoted output amount, default: 0.
+	// It is capped at the slippage of the route, as the swap would fail beyond it.
+	SlippageBps uint64
+}
+
+// Strategy are the callbacks of a backtest, all optional. They are called in the order of the records.
+type Strategy struct {
+	// OnQuote returns the route to fill, nil to skip the quote.
+	OnQuote func(s *State, params jupag.QuoteParams, quote jupag.QuoteResponse) *jupag.Route
+	OnPrice func(s *State, prices jupag.PriceMap)
+}
+
+// State is the simulated state of a backtest at the record being replayed.
+type State struct {
+	Time        time.Time
+	ContextSlot uint64
+
+	balances map[string]jupag.Amount
+	prices   map[string]float64
+}
+
+// Balance returns the raw balance of a mint.
+func (s *State) Balance(mint string) jupag.Amount {
+	return s.balances[mint]
+}
+
+// Price returns the latest recorded price of a mint, false when none was recorded yet.
+func (s *State) Price(mint string) (float64, bool) {
+	p, ok := s.prices[mint]
+	return p, ok
+}
+
+// Fill is a hypothetical fill of a route.
+type Fill struct {
+	Time        time.Time
+	ContextSlot uint64
+	InputMint   string
+	OutputMint  string
+	InAmount    jupag.Amount
+	OutAmount   jupag.Amount // output after the hypothetical slippage
+	QuotedOut   jupag.Amount
+	Fees        map[string]jupag.Amount // LP and platform fees of the route, included in the quoted amounts
+	FeeLamports uint64
+}
+
+// Rejection is a route returned by the strategy that couldn't be filled.
+type Rejection struct {
+	Time  time.Time
+	Route jupag.Route
+	Err   error
+}
+
+// Report is the result of a backtest.
+type Report struct {
+	Fills      []Fill
+	Rejections []Rejection
+	Fees       map[string]jupag.Amount // total LP and platform fees by mint, network fees under jupag.MintSOL
+	Balances   map[string]jupag.Amount // final raw balances by mint
+
+	// StartValue and EndValue are the values of the initial and final balances at the latest recorded prices,
+	// in USD unless the prices were recorded in another vs token. PnL is their difference.
+	StartValue float64
+	EndValue   float64
+	PnL        float64
+	Unpriced   []string // mints left out of the values, without recorded price or configured decimals
+}
+
+// ReadRecords reads the records written by jupag.NewJSONLinesSink.
+func ReadRecords(r io.Reader) ([]jupag.Record, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), 64<<20)
+	var records []jupag.Record
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var record jupag.Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("failed to decode record at line %d: %w", line, err)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read records: %w", err)
+	}
+	return records, nil
+}
+
+// Run replays the records in time order through the strategy, filling the returned routes at their quoted amounts
+// less the slippage, against the simulated balances.
+func Run(records []jupag.Record, cfg Config, strategy Strategy) Report {
+	if cfg.FeeLamports == 0 {
+		cfg.FeeLamports = defaultFeeLamports
+	}
+	records = append([]jupag.Record(nil), records...)
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+
+	s := &State{balances: make(map[string]jupag.Amount), prices: make(map[string]float64)}
+	for mint, b := range cfg.Balances {
+		s.balances[mint] = b
+	}
+	report := Report{Fees: make(map[string]jupag.Amount)}
+
+	for _, record := range records {
+		s.Time, s.ContextSlot = record.Time, record.ContextSlot
+		switch record.Kind {
+		case jupag.RecordPrice:
+			for mint, p := range record.Prices {
+				s.prices[mint], _ = strconv.ParseFloat(p.Price, 64)
+			}
+			if strategy.OnPrice != nil {
+				strategy.OnPrice(s, record.Prices)
+			}
+		case jupag.RecordQuote:
+			if strategy.OnQuote == nil || len(record.Quote) == 0 {
+				continue
+			}
+			var params jupag.QuoteParams
+			if record.QuoteParams != nil {
+				params = *record.QuoteParams
+			}
+			route := strategy.OnQuote(s, params, record.Quote)
+			if route == nil {
+				continue
+			}
+			fill, err := s.fill(params, *route, cfg)
+			if err != nil {
+				report.Rejections = append(report.Rejections, Rejection{Time: s.Time, Route: *route, Err: err})
+				continue
+			}
+			report.Fills = append(report.Fills, fill)
+			for mint, fee := range fill.Fees {
+				report.Fees[mint] = report.Fees[mint].Add(fee)
+			}
+			report.Fees[jupag.MintSOL] = report.Fees[jupag.MintSOL].Add(jupag.NewAmount(fill.FeeLamports))
+		}
+	}
+
+	report.Balances = s.balances
+	unpriced := make(map[string]bool)
+	report.StartValue = s.value(cfg.Balances, cfg.Decimals, unpriced)
+	report.EndValue = s.value(s.balances, cfg.Decimals, unpriced)
+	report.PnL = report.EndValue - report.StartValue
+	for mint := range unpriced {
+		report.Unpriced = append(report.Unpriced, mint)
+	}
+	sort.Strings(report.Unpriced)
+	return report
+}
+
+// fill applies a route to the balances.
+func (s *State) fill(params jupag.QuoteParams, route jupag.Route, cfg Config) (Fill, error) {
+	input, output := params.InputMint, params.OutputMint
+	if n := len(route.MarketInfos); n > 0 {
+		input, output = route.MarketInfos[0].InputMint, route.MarketInfos[n-1].OutputMint
+	}
+	if input == "" || output == "" {
+		return Fill{}, errors.New("unknown mints of the route")
+	}
+
+	slippage := cfg.SlippageBps
+	if route.SlippageBps >= 0 && slippage > uint64(route.SlippageBps) {
+		slippage = uint64(route.SlippageBps)
+	}
+	out := route.OutAmount.ApplyBps(slippage)
+
+	if s.Balance(input).Cmp(route.InAmount) < 0 {
+		return Fill{}, fmt.Errorf("insufficient %s balance %s for %s", input, s.Balance(input), route.InAmount)
+	}
+	solLeft := s.Balance(jupag.MintSOL)
+	if input == jupag.MintSOL {
+		solLeft = solLeft.Sub(route.InAmount)
+	}
+	if solLeft.Cmp(jupag.NewAmount(cfg.FeeLamports)) < 0 {
+		return Fill{}, fmt.Errorf("insufficient SOL balance for the network fee of %d lamports", cfg.FeeLamports)
+	}
+
+	s.balances[input] = s.Balance(input).Sub(route.InAmount)
+	s.balances[output] = s.Balance(output).Add(out)
+	s.balances[jupag.MintSOL] = s.Balance(jupag.MintSOL).Sub(jupag.NewAmount(cfg.FeeLamports))
+
+	return Fill{
+		Time:        s.Time,
+		ContextSlot: s.ContextSlot,
+		InputMint:   input,
+		OutputMint:  output,
+		InAmount:    route.InAmount,
+		OutAmount:   out,
+		QuotedOut:   route.OutAmount,
+		Fees:        route.TotalFees(),
+		FeeLamports: cfg.FeeLamports,
+	}, nil
+}
+
+// value returns the value of balances at the latest prices, adding the mints it can't value to unpriced.
+func (s *State) value(balances map[string]jupag.Amount, decimals map[string]uint8, unpriced map[string]bool) float64 {
+	var total float64
+	for mint, b := range balances {
+		if b.IsZero() {
+			continue
+		}
+		price, ok := s.prices[mint]
+		d, known := decimals[mint]
+		if !ok || !known {
+			unpriced[mint] = true
+			continue
+		}
+		total += b.Float64() / math.Pow10(int(d)) * price
+	}
+	return total
+}
@@ -0,0 +1,117 @@
+package jupag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrInsufficientBalance = errors.New("insufficient balance")
+
+const (
+	signatureFeeLamports = 5000
+	maxComputeUnitLimit  = 1_400_000
+)
+
+// InsufficientBalanceError is returned when the wallet can't cover a swap, it matches ErrInsufficientBalance.
+type InsufficientBalanceError struct {
+	Mint      string // MintSOL for the SOL needed by fees and rent
+	Required  Amount // raw amount
+	Available Amount // raw amount
+}
+
+func (e *InsufficientBalanceError) Error() string {
+	return fmt.Sprintf("insufficient balance of %s: required %s, available %s", e.Mint, e.Required, e.Available)
+}
+
+func (e *InsufficientBalanceError) Unwrap() error {
+	return ErrInsufficientBalance
+}
+
+// SOLBalance returns the lamports of a wallet.
+func SOLBalance(ctx context.Context, rpc RPCClient, owner string) (uint64, error) {
+	var result struct {
+		Value uint64 `json:"value"`
+	}
+	if err := rpc.Call(ctx, "getBalance", []any{owner, map[string]any{"commitment": CommitmentConfirmed}}, &result); err != nil {
+		return 0, fmt.Errorf("failed to get balance: %w", err)
+	}
+	return result.Value, nil
+}
+
+// TokenBalance returns the raw amount of a mint held by the token accounts of a wallet.
+func TokenBalance(ctx context.Context, rpc RPCClient, owner, mint string) (Amount, error) {
+	var result struct {
+		Value []struct {
+			Account struct {
+				Data struct {
+					Parsed struct {
+						Info struct {
+							TokenAmount struct {
+								Amount Amount `json:"amount"`
+							} `json:"tokenAmount"`
+						} `json:"info"`
+					} `json:"parsed"`
+				} `json:"data"`
+			} `json:"account"`
+		} `json:"value"`
+	}
+	params := []any{owner, map[string]any{"mint": mint}, map[string]any{"encoding": "jsonParsed", "commitment": CommitmentConfirmed}}
+	if err := rpc.Call(ctx, "getTokenAccountsByOwner", params, &result); err != nil {
+		return Amount{}, fmt.Errorf("failed to get token accounts: %w", err)
+	}
+
+	var total Amount
+	for _, a := range result.Value {
+		total = total.Add(a.Account.Data.Parsed.Info.TokenAmount.Amount)
+	}
+	return total, nil
+}
+
+// checkBalance returns an InsufficientBalanceError when the wallet can't cover the input amount of the route,
+// or the SOL needed for its fees and deposits. Native SOL and wSOL both count for a SOL input.
+// The priority fee is reserved at the maximum compute unit limit, and one signature fee without fee estimate on the route.
+func (c *JupagImpl) checkBalance(ctx context.Context, owner string, route Route, computeUnitPrice int64) error {
+	if len(route.MarketInfos) == 0 {
+		return nil
+	}
+	inputMint := route.MarketInfos[0].InputMint
+
+	lamports, err := SOLBalance(ctx, c.rpc, owner)
+	if err != nil {
+		return err
+	}
+	solAvailable := NewAmount(lamports)
+
+	reserve := NewAmount(signatureFeeLamports)
+	if route.Fees != nil && route.Fees.TotalFeeAndDeposits > 0 {
+		reserve = NewAmount(uint64(route.Fees.TotalFeeAndDeposits))
+	}
+	if computeUnitPrice > 0 {
+		reserve = reserve.Add(NewAmount(uint64(computeUnitPrice)).MulDiv(maxComputeUnitLimit, 1_000_000))
+	}
+
+	if inputMint != MintSOL {
+		tokens, err := TokenBalance(ctx, c.rpc, owner, inputMint)
+		if err != nil {
+			return err
+		}
+		if tokens.Cmp(route.InAmount) < 0 {
+			return &InsufficientBalanceError{Mint: inputMint, Required: route.InAmount, Available: tokens}
+		}
+		if solAvailable.Cmp(reserve) < 0 {
+			return &InsufficientBalanceError{Mint: MintSOL, Required: reserve, Available: solAvailable}
+		}
+		return nil
+	}
+
+	wrapped, err := TokenBalance(ctx, c.rpc, owner, MintSOL)
+	if err != nil {
+		return err
+	}
+	available := solAvailable.Add(wrapped)
+	if required := route.InAmount.Add(reserve); available.Cmp(required) < 0 {
+		return &InsufficientBalanceError{Mint: MintSOL, Required: required, Available: available}
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package jupag
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// BestExecutionRow summarizes executions of a pair within a time bucket.
+// Shortfall is expressed in basis points against the mid price, positive values mean a worse price than mid.
+type BestExecutionRow struct {
+	InputMint         string    `json:"inputMint"`
+	OutputMint        string    `json:"outputMint"`
+	BucketStart       time.Time `json:"bucketStart"`
+	Count             int       `json:"count"`
+	InAmount          uint64    `json:"inAmount"`         // total raw input amount
+	OutAmount         uint64    `json:"outAmount"`        // total raw output amount
+	AvgShortfallBps   float64   `json:"avgShortfallBps"`  // input volume weighted
+	BestShortfallBps  float64   `json:"bestShortfallBps"` // lowest shortfall in the bucket
+	WorstShortfallBps float64   `json:"worstShortfallBps"`
+}
+
+// BestExecutionReport is a best-execution report per pair and time bucket.
+type BestExecutionReport struct {
+	From    time.Time          `json:"from"`
+	To      time.Time          `json:"to"`
+	Bucket  time.Duration      `json:"bucket"`
+	Skipped int                `json:"skipped"` // entries without a mid price
+	Rows    []BestExecutionRow `json:"rows"`
+}
+
+// BestExecutionReporter compares achieved execution prices from a journal against the mid price at execution time.
+type BestExecutionReporter struct {
+	journal Journal
+	bucket  time.Duration
+}
+
+// NewBestExecutionReporter returns a reporter grouping executions into buckets of the given duration.
+func NewBestExecutionReporter(journal Journal, bucket time.Duration) *BestExecutionReporter {
+	if bucket <= 0 {
+		bucket = time.Hour
+	}
+	return &BestExecutionReporter{journal: journal, bucket: bucket}
+}
+
+// Report builds the report for executions recorded in [from, to).
+func (r *BestExecutionReporter) Report(from, to time.Time) (BestExecutionReport, error) {
+	entries, err := r.journal.Entries(from, to)
+	if err != nil {
+		return BestExecutionReport{}, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	type key struct {
+		in, out string
+		bucket  time.Time
+	}
+	type acc struct {
+		row    BestExecutionRow
+		weight float64
+		sum    float64
+	}
+
+	report := BestExecutionReport{From: from, To: to, Bucket: r.bucket}
+	groups := make(map[key]*acc)
+	for _, e := range entries {
+		if e.MidPrice <= 0 || e.InAmount == 0 {
+			report.Skipped++
+			continue
+		}
+		shortfall := (e.MidPrice - e.ExecutionPrice()) / e.MidPrice * 10000
+
+		k := key{e.InputMint, e.OutputMint, e.Time.Truncate(r.bucket)}
+		g, ok := groups[k]
+		if !ok {
+			g = &acc{row: BestExecutionRow{
+				InputMint:         e.InputMint,
+				OutputMint:        e.OutputMint,
+				BucketStart:       k.bucket,
+				BestShortfallBps:  math.Inf(1),
+				WorstShortfallBps: math.Inf(-1),
+			}}
+			groups[k] = g
+		}
+
+		weight := ToUIAmount(e.InAmount, e.InputDecimals)
+		g.row.Count++
+		g.row.InAmount += e.InAmount
+		g.row.OutAmount += e.OutAmount
+		g.row.BestShortfallBps = math.Min(g.row.BestShortfallBps, shortfall)
+		g.row.WorstShortfallBps = math.Max(g.row.WorstShortfallBps, shortfall)
+		g.weight += weight
+		g.sum += shortfall * weight
+	}
+
+	for _, g := range groups {
+		if g.weight > 0 {
+			g.row.AvgShortfallBps = g.sum / g.weight
+		}
+		report.Rows = append(report.Rows, g.row)
+	}
+	sort.Slice(report.Rows, func(i, j int) bool {
+		a, b := report.Rows[i], report.Rows[j]
+		if !a.BucketStart.Equal(b.BucketStart) {
+			return a.BucketStart.Before(b.BucketStart)
+		}
+		if a.InputMint != b.InputMint {
+			return a.InputMint < b.InputMint
+		}
+		return a.OutputMint < b.OutputMint
+	})
+
+	return report, nil
+}
+
+// Run builds a report every interval over the trailing window and passes it to fn, until ctx is done.
+// The interval defaults to the bucket duration when not positive.
+func (r *BestExecutionReporter) Run(ctx context.Context, interval, window time.Duration, fn func(BestExecutionReport, error)) {
+	if interval <= 0 {
+		interval = r.bucket
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			fn(r.Report(now.Add(-window), now))
+		}
+	}
+}
+
+// WriteJSON writes the report as JSON.
+func (r BestExecutionReport) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(r)
+}
+
+// WriteCSV writes the report rows as CSV with a header line.
+func (r BestExecutionReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{
+		"bucket_start", "input_mint", "output_mint", "count", "in_amount", "out_amount",
+		"avg_shortfall_bps", "best_shortfall_bps", "worst_shortfall_bps",
+	}); err != nil {
+		return err
+	}
+
+	for _, row := range r.Rows {
+		if err := cw.Write([]string{
+			row.BucketStart.UTC().Format(time.RFC3339),
+			row.InputMint,
+			row.OutputMint,
+			strconv.Itoa(row.Count),
+			strconv.FormatUint(row.InAmount, 10),
+			strconv.FormatUint(row.OutAmount, 10),
+			strconv.FormatFloat(row.AvgShortfallBps, 'f', 2, 64),
+			strconv.FormatFloat(row.BestShortfallBps, 'f', 2, 64),
+			strconv.FormatFloat(row.WorstShortfallBps, 'f', 2, 64),
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,139 @@
+package jupag
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitBreaker decides whether API requests are sent, requests it rejects fail with ErrCircuitOpen.
+type CircuitBreaker interface {
+	Allow() bool         // reports whether a request may be sent
+	Record(success bool) // records the outcome of a request that was allowed
+}
+
+// CircuitBreakerReleaser is implemented by the circuit breakers releasing the requests cancelled by the caller,
+// which are neither a success nor a failure. The cancelled requests aren't recorded with the other breakers.
+type CircuitBreakerReleaser interface {
+	Release() // releases a request that was allowed without recording an outcome
+}
+
+// CircuitState is the state of a circuit breaker.
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"    // requests are sent
+	CircuitOpen     CircuitState = "open"      // requests fail fast
+	CircuitHalfOpen CircuitState = "half-open" // a limited number of trial requests are sent
+)
+
+// CircuitBreakerConfig configures the default circuit breaker.
+type CircuitBreakerConfig struct {
+	FailureThreshold int           // consecutive failures opening the circuit, default: 5
+	OpenTimeout      time.Duration // time the circuit stays open before trial requests, default: 30s
+	HalfOpenRequests int           // concurrent trial requests while half-open, default: 1
+	OnStateChange    func(from, to CircuitState)
+}
+
+// DefaultCircuitBreaker is a consecutive failures circuit breaker.
+type DefaultCircuitBreaker struct {
+	cfg CircuitBreakerConfig
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	trials   int
+}
+
+// NewCircuitBreaker returns a circuit breaker opening after cfg.FailureThreshold consecutive failures.
+func NewCircuitBreaker(cfg CircuitBreakerConfig) *DefaultCircuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = 30 * time.Second
+	}
+	if cfg.HalfOpenRequests <= 0 {
+		cfg.HalfOpenRequests = 1
+	}
+	return &DefaultCircuitBreaker{cfg: cfg, state: CircuitClosed}
+}
+
+// Allow implements CircuitBreaker.
+func (b *DefaultCircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if time.Since(b.openedAt) < b.cfg.OpenTimeout {
+			return false
+		}
+		b.setState(CircuitHalfOpen)
+		b.trials = 1
+		return true
+	case CircuitHalfOpen:
+		if b.trials >= b.cfg.HalfOpenRequests {
+			return false
+		}
+		b.trials++
+		return true
+	default:
+		return true
+	}
+}
+
+// Record implements CircuitBreaker.
+func (b *DefaultCircuitBreaker) Record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if success {
+		b.failures = 0
+		if b.state != CircuitClosed {
+			b.setState(CircuitClosed)
+		}
+		return
+	}
+
+	b.failures++
+	if b.state == CircuitHalfOpen || (b.state == CircuitClosed && b.failures >= b.cfg.FailureThreshold) {
+		b.openedAt = time.Now()
+		b.setState(CircuitOpen)
+	}
+}
+
+// Release implements CircuitBreakerReleaser, freeing the trial of a cancelled request while half-open.
+func (b *DefaultCircuitBreaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitHalfOpen && b.trials > 0 {
+		b.trials--
+	}
+}
+
+// State returns the current state of the circuit.
+func (b *DefaultCircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= b.cfg.OpenTimeout {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+func (b *DefaultCircuitBreaker) setState(state CircuitState) {
+	from := b.state
+	b.state = state
+	if state != CircuitHalfOpen {
+		b.trials = 0
+	}
+	if b.cfg.OnStateChange != nil && from != state {
+		go b.cfg.OnStateChange(from, state)
+	}
+}
@@ -0,0 +1,95 @@
+package jupag
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// cacheKeyPrefix prefixes the keys written to a shared Cache.
+const cacheKeyPrefix = "jupag:"
+
+// Cache is a key-value store with expiration shared by the price, token list and routes map caches,
+// e.g. a Redis server so several instances share cached data. See WithCache.
+type Cache interface {
+	// Get returns the value of the key, false when it is missing or expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores the value of the key for ttl, a ttl <= 0 never expires.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
+
+type memoryCacheEntry struct {
+	value     []byte
+	expiresAt time.Time // zero when it never expires
+}
+
+// MemoryCache is an in-process Cache.
+type MemoryCache struct {
+	mu      sync.RWMutex
+	entries map[string]memoryCacheEntry
+}
+
+// NewMemoryCache returns an empty in-process cache, expired entries are removed when read or overwritten.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]memoryCacheEntry)}
+}
+
+func (m *MemoryCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.RLock()
+	entry, ok := m.entries[key]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, false, nil
+	}
+	if !entry.expiresAt.IsZero() && !time.Now().Before(entry.expiresAt) {
+		m.mu.Lock()
+		if e, ok := m.entries[key]; ok && e.expiresAt.Equal(entry.expiresAt) {
+			delete(m.entries, key)
+		}
+		m.mu.Unlock()
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+func (m *MemoryCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	entry := memoryCacheEntry{value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+	m.mu.Lock()
+	m.entries[key] = entry
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *MemoryCache) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	delete(m.entries, key)
+	m.mu.Unlock()
+	return nil
+}
+
+// cacheGet decodes the JSON value of the key, a cache error or an undecodable value is a miss.
+func cacheGet[T any](ctx context.Context, cache Cache, key string) (T, bool) {
+	var v T
+	data, ok, err := cache.Get(ctx, cacheKeyPrefix+key)
+	if err != nil || !ok {
+		return v, false
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return v, false
+	}
+	return v, true
+}
+
+// cacheSet stores the JSON value of the key, errors are ignored as the value is still returned to the caller.
+func cacheSet(ctx context.Context, cache Cache, key string, v any, ttl time.Duration) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	_ = cache.Set(ctx, cacheKeyPrefix+key, data, ttl)
+}
@@ -2,12 +2,17 @@ package jupag
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"net"
 	"net/http"
 	"net/url"
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/gojek/heimdall/v7"
@@ -15,180 +20,551 @@ import (
 	"github.com/ipanardian/go-jup-ag/utils"
 )
 
-type Jupag interface {
-	request(method, endpoint string, params, body any) (*http.Response, error)
-	parseResponse(resp *http.Response) (json.RawMessage, error)
+// Quoter quotes swaps.
+type Quoter interface {
 	Quote(params QuoteParams) (QuoteResponse, error)
+	ExchangeRate(params ExchangeRateParams) (Rate, error)
+}
+
+// Swapper builds swap transactions.
+type Swapper interface {
 	Swap(params SwapParams) (string, error)
+	BestSwap(params BestSwapParams) (string, error)
+}
+
+// Pricer returns token prices.
+type Pricer interface {
 	Price(params PriceParams) (PriceMap, error)
+	Prices(ctx context.Context, mints []string) (PriceMap, error)
+}
+
+// RouteMapper returns the routes map.
+type RouteMapper interface {
 	RoutesMap(onlyDirectRoutes bool) (IndexedRoutesMap, error)
-	BestSwap(params BestSwapParams) (string, error)
-	ExchangeRate(params ExchangeRateParams) (Rate, error)
+}
+
+// ContextClient quotes, builds swaps and returns prices with a context, e.g. to serve the client to other processes.
+type ContextClient interface {
+	QuoteWithContext(ctx context.Context, params QuoteParams) (QuoteResponse, error)
+	SwapWithContext(ctx context.Context, params SwapParams) (SwapResponse, error)
+	PriceWithContext(ctx context.Context, params PriceParams) (PriceMap, error)
+}
+
+// Jupag is the core API of the client, kept stable for the mocks and the other implementations. The other
+// capabilities are methods of *JupagImpl, grouped by the small role interfaces where callers need to abstract them.
+type Jupag interface {
+	Quoter
+	Swapper
+	Pricer
+	RouteMapper
 }
 
 type JupagImpl struct {
-	jupagImpl     *httpclient.Client
-	apiUrl        string
-	quotePath     string
-	swapPath      string
-	pricePath     string
-	routesMapPath string
-}
-
-func NewJupag() Jupag {
-	timeout := 3000 * time.Millisecond
-	cl := httpclient.NewClient(
-		httpclient.WithHTTPTimeout(timeout),
-		httpclient.WithRetryCount(1),
-		httpclient.WithRetrier(heimdall.NewRetrier(heimdall.NewConstantBackoff(500*time.Millisecond, 1000*time.Millisecond))),
-	)
+	jupagImpl        *httpclient.Client // client of the default endpoint policy
+	clients          map[EndpointPolicy]*httpclient.Client
+	policies         map[Endpoint]EndpointPolicy
+	retrier          heimdall.Retriable
+	retrierSet       bool
+	coalescer        *coalescer
+	quoteMemo        *quoteMemo
+	scheduler        *scheduler
+	apiKey           string
+	liteFallback     *liteFallback
+	apiUrl           string
+	baseURLs         map[APIFamily]string
+	paths            map[Endpoint]string
+	rpc              RPCClient
+	slippage         *SlippageEngine
+	degradation      *degradation
+	feeEstimator     PriorityFeeEstimator
+	decimals         DecimalsResolver
+	routesCache      *routesMapCache
+	priceCache       *priceCache
+	httpClient       heimdall.Doer
+	logger           *slog.Logger
+	tokenList        tokenList
+	selfHosted       bool
+	version          APIVersion
+	wrapSolKey       WrapSolKey
+	trackingAccount  string
+	failover         *failover
+	breaker          CircuitBreaker
+	limiter          RateLimiter
+	hedgeDelay       time.Duration
+	staleGuard       *staleQuoteGuard
+	strictDecode     bool
+	decimalNumbers   bool
+	driftReport      func(SchemaDrift)
+	tokenPrograms    tokenPrograms
+	platformFee      *platformFee
+	events           *Events
+	recorder         *Recorder
+	tradeStore       TradeStore
+	transferFeeGuard *transferFeeGuard
+	slippageRegistry *SlippageRegistry
+	notionalGuard    *notionalGuard
+	stats            *stats
+	cache            Cache
+	persistentCache  Cache
+	payloadCache     Cache // cache of the routes maps and token list, see WithPersistentCache
+	codec            Codec
+	noCompression    bool
+	proxy            *url.URL
+	dialContext      func(ctx context.Context, network, addr string) (net.Conn, error)
+	wrapTransport    []func(http.RoundTripper) http.RoundTripper
+}
 
-	return &JupagImpl{
-		jupagImpl:     cl,
-		apiUrl:        "https://api.jup.ag",
-		quotePath:     "/quote",
-		swapPath:      "/swap",
-		pricePath:     "/price/v2",
-		routesMapPath: "/indexed-route-map",
+func NewJupag(opts ...Option) *JupagImpl {
+	c := &JupagImpl{
+		apiUrl:   defaultAPIURL,
+		baseURLs: make(map[APIFamily]string),
+		paths:    make(map[Endpoint]string),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.applyAPIVersion()
+	c.buildHTTPClients()
+	if c.decimals == nil {
+		c.decimals = NewTokenDecimalsResolver(c)
+	}
+	if c.failover != nil {
+		c.failover.check = c.healthCheck
 	}
+	if c.priceCache != nil {
+		c.priceCache.shared = c.cache
+	}
+	if c.tradeStore != nil {
+		events := c.storeEvents()
+		if c.events != nil {
+			events = MergeEvents(*c.events, events)
+		}
+		c.events = &events
+	}
+	switch {
+	case c.cache != nil && c.persistentCache != nil:
+		c.payloadCache = tieredCache{c.cache, c.persistentCache}
+	case c.cache != nil:
+		c.payloadCache = c.cache
+	case c.persistentCache != nil:
+		c.payloadCache = c.persistentCache
+	}
+
+	return c
 }
 
-func (c *JupagImpl) request(method, endpoint string, params, payload any) (*http.Response, error) {
+func (c *JupagImpl) request(ctx context.Context, method, endpoint string, params, payload any) (*http.Response, error) {
 	u, err := url.Parse(endpoint)
 	if err != nil {
 		return nil, err
 	}
-	uv, err := utils.StructToUrlValues(params)
-	if err != nil {
-		return nil, fmt.Errorf("failed to convert params to url values: %w", err)
-	}
-
-	u.RawQuery = uv.Encode()
-
-	completeUrl := u.String()
+	if params != nil {
+		uv, err := utils.StructToUrlValues(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert params to url values: %w", err)
+		}
 
-	var (
-		req  *http.Request
-		body io.Reader
-	)
+		u.RawQuery = uv.Encode()
+	}
 
-	if method != "GET" {
-		data, err := json.Marshal(payload)
+	var data []byte
+	if method != http.MethodGet {
+		data, err = c.jsonCodec().Marshal(payload)
 		if payload != nil && err != nil {
 			return nil, err
 		}
-		body = bytes.NewBuffer(data)
 	}
 
-	req, err = http.NewRequest(method, completeUrl, body)
+	if c.degradation != nil && !c.degradation.allow() {
+		return nil, ErrDegraded
+	}
+	// the rate token and the scheduler slot are taken first, a half-open trial allowed by the breaker must end
+	// with a Record
+	if err := c.waitRate(ctx); err != nil {
+		return nil, err
+	}
+	e := c.endpointOf(endpoint)
+	if c.scheduler != nil {
+		if err := c.scheduler.acquire(ctx, priorityOf(ctx, e)); err != nil {
+			return nil, err
+		}
+	}
+	if c.breaker != nil && !c.breaker.Allow() {
+		if c.scheduler != nil {
+			c.scheduler.release()
+		}
+		return nil, ErrCircuitOpen
+	}
+
+	var resp *http.Response
+	start := time.Now()
+	if c.failover != nil {
+		resp, err = c.failover.do(ctx, u.String(), func(completeUrl string) (*http.Response, error) {
+			return c.sendOrFallback(ctx, e, method, completeUrl, data)
+		})
+	} else {
+		resp, err = c.sendOrFallback(ctx, e, method, u.String(), data)
+	}
+	if c.scheduler != nil {
+		c.scheduler.release()
+	}
+	if c.stats != nil {
+		c.stats.recordRequest(e, time.Since(start), resp, err)
+	}
+	if c.degradation != nil {
+		c.degradation.record(resp, err)
+	}
+	if c.breaker != nil {
+		c.recordBreaker(resp, err)
+	}
+	if err == nil {
+		captureBody(ctx, resp)
+	}
+
+	return resp, err
+}
+
+// recordBreaker records the outcome of a request allowed by the breaker, the requests cancelled by the caller
+// are released instead.
+func (c *JupagImpl) recordBreaker(resp *http.Response, err error) {
+	if !errors.Is(err, context.Canceled) {
+		c.breaker.Record(!failedResponse(resp, err))
+		return
+	}
+	if r, ok := c.breaker.(CircuitBreakerReleaser); ok {
+		r.Release()
+	}
+}
+
+// send sends a single request with the http client of the endpoint policy, which retries it on server errors.
+func (c *JupagImpl) send(ctx context.Context, e Endpoint, method, completeUrl string, data []byte) (*http.Response, error) {
+	var body io.Reader
+	if method != http.MethodGet {
+		body = bytes.NewReader(data)
+	}
+
+	var attempts *atomic.Int32
+	if c.logger != nil {
+		ctx, attempts = withAttempts(ctx)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, completeUrl, body)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Cache-Control", "no-cache")
 	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36")
 	req.Header.Set("Referer", "https://jup.ag/")
 	req.Header.Set("sec-ch-ua-platform", "macOS")
+	if lite, _ := ctx.Value(liteRequestKey{}).(bool); c.apiKey != "" && !lite {
+		req.Header.Set(APIKeyHeader, c.apiKey)
+	}
+	// gzip is requested by http.Transport itself, which then decompresses the body beneath the user transports
+	if c.noCompression {
+		req.Header.Set("Accept-Encoding", "identity")
+	}
+	for key, values := range headersFromContext(ctx) {
+		req.Header[key] = values
+	}
 
-	return c.jupagImpl.Do(req)
+	start := time.Now()
+	resp, err := c.httpClientFor(e).Do(req)
+	if err == nil {
+		decompressResponse(resp)
+	}
+	if c.logger != nil {
+		c.logRequest(ctx, req, data, resp, err, time.Since(start), attempts.Load())
+	}
+
+	return resp, err
 }
 
 // parseResponse parses the response body into the given response structure.
 func (c *JupagImpl) parseResponse(resp *http.Response) (json.RawMessage, error) {
+	response, err := c.parseEnvelope(resp)
+	if err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+// parseEnvelope parses the response body into the data envelope, bare bodies are wrapped in one.
+func (c *JupagImpl) parseEnvelope(resp *http.Response) (Response, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return Response{}, newAPIError(resp)
 	}
 
-	var response Response
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to read response: %w", err)
+	}
+	response, err := c.unwrapEnvelope(body)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return response.Data, nil
+	return response, nil
 }
 
 // Quote returns a quote for a given input mint, output mint and amount
 func (c *JupagImpl) Quote(params QuoteParams) (QuoteResponse, error) {
-	resp, err := c.request(http.MethodGet, fmt.Sprintf("%s%s", c.apiUrl, c.quotePath), params, nil)
+	return c.quote(context.Background(), params)
+}
+
+// QuoteWithContext is Quote with a context, e.g. to cancel the request with the caller.
+func (c *JupagImpl) QuoteWithContext(ctx context.Context, params QuoteParams) (QuoteResponse, error) {
+	return c.quote(ctx, params)
+}
+
+func (c *JupagImpl) quote(ctx context.Context, params QuoteParams) (QuoteResponse, error) {
+	c.applyPlatformFee(&params)
+	if c.slippage != nil {
+		c.slippage.Apply(&params)
+	}
+	if err := params.Validate(); err != nil {
+		c.events.quote(ctx, params, nil, err)
+		return nil, err
+	}
+
+	var (
+		quotes QuoteResponse
+		err    error
+	)
+	fetch := func() (QuoteResponse, error) {
+		return c.coalesceQuote(ctx, params, func(ctx context.Context) (QuoteResponse, error) {
+			if c.hedgeDelay <= 0 {
+				return c.fetchQuote(ctx, params)
+			}
+			allow := func() bool { return c.limiter == nil || c.limiter.Allow() }
+			return hedge(ctx, c.hedgeDelay, allow, func(ctx context.Context) (QuoteResponse, error) {
+				return c.fetchQuote(ctx, params)
+			})
+		})
+	}
+	if c.quoteMemo != nil {
+		quotes, err = c.quoteMemo.get(params, fetch)
+	} else {
+		quotes, err = fetch()
+	}
+	c.events.quote(ctx, params, quotes, err)
+	return quotes, err
+}
+
+func (c *JupagImpl) fetchQuote(ctx context.Context, params QuoteParams) (QuoteResponse, error) {
+	resp, err := c.request(ctx, http.MethodGet, c.endpoint(EndpointQuote), c.quoteQuery(params), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make quote request: %w", err)
 	}
 
-	data, err := c.parseResponse(resp)
+	if c.version == APIVersionV6 {
+		quotes, err := parseQuoteV6(resp)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse quote response: %w", err)
+		}
+		c.record(ctx, Record{Kind: RecordQuote, ContextSlot: quotes.ContextSlot(), QuoteParams: &params, Quote: quotes})
+		return quotes, nil
+	}
+
+	response, err := c.parseEnvelope(resp)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse quote response: %w", err)
 	}
 
 	var quotes QuoteResponse
-	if err := json.Unmarshal(data, &quotes); err != nil {
+	if err := c.decodeJSON(response.Data, &quotes); err != nil {
 		return nil, fmt.Errorf("failed to parse quote response: %w", err)
 	}
+	if response.ContextSlot > 0 {
+		for i := range quotes {
+			quotes[i].ContextSlot = uint64(response.ContextSlot)
+		}
+	}
 
 	if len(quotes) == 0 {
 		return nil, fmt.Errorf("no quotes returned")
 	}
 
+	c.record(ctx, Record{Kind: RecordQuote, ContextSlot: quotes.ContextSlot(), QuoteParams: &params, Quote: quotes})
 	return quotes, nil
 }
 
 // Swap returns swap base64 serialized transaction for a route.
 // The caller is responsible for signing the transactions.
 func (c *JupagImpl) Swap(params SwapParams) (string, error) {
-	resp, err := c.request(http.MethodPost, fmt.Sprintf("%s%s", c.apiUrl, c.swapPath), nil, params)
+	response, err := c.swap(context.Background(), params)
+	if err != nil {
+		return "", err
+	}
+
+	return response.SwapTransaction, nil
+}
+
+// SwapWithContext is Swap with a context, returning the whole swap response.
+func (c *JupagImpl) SwapWithContext(ctx context.Context, params SwapParams) (SwapResponse, error) {
+	return c.swap(ctx, params)
+}
+
+func (c *JupagImpl) swap(ctx context.Context, params SwapParams) (SwapResponse, error) {
+	response, err := c.swapTransaction(ctx, &params)
+	c.events.swapBuilt(ctx, params, response, err)
+	return response, err
+}
+
+// swapTransaction checks and completes the swap params, then requests the swap transaction.
+func (c *JupagImpl) swapTransaction(ctx context.Context, params *SwapParams) (SwapResponse, error) {
+	if err := params.Validate(); err != nil {
+		return SwapResponse{}, err
+	}
+	if err := c.CheckQuoteFreshness(ctx, params.Route); err != nil {
+		return SwapResponse{}, err
+	}
+	if err := c.checkTransferFee(ctx, params.Route); err != nil {
+		return SwapResponse{}, err
+	}
+	if err := c.checkNotional(ctx, params.Route); err != nil {
+		return SwapResponse{}, err
+	}
+	if err := applyPriorityFee(ctx, params); err != nil {
+		return SwapResponse{}, err
+	}
+	if err := c.applyFeeAccount(ctx, params); err != nil {
+		return SwapResponse{}, err
+	}
+	resp, err := c.request(ctx, http.MethodPost, c.endpoint(EndpointSwap), nil, c.swapPayload(*params))
 	if err != nil {
-		return "", fmt.Errorf("failed to make swap request: %w", err)
+		return SwapResponse{}, fmt.Errorf("failed to make swap request: %w", err)
 	}
+	defer resp.Body.Close()
+
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return SwapResponse{}, newAPIError(resp)
 	}
 
 	var response SwapResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+	if err := c.decodeData(resp.Body, &response); err != nil {
+		return SwapResponse{}, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return response.SwapTransaction, nil
+	return response, nil
 }
 
 // Price returns simple price for a given input mint, output mint and amount.
 func (c *JupagImpl) Price(params PriceParams) (PriceMap, error) {
-	resp, err := c.request(http.MethodGet, fmt.Sprintf("%s%s", c.apiUrl, c.pricePath), params, nil)
+	return c.price(context.Background(), params)
+}
+
+// PriceWithContext is Price with a context, served by the price cache and coalesced when configured.
+func (c *JupagImpl) PriceWithContext(ctx context.Context, params PriceParams) (PriceMap, error) {
+	return c.price(ctx, params)
+}
+
+func (c *JupagImpl) price(ctx context.Context, params PriceParams) (PriceMap, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	if c.priceCache != nil {
+		return c.priceCache.get(ctx, params, c.livePrice)
+	}
+	return c.coalescePrice(ctx, params, c.livePrice)
+}
+
+// livePrice requests the price API, falling back to stale prices in degraded mode.
+func (c *JupagImpl) livePrice(ctx context.Context, params PriceParams) (PriceMap, error) {
+	price, err := c.fetchPrice(ctx, params)
+	if c.degradation == nil {
+		return price, err
+	}
+	if err != nil {
+		if cached, ok := c.degradation.cachedPrices(params); ok && c.degradation.isDegraded() {
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	c.degradation.rememberPrices(params, price)
+	return price, nil
+}
+
+func (c *JupagImpl) fetchPrice(ctx context.Context, params PriceParams) (PriceMap, error) {
+	resp, err := c.request(ctx, http.MethodGet, c.endpoint(EndpointPrice), params, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make price request: %w", err)
 	}
 
-	data, err := c.parseResponse(resp)
+	response, err := c.parseEnvelope(resp)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse price response: %w", err)
 	}
 
 	var price PriceMap
-	if err := json.Unmarshal(data, &price); err != nil {
+	if err := c.decodeJSON(response.Data, &price); err != nil {
 		return nil, fmt.Errorf("failed to parse price response: %w", err)
 	}
 
+	c.record(ctx, Record{Kind: RecordPrice, ContextSlot: uint64(max(response.ContextSlot, 0)), PriceParams: &params, Prices: price})
 	return price, nil
 }
 
 // RoutesMap returns a hash map, input mint as key and an array of valid output mint as values,
 // token mints are indexed to reduce the file size.
 func (c *JupagImpl) RoutesMap(onlyDirectRoutes bool) (IndexedRoutesMap, error) {
-	resp, err := c.request(http.MethodGet, fmt.Sprintf("%s%s", c.apiUrl, c.routesMapPath), url.Values{
+	return c.routesMap(context.Background(), onlyDirectRoutes)
+}
+
+func (c *JupagImpl) routesMap(ctx context.Context, onlyDirectRoutes bool) (IndexedRoutesMap, error) {
+	if c.routesCache != nil {
+		return c.routesCache.get(ctx, c, onlyDirectRoutes, false)
+	}
+
+	routesMap, _, err := c.fetchRoutesMap(ctx, onlyDirectRoutes, "")
+	if err != nil {
+		return IndexedRoutesMap{}, err
+	}
+
+	return *routesMap, nil
+}
+
+// fetchRoutesMap downloads the routes map, when etag is set the request is conditional
+// and a nil map is returned if the routes map was not modified.
+func (c *JupagImpl) fetchRoutesMap(ctx context.Context, onlyDirectRoutes bool, etag string) (*IndexedRoutesMap, string, error) {
+	if etag != "" {
+		ctx = withHeaders(ctx, http.Header{"If-None-Match": []string{etag}})
+	}
+	resp, err := c.request(ctx, http.MethodGet, c.endpoint(EndpointRoutesMap), url.Values{
 		"onlyDirectRoutes": []string{strconv.FormatBool(onlyDirectRoutes)},
 	}, nil)
 	if err != nil {
-		return IndexedRoutesMap{}, fmt.Errorf("failed to make routes map request: %w", err)
+		return nil, "", fmt.Errorf("failed to make routes map request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if etag != "" && resp.StatusCode == http.StatusNotModified {
+		return nil, etag, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", newAPIError(resp)
 	}
 
 	var routesMap IndexedRoutesMap
-	if err := json.NewDecoder(resp.Body).Decode(&routesMap); err != nil {
-		return IndexedRoutesMap{}, fmt.Errorf("failed to parse routes map response: %w", err)
+	if c.driftReport == nil && !c.strictDecode {
+		routesMap, err = DecodeRoutesMap(resp.Body)
+	} else {
+		err = c.decodeData(resp.Body, &routesMap)
+		routesMap.BuildIndex()
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse routes map response: %w", err)
 	}
 
-	return routesMap, nil
+	return &routesMap, resp.Header.Get("ETag"), nil
 }
 
 // BestSwap returns the ebase64 encoded transaction for the best swap route
@@ -196,39 +572,67 @@ func (c *JupagImpl) RoutesMap(onlyDirectRoutes bool) (IndexedRoutesMap, error) {
 // Default swap mode: ExactOut, so the amount is the amount of output token.
 // Default wrap unwrap sol: true
 func (c *JupagImpl) BestSwap(params BestSwapParams) (string, error) {
+	ctx := context.Background()
+	route, err := c.bestRoute(ctx, params)
+	if err != nil {
+		return "", err
+	}
+
+	swap, err := c.buildSwap(ctx, params, route, "")
+	if err != nil {
+		return "", err
+	}
+
+	return swap.SwapTransaction, nil
+}
+
+// bestRoute quotes the best swap params and returns the best route.
+func (c *JupagImpl) bestRoute(ctx context.Context, params BestSwapParams) (Route, error) {
 	if params.SwapMode == "" {
 		params.SwapMode = SwapModeExactIn
 	}
-	routes, err := c.Quote(QuoteParams{
+	limits, _ := c.slippageLimits(params.InputMint, params.OutputMint)
+	if params.SlippageBps == 0 {
+		params.SlippageBps = limits.SlippageBps
+	}
+	if params.MaxPriceImpactPct == 0 {
+		params.MaxPriceImpactPct = limits.MaxPriceImpactPct
+	}
+	routes, err := c.quote(ctx, QuoteParams{
 		InputMint:        params.InputMint,
 		OutputMint:       params.OutputMint,
 		Amount:           params.Amount,
 		FeeBps:           params.FeeAmount,
 		SwapMode:         params.SwapMode,
+		SlippageBps:      params.SlippageBps,
 		OnlyDirectRoutes: false,
 	})
 	if err != nil {
-		return "", err
+		return Route{}, err
 	}
 
 	route, err := routes.GetBestRoute()
 	if err != nil {
-		return "", err
+		return Route{}, err
 	}
-
-	swap, err := c.Swap(SwapParams{
-		Route:               route,
-		UserPublicKey:       params.UserPublicKey,
-		DestinationWallet:   params.DestinationPublicKey,
-		FeeAccount:          params.FeeAccount,
-		WrapUnwrapSol:       utils.Pointer(true),
-		AsLegacyTransaction: utils.Pointer(true),
-	})
-	if err != nil {
-		return "", err
+	if err := checkPriceImpact(route, params.MaxPriceImpactPct); err != nil {
+		return Route{}, err
 	}
+	return route, nil
+}
 
-	return swap, nil
+// buildSwap builds the swap transaction of the best swap params for the given route.
+func (c *JupagImpl) buildSwap(ctx context.Context, params BestSwapParams, route Route, computeUnitPrice FeeSetting) (SwapResponse, error) {
+	return c.swap(ctx, SwapParams{
+		Route:                         route,
+		UserPublicKey:                 params.UserPublicKey,
+		DestinationWallet:             params.DestinationPublicKey,
+		FeeAccount:                    params.FeeAccount,
+		WrapUnwrapSol:                 utils.Pointer(true),
+		AsLegacyTransaction:           utils.Pointer(true),
+		ComputeUnitPriceMicroLamports: computeUnitPrice,
+		PriorityFeeEstimator:          c.feeEstimator,
+	})
 }
 
 // ExchangeRate returns the exchange rate for a given input mint, output mint and amount.
@@ -238,7 +642,7 @@ func (c *JupagImpl) ExchangeRate(params ExchangeRateParams) (Rate, error) {
 		InputMint:  params.InputMint,
 		OutputMint: params.OutputMint,
 	}
-	routes, err := c.Quote(QuoteParams{
+	routes, err := c.quote(context.Background(), QuoteParams{
 		InputMint:        params.InputMint,
 		OutputMint:       params.OutputMint,
 		Amount:           params.Amount,
@@ -254,17 +658,17 @@ func (c *JupagImpl) ExchangeRate(params ExchangeRateParams) (Rate, error) {
 		return result, err
 	}
 
-	inAmount, err := strconv.ParseInt(route.InAmount, 10, 64)
-	if err != nil {
-		return result, fmt.Errorf("failed to parse in amount: %w", err)
+	inAmount, ok := route.InAmount.Uint64()
+	if !ok {
+		return result, fmt.Errorf("in amount %s overflows uint64", route.InAmount)
 	}
-	outAmount, err := strconv.ParseInt(route.OutAmount, 10, 64)
-	if err != nil {
-		return result, fmt.Errorf("failed to parse out amount: %w", err)
+	outAmount, ok := route.OutAmount.Uint64()
+	if !ok {
+		return result, fmt.Errorf("out amount %s overflows uint64", route.OutAmount)
 	}
 
-	result.InAmount = uint64(inAmount)
-	result.OutAmount = uint64(outAmount)
+	result.InAmount = inAmount
+	result.OutAmount = outAmount
 
 	return result, nil
 }
@@ -0,0 +1,117 @@
+// Command jup-proxy serves the quote, price and swap build endpoints of the Jupiter API on a local HTTP API,
+// so the services of a polyglot stack share one client: its API key, caches, coalescing and rate limit.
+//
+//	JUPAG_API_KEY=... jup-proxy --listen 127.0.0.1:8080 --price-cache 5s --quote-memo 2
+//	curl 'localhost:8080/quote?inputMint=So11111111111111111111111111111111111111112&outputMint=EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v&amount=1000000000'
+//
+// The client is configured by the JUPAG_* environment variables, see jupag.FromEnv, or by --config.
+//
+//	GET  /quote?inputMint=&outputMint=&amount=&swapMode=&slippageBps=&onlyDirectRoutes=&maxAccounts=
+//	GET  /price?ids=&vsToken=
+//	POST /swap                 jupag.SwapParams
+//	POST /swap-instructions    jupag.SwapParams
+//	GET  /health
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	jupag "github.com/ipanardian/go-jup-ag"
+)
+
+func main() {
+	if err := run(); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		fmt.Fprintf(os.Stderr, "jup-proxy: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	fs := flag.NewFlagSet("jup-proxy", flag.ContinueOnError)
+	listen := fs.String("listen", "127.0.0.1:8080", "address to listen on")
+	config := fs.String("config", "", "JSON or YAML client config file, instead of the JUPAG_* environment variables")
+	token := fs.String("token", os.Getenv("JUP_PROXY_TOKEN"), "bearer token required from the callers, default: $JUP_PROXY_TOKEN")
+	priceCache := fs.Duration("price-cache", 5*time.Second, "time prices are cached, 0 to disable")
+	routesCache := fs.Duration("routes-cache", 10*time.Minute, "time routes maps are cached, 0 to disable")
+	quoteMemo := fs.Uint64("quote-memo", 2, "slots identical quotes are reused for, 0 to disable")
+	maxInFlight := fs.Int("max-in-flight", 16, "maximum number of upstream requests in flight, 0 for no limit")
+	clientRate := fs.Float64("client-rate", 10, "requests per second allowed per caller address, 0 for no limit")
+	clientBurst := fs.Int("client-burst", 20, "request burst allowed per caller address")
+	verbose := fs.Bool("v", false, "log the upstream requests")
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		return err
+	}
+
+	var (
+		opts []jupag.Option
+		err  error
+	)
+	if *config != "" {
+		opts, err = jupag.FromConfigFile(*config)
+	} else {
+		opts, err = jupag.FromEnv()
+	}
+	if err != nil {
+		return err
+	}
+
+	level := slog.LevelInfo
+	if *verbose {
+		level = slog.LevelDebug
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+	opts = append(opts, jupag.WithLogger(logger), jupag.WithCoalescing())
+	if *priceCache > 0 {
+		opts = append(opts, jupag.WithPriceCache(*priceCache))
+	}
+	if *routesCache > 0 {
+		opts = append(opts, jupag.WithRoutesMapCache(*routesCache))
+	}
+	if *quoteMemo > 0 {
+		opts = append(opts, jupag.WithQuoteMemo(*quoteMemo))
+	}
+	if *maxInFlight > 0 {
+		opts = append(opts, jupag.WithScheduler(*maxInFlight))
+	}
+
+	s := &server{client: jupag.NewJupag(opts...), token: *token, logger: logger}
+	if *clientRate > 0 {
+		s.limiter = newClientLimiter(*clientRate, max(*clientBurst, 1))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	srv := &http.Server{
+		Addr:              *listen,
+		Handler:           s.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+		// the signal only triggers Shutdown, the requests in flight get its grace period
+		BaseContext: func(_ net.Listener) context.Context { return context.Background() },
+	}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.ListenAndServe() }()
+	logger.Info("jup-proxy listening", slog.String("addr", *listen))
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	jupag "github.com/ipanardian/go-jup-ag"
+	"golang.org/x/time/rate"
+)
+
+// maxBodySize is the maximum size of the swap request bodies.
+const maxBodySize = 1 << 20
+
+type server struct {
+	client  *jupag.JupagImpl
+	token   string
+	limiter *clientLimiter
+	logger  *slog.Logger
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /quote", s.handleQuote)
+	mux.HandleFunc("GET /price", s.handlePrice)
+	mux.HandleFunc("POST /swap", s.handleSwap)
+	mux.HandleFunc("POST /swap-instructions", s.handleSwapInstructions)
+	mux.HandleFunc("GET /health", s.handleHealth)
+	return s.guard(mux)
+}
+
+// guard checks the bearer token and the rate limit of the caller.
+func (s *server) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.token != "" {
+			want := "Bearer " + s.token
+			if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte(want)) != 1 {
+				writeError(w, http.StatusUnauthorized, errors.New("invalid or missing bearer token"))
+				return
+			}
+		}
+		if s.limiter != nil && !s.limiter.allow(remoteHost(r)) {
+			w.Header().Set("Retry-After", "1")
+			writeError(w, http.StatusTooManyRequests, errors.New("rate limit exceeded"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *server) handleQuote(w http.ResponseWriter, r *http.Request) {
+	params, err := parseQuoteParams(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	quote, err := s.client.QuoteWithContext(r.Context(), params)
+	if err != nil {
+		s.writeClientError(w, r, err)
+		return
+	}
+	writeJSON(w, quote)
+}
+
+func (s *server) handlePrice(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	params := jupag.PriceParams{
+		IDs:           query.Get("ids"),
+		VsToken:       query.Get("vsToken"),
+		ShowExtraInfo: query.Get("showExtraInfo") == "true",
+	}
+	if params.IDs == "" {
+		writeError(w, http.StatusBadRequest, errors.New("ids is required"))
+		return
+	}
+	prices, err := s.client.PriceWithContext(r.Context(), params)
+	if err != nil {
+		s.writeClientError(w, r, err)
+		return
+	}
+	writeJSON(w, prices)
+}
+
+func (s *server) handleSwap(w http.ResponseWriter, r *http.Request) {
+	params, ok := readSwapParams(w, r)
+	if !ok {
+		return
+	}
+	swap, err := s.client.SwapWithContext(r.Context(), params)
+	if err != nil {
+		s.writeClientError(w, r, err)
+		return
+	}
+	writeJSON(w, swap)
+}
+
+func (s *server) handleSwapInstructions(w http.ResponseWriter, r *http.Request) {
+	params, ok := readSwapParams(w, r)
+	if !ok {
+		return
+	}
+	instructions, err := s.client.SwapInstructions(r.Context(), params)
+	if err != nil {
+		s.writeClientError(w, r, err)
+		return
+	}
+	writeJSON(w, instructions)
+}
+
+func (s *server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	status, err := s.client.Health(r.Context())
+	if err != nil {
+		s.writeClientError(w, r, err)
+		return
+	}
+	writeJSON(w, status)
+}
+
+func parseQuoteParams(query url.Values) (jupag.QuoteParams, error) {
+	params := jupag.QuoteParams{
+		InputMint:     query.Get("inputMint"),
+		OutputMint:    query.Get("outputMint"),
+		SwapMode:      query.Get("swapMode"),
+		UserPublicKey: query.Get("userPublicKey"),
+	}
+	if params.InputMint == "" || params.OutputMint == "" || query.Get("amount") == "" {
+		return params, errors.New("inputMint, outputMint and amount are required")
+	}
+
+	var err error
+	parseUint := func(name string, v *uint64) {
+		if s := query.Get(name); s != "" && err == nil {
+			if *v, err = strconv.ParseUint(s, 10, 64); err != nil {
+				err = fmt.Errorf("invalid %s %q", name, s)
+			}
+		}
+	}
+	parseBool := func(name string, v *bool) {
+		if s := query.Get(name); s != "" && err == nil {
+			if *v, err = strconv.ParseBool(s); err != nil {
+				err = fmt.Errorf("invalid %s %q", name, s)
+			}
+		}
+	}
+	parseUint("amount", &params.Amount)
+	parseUint("slippageBps", &params.SlippageBps)
+	parseUint("feeBps", &params.FeeBps)
+	parseUint("maxAccounts", &params.MaxAccounts)
+	parseBool("onlyDirectRoutes", &params.OnlyDirectRoutes)
+	parseBool("asLegacyTransaction", &params.AsLegacyTransaction)
+	return params, err
+}
+
+func readSwapParams(w http.ResponseWriter, r *http.Request) (jupag.SwapParams, bool) {
+	var params jupag.SwapParams
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBodySize)).Decode(&params); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid swap params: %w", err))
+		return params, false
+	}
+	if params.UserPublicKey == "" {
+		writeError(w, http.StatusBadRequest, errors.New("userPublicKey is required"))
+		return params, false
+	}
+	return params, true
+}
+
+// writeClientError writes the error of the client, with the status of the Jupiter API error if any.
+func (s *server) writeClientError(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusBadGateway
+	var apiErr *jupag.APIError
+	switch {
+	case errors.Is(err, jupag.ErrInvalidParams):
+		status = http.StatusBadRequest
+	case errors.As(err, &apiErr) && apiErr.StatusCode >= 400 && apiErr.StatusCode < 500:
+		status = apiErr.StatusCode
+	case r.Context().Err() != nil:
+		return
+	}
+	s.logger.LogAttrs(r.Context(), slog.LevelWarn, "jup-proxy request failed",
+		slog.String("path", r.URL.Path),
+		slog.String("error", err.Error()),
+	)
+	writeError(w, status, err)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+}
+
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// clientLimiter rate limits the callers by address, forgetting the idle ones.
+type clientLimiter struct {
+	rps   rate.Limit
+	burst int
+
+	mu      sync.Mutex
+	callers map[string]*caller
+	swept   time.Time
+}
+
+type caller struct {
+	limiter *rate.Limiter
+	seen    time.Time
+}
+
+func newClientLimiter(rps float64, burst int) *clientLimiter {
+	return &clientLimiter{rps: rate.Limit(rps), burst: burst, callers: make(map[string]*caller)}
+}
+
+func (l *clientLimiter) allow(host string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.swept) > time.Minute {
+		for h, c := range l.callers {
+			if now.Sub(c.seen) > time.Minute {
+				delete(l.callers, h)
+			}
+		}
+		l.swept = now
+	}
+	c, ok := l.callers[host]
+	if !ok {
+		c = &caller{limiter: rate.NewLimiter(l.rps, l.burst)}
+		l.callers[host] = c
+	}
+	c.seen = now
+	return c.limiter.AllowN(now, 1)
+}
@@ -0,0 +1,70 @@
+// Command jup is a command line client of the Jupiter API.
+//
+//	jup quote --in SOL --out USDC --amount 1.5 --slippage 50
+//	jup swap --in SOL --out USDC --amount 1.5 --keypair ~/.config/solana/id.json
+//	jup price --watch SOL,JUP,USDC
+//	jup tokens search bonk
+//	jup shield <mint>
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+)
+
+type command struct {
+	usage string
+	run   func(ctx context.Context, args []string) error
+}
+
+var commands = map[string]command{
+	"price":  {"print or watch token prices", runPrice},
+	"quote":  {"quote a swap", runQuote},
+	"shield": {"check the Shield warnings of mints", runShield},
+	"swap":   {"quote, sign, send and confirm a swap with a keypair file", runSwap},
+	"tokens": {"search tokens", runTokens},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "jup: unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := cmd.run(ctx, os.Args[2:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		fmt.Fprintf(os.Stderr, "jup %s: %v\n", os.Args[1], err)
+		stop()
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: jup <command> [flags]")
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name].usage)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	jupag "github.com/ipanardian/go-jup-ag"
+)
+
+const (
+	colorRed   = "\x1b[31m"
+	colorGreen = "\x1b[32m"
+	colorReset = "\x1b[0m"
+)
+
+// priceLine is a JSON line of the price command.
+type priceLine struct {
+	Time      time.Time `json:"time"`
+	Token     string    `json:"token"`
+	Mint      string    `json:"mint"`
+	Price     string    `json:"price"`
+	ChangePct float64   `json:"changePct,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+func runPrice(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("price", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: jup price [flags] SOL,JUP,USDC")
+		fs.PrintDefaults()
+	}
+	watch := fs.Bool("watch", false, "keep refreshing the prices")
+	interval := fs.Duration("interval", 5*time.Second, "refresh interval with --watch")
+	asJSON := fs.Bool("json", false, "print JSON lines instead of text")
+	noColor := fs.Bool("no-color", false, "don't highlight the price changes")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("tokens are required")
+	}
+
+	client := jupag.NewJupag()
+	var (
+		mints  []string
+		tokens = make(map[string]string)
+	)
+	for _, arg := range fs.Args() {
+		for _, token := range strings.Split(arg, ",") {
+			if token = strings.TrimSpace(token); token == "" {
+				continue
+			}
+			mint, err := client.ResolveMint(ctx, token)
+			if err != nil {
+				return err
+			}
+			mints = append(mints, mint)
+			tokens[mint] = token
+		}
+	}
+
+	color := !*noColor && !*asJSON && isTerminal(os.Stdout)
+	if !*watch {
+		prices, err := client.Prices(ctx, mints)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, mint := range mints {
+			if p, ok := prices[mint]; ok {
+				printPrice(jupag.PriceUpdate{Time: now, Mint: mint, Price: p}, tokens, *asJSON, color)
+			}
+		}
+		return nil
+	}
+
+	for update := range client.SubscribePrices(ctx, mints, *interval) {
+		printPrice(update, tokens, *asJSON, color)
+	}
+	return nil
+}
+
+func printPrice(u jupag.PriceUpdate, tokens map[string]string, asJSON, color bool) {
+	if asJSON {
+		line := priceLine{Time: u.Time, Token: tokens[u.Mint], Mint: u.Mint, Price: u.Price.Price, ChangePct: u.ChangePct}
+		if u.Err != nil {
+			line = priceLine{Time: u.Time, Error: u.Err.Error()}
+		}
+		_ = json.NewEncoder(os.Stdout).Encode(line)
+		return
+	}
+
+	if u.Err != nil {
+		fmt.Fprintf(os.Stderr, "%s error: %v\n", u.Time.Format(time.TimeOnly), u.Err)
+		return
+	}
+
+	change := ""
+	if u.Previous != nil {
+		change = fmt.Sprintf("%+.4f%%", u.ChangePct)
+		if color && u.ChangePct > 0 {
+			change = colorGreen + change + colorReset
+		} else if color && u.ChangePct < 0 {
+			change = colorRed + change + colorReset
+		}
+	}
+	fmt.Printf("%s  %-8s %16s  %s\n", u.Time.Format(time.TimeOnly), tokens[u.Mint], u.Price.Price, change)
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	jupag "github.com/ipanardian/go-jup-ag"
+)
+
+// quoteOutput is the JSON output of the quote command.
+type quoteOutput struct {
+	InputMint       string            `json:"inputMint"`
+	OutputMint      string            `json:"outputMint"`
+	InAmount        string            `json:"inAmount"`
+	OutAmount       string            `json:"outAmount"`
+	MinimumReceived string            `json:"minimumReceived"`
+	PriceImpactPct  float64           `json:"priceImpactPct"`
+	Price           float64           `json:"price"` // output token per input token, in UI units
+	Fees            map[string]string `json:"fees"`  // UI amount by mint
+	Plan            []quoteLeg        `json:"plan"`
+	Route           jupag.Route       `json:"route"`
+}
+
+type quoteLeg struct {
+	Label      string `json:"label"`
+	InputMint  string `json:"inputMint"`
+	OutputMint string `json:"outputMint"`
+	InAmount   string `json:"inAmount"`
+	OutAmount  string `json:"outAmount"`
+}
+
+func runQuote(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("quote", flag.ContinueOnError)
+	in := fs.String("in", "", "input token symbol or mint (required)")
+	out := fs.String("out", "", "output token symbol or mint (required)")
+	amount := fs.String("amount", "", "amount in UI units, of the output token with --exact-out (required)")
+	slippage := fs.Uint64("slippage", 50, "slippage tolerance in basis points")
+	exactOut := fs.Bool("exact-out", false, "quote an exact output amount")
+	direct := fs.Bool("direct", false, "only use direct routes")
+	asJSON := fs.Bool("json", false, "print JSON instead of a table")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *in == "" || *out == "" || *amount == "" {
+		fs.Usage()
+		return errors.New("--in, --out and --amount are required")
+	}
+
+	q, err := quote(ctx, jupag.NewJupag(), *in, *out, *amount, *slippage, *exactOut, *direct)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(q)
+	}
+	printQuote(q)
+	return nil
+}
+
+// quote resolves the tokens and amount, quotes and formats the best route in UI units.
+func quote(ctx context.Context, client *jupag.JupagImpl, in, out, amount string, slippageBps uint64, exactOut, direct bool) (quoteOutput, error) {
+	inputMint, err := client.ResolveMint(ctx, in)
+	if err != nil {
+		return quoteOutput{}, err
+	}
+	outputMint, err := client.ResolveMint(ctx, out)
+	if err != nil {
+		return quoteOutput{}, err
+	}
+
+	swapMode, amountMint := jupag.SwapModeExactIn, inputMint
+	if exactOut {
+		swapMode, amountMint = jupag.SwapModeExactOut, outputMint
+	}
+	amountDecimals, err := client.Decimals(ctx, amountMint)
+	if err != nil {
+		return quoteOutput{}, err
+	}
+	raw, err := jupag.ParseUIAmount(amount, amountDecimals)
+	if err != nil {
+		return quoteOutput{}, err
+	}
+	rawAmount, ok := raw.Uint64()
+	if !ok || rawAmount == 0 {
+		return quoteOutput{}, fmt.Errorf("invalid amount %q", amount)
+	}
+
+	quotes, err := client.Quote(jupag.QuoteParams{
+		InputMint:        inputMint,
+		OutputMint:       outputMint,
+		Amount:           rawAmount,
+		SwapMode:         swapMode,
+		SlippageBps:      slippageBps,
+		OnlyDirectRoutes: direct,
+	})
+	if err != nil {
+		return quoteOutput{}, err
+	}
+	route, err := quotes.GetBestRoute()
+	if err != nil {
+		return quoteOutput{}, err
+	}
+
+	return newQuoteOutput(ctx, client, inputMint, outputMint, route, slippageBps)
+}
+
+// newQuoteOutput formats a route in UI units, amounts of mints with unknown decimals are left raw.
+func newQuoteOutput(ctx context.Context, client *jupag.JupagImpl, inputMint, outputMint string, route jupag.Route, slippageBps uint64) (quoteOutput, error) {
+	decimals := func(mint string) (uint8, error) { return client.Decimals(ctx, mint) }
+	inDecimals, err := decimals(inputMint)
+	if err != nil {
+		return quoteOutput{}, err
+	}
+	outDecimals, err := decimals(outputMint)
+	if err != nil {
+		return quoteOutput{}, err
+	}
+
+	result := quoteOutput{
+		InputMint:       inputMint,
+		OutputMint:      outputMint,
+		InAmount:        jupag.FormatUIAmount(route.InAmount, inDecimals),
+		OutAmount:       jupag.FormatUIAmount(route.OutAmount, outDecimals),
+		MinimumReceived: jupag.FormatUIAmount(route.MinimumReceived(slippageBps), outDecimals),
+		PriceImpactPct:  route.PriceImpactPct * 100,
+		Fees:            make(map[string]string),
+		Route:           route,
+	}
+	if !route.InAmount.IsZero() {
+		result.Price = jupag.ToUIAmount(route.OutAmount.MustUint64(), outDecimals) / jupag.ToUIAmount(route.InAmount.MustUint64(), inDecimals)
+	}
+	for mint, fee := range route.TotalFees() {
+		d, err := decimals(mint)
+		if err != nil {
+			result.Fees[mint] = fee.String() + " (raw)"
+			continue
+		}
+		result.Fees[mint] = jupag.FormatUIAmount(fee, d)
+	}
+	for _, m := range route.MarketInfos {
+		leg := quoteLeg{Label: m.Label, InputMint: m.InputMint, OutputMint: m.OutputMint, InAmount: m.InAmount.String(), OutAmount: m.OutAmount.String()}
+		if d, err := decimals(m.InputMint); err == nil {
+			leg.InAmount = jupag.FormatUIAmount(m.InAmount, d)
+		}
+		if d, err := decimals(m.OutputMint); err == nil {
+			leg.OutAmount = jupag.FormatUIAmount(m.OutAmount, d)
+		}
+		result.Plan = append(result.Plan, leg)
+	}
+
+	return result, nil
+}
+
+func printQuote(q quoteOutput) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "In\t%s %s\n", q.InAmount, q.InputMint)
+	fmt.Fprintf(w, "Out\t%s %s\n", q.OutAmount, q.OutputMint)
+	fmt.Fprintf(w, "Minimum received\t%s\n", q.MinimumReceived)
+	fmt.Fprintf(w, "Price\t%.9g\n", q.Price)
+	fmt.Fprintf(w, "Price impact\t%.4f%%\n", q.PriceImpactPct)
+
+	mints := make([]string, 0, len(q.Fees))
+	for mint := range q.Fees {
+		mints = append(mints, mint)
+	}
+	sort.Strings(mints)
+	for i, mint := range mints {
+		label := ""
+		if i == 0 {
+			label = "Fees"
+		}
+		fmt.Fprintf(w, "%s\t%s %s\n", label, q.Fees[mint], mint)
+	}
+	w.Flush()
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tMARKET\tIN\tOUT")
+	for i, leg := range q.Plan {
+		fmt.Fprintf(w, "%d\t%s\t%s %s\t%s %s\n", i+1, leg.Label, leg.InAmount, leg.InputMint, leg.OutAmount, leg.OutputMint)
+	}
+	w.Flush()
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	jupag "github.com/ipanardian/go-jup-ag"
+)
+
+const defaultRPC = "https://api.mainnet-beta.solana.com"
+
+func runSwap(ctx context.Context, args []string) error {
+	home, _ := os.UserHomeDir()
+
+	fs := flag.NewFlagSet("swap", flag.ContinueOnError)
+	in := fs.String("in", "", "input token symbol or mint (required)")
+	out := fs.String("out", "", "output token symbol or mint (required)")
+	amount := fs.String("amount", "", "amount in UI units, of the output token with --exact-out (required)")
+	slippage := fs.Uint64("slippage", 50, "slippage tolerance in basis points")
+	exactOut := fs.Bool("exact-out", false, "swap for an exact output amount")
+	direct := fs.Bool("direct", false, "only use direct routes")
+	maxImpact := fs.Float64("max-impact", 5, "maximum price impact in percent")
+	priorityFee := fs.Int64("priority-fee", 0, "compute unit price in micro lamports")
+	keypair := fs.String("keypair", filepath.Join(home, ".config", "solana", "id.json"), "keypair file signing the swap")
+	rpcURL := fs.String("rpc", envOr("SOLANA_RPC_URL", defaultRPC), "Solana RPC endpoint, $SOLANA_RPC_URL when set")
+	commitment := fs.String("commitment", string(jupag.CommitmentConfirmed), "commitment to wait for: processed, confirmed or finalized")
+	skipPreflight := fs.Bool("skip-preflight", false, "skip the RPC preflight simulation")
+	yes := fs.Bool("yes", false, "send without asking for confirmation")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *in == "" || *out == "" || *amount == "" {
+		fs.Usage()
+		return errors.New("--in, --out and --amount are required")
+	}
+	uiAmount, err := strconv.ParseFloat(*amount, 64)
+	if err != nil || uiAmount <= 0 {
+		return fmt.Errorf("invalid amount %q", *amount)
+	}
+
+	signer, err := jupag.LoadKeypairFile(*keypair)
+	if err != nil {
+		return err
+	}
+	rpc := jupag.NewRPCClient(*rpcURL)
+	client := jupag.NewJupag(jupag.WithRPC(rpc))
+
+	progress("Quoting %s %s -> %s with wallet %s", *amount, *in, *out, signer.PublicKey())
+	intent := client.NewSwap().
+		From(*in).
+		To(*out).
+		AmountUI(uiAmount).
+		Slippage(*slippage).
+		MaxImpact(*maxImpact).
+		Wallet(signer.PublicKey().String())
+	if *exactOut {
+		intent.ExactOut()
+	}
+	if *direct {
+		intent.OnlyDirect()
+	}
+	if *priorityFee > 0 {
+		intent.ComputeUnitPrice(*priorityFee)
+	}
+	prepared, err := intent.Build(ctx)
+	if err != nil {
+		return err
+	}
+
+	q, err := newQuoteOutput(ctx, client, prepared.InputMint, prepared.OutputMint, prepared.Route, *slippage)
+	if err != nil {
+		return err
+	}
+	printQuote(q)
+	fmt.Println()
+
+	if !*yes && !confirm("Send the swap?") {
+		return errors.New("aborted")
+	}
+
+	progress("Signing")
+	tx, err := jupag.DecodeTransaction(prepared.SwapTransaction)
+	if err != nil {
+		return err
+	}
+	if err := tx.Sign(signer); err != nil {
+		return err
+	}
+
+	progress("Sending")
+	signature, err := jupag.SendTransaction(ctx, rpc, tx.Base64(), *skipPreflight)
+	if err != nil {
+		return err
+	}
+	progress("Sent %s", signature)
+
+	stop := spinner(fmt.Sprintf("Waiting for %s commitment", *commitment))
+	result, err := client.WaitForConfirmation(ctx, signature, jupag.Commitment(*commitment), prepared.LastValidBlockHeight)
+	stop()
+	if err != nil {
+		return err
+	}
+
+	switch result.Status {
+	case jupag.ConfirmationExpired:
+		return fmt.Errorf("%w: %s", jupag.ErrTransactionExpired, signature)
+	case jupag.ConfirmationFailed:
+		if result.Reason != nil {
+			return fmt.Errorf("%w: %w", jupag.ErrTransactionFailed, result.Reason)
+		}
+		return fmt.Errorf("%w: %s", jupag.ErrTransactionFailed, result.Err)
+	}
+
+	progress("Confirmed in slot %d: https://solscan.io/tx/%s", result.Slot, signature)
+	return nil
+}
+
+func progress(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "==> "+format+"\n", args...)
+}
+
+// spinner shows an elapsed time indicator on stderr until the returned func is called.
+func spinner(message string) (stop func()) {
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		frames := `|/-\`
+		start := time.Now()
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			fmt.Fprintf(os.Stderr, "\r%c %s %.1fs", frames[i%len(frames)], message, time.Since(start).Seconds())
+			select {
+			case <-done:
+				fmt.Fprintf(os.Stderr, "\r==> %s %.1fs\n", message, time.Since(start).Seconds())
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-finished
+	}
+}
+
+func confirm(question string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
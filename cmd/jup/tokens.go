@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	jupag "github.com/ipanardian/go-jup-ag"
+)
+
+func runTokens(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] != "search" {
+		fmt.Fprintln(os.Stderr, "usage: jup tokens search [--json] <query>")
+		return errors.New("unknown tokens command")
+	}
+
+	fs := flag.NewFlagSet("tokens search", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print JSON instead of a table")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("query is required")
+	}
+
+	tokens, err := jupag.NewJupag().SearchTokens(ctx, strings.Join(fs.Args(), " "))
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(tokens)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SYMBOL\tNAME\tMINT\tDECIMALS\tVERIFIED\tPRICE\tLIQUIDITY\tORGANIC")
+	for _, t := range tokens {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%t\t%.6g\t%.0f\t%.0f %s\n",
+			t.Symbol, t.Name, t.ID, t.Decimals, t.IsVerified, t.USDPrice, t.Liquidity, t.OrganicScore, t.OrganicScoreLabel)
+	}
+	return w.Flush()
+}
+
+func runShield(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("shield", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: jup shield [flags] <mint|symbol>...")
+		fs.PrintDefaults()
+	}
+	asJSON := fs.Bool("json", false, "print JSON instead of text")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("mint is required")
+	}
+
+	client := jupag.NewJupag()
+	mints := make([]string, 0, fs.NArg())
+	for _, arg := range fs.Args() {
+		mint, err := client.ResolveMint(ctx, arg)
+		if err != nil {
+			return err
+		}
+		mints = append(mints, mint)
+	}
+
+	warnings, err := client.Shield(ctx, mints...)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(warnings)
+	}
+
+	for _, mint := range mints {
+		if len(warnings[mint]) == 0 {
+			fmt.Printf("%s: no warnings\n", mint)
+			continue
+		}
+		fmt.Printf("%s:\n", mint)
+		for _, w := range warnings[mint] {
+			fmt.Printf("  [%s] %s: %s\n", strings.ToUpper(w.Severity), w.Type, w.Message)
+		}
+	}
+	return nil
+}
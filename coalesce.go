@@ -0,0 +1,60 @@
+package jupag
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/ipanardian/go-jup-ag/utils"
+)
+
+// coalescer shares the result of identical in-flight quote and price requests, see WithCoalescing.
+type coalescer struct {
+	quotes flightGroup[QuoteResponse]
+	prices flightGroup[PriceMap]
+}
+
+// coalesceQuote fetches the quote once for all the concurrent callers with the same parameters.
+// The call doesn't stop when the first caller is canceled while others are still waiting for it.
+func (c *JupagImpl) coalesceQuote(ctx context.Context, params QuoteParams, fetch func(context.Context) (QuoteResponse, error)) (QuoteResponse, error) {
+	if c.coalescer == nil {
+		return fetch(ctx)
+	}
+	uv, err := utils.StructToUrlValues(params)
+	if err != nil {
+		return fetch(ctx)
+	}
+	quotes, err := c.coalescer.quotes.do(ctx, uv.Encode(), fetch)
+	if err != nil {
+		return nil, err
+	}
+	// each caller gets its own copy of the routes
+	return quotes.clone(), nil
+}
+
+// coalescePrice fetches the prices once for all the concurrent callers with the same ids, in any order, and options.
+func (c *JupagImpl) coalescePrice(ctx context.Context, params PriceParams, fetch func(context.Context, PriceParams) (PriceMap, error)) (PriceMap, error) {
+	if c.coalescer == nil {
+		return fetch(ctx, params)
+	}
+	ids := dedupe(splitIDs(params.IDs))
+	sort.Strings(ids)
+	params.IDs = strings.Join(ids, ",")
+	uv, err := utils.StructToUrlValues(params)
+	if err != nil {
+		return fetch(ctx, params)
+	}
+	prices, err := c.coalescer.prices.do(ctx, uv.Encode(), func(ctx context.Context) (PriceMap, error) {
+		return fetch(ctx, params)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// each caller gets its own map, the prices are copied
+	result := make(PriceMap, len(prices))
+	for id, p := range prices {
+		result[id] = p
+	}
+	return result, nil
+}
@@ -0,0 +1,42 @@
+package jupag
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestCoalescedQuotesAreCopied(t *testing.T) {
+	minOut := NewAmount(1)
+	shared := QuoteResponse{{
+		InAmount:    NewAmount(10),
+		MarketInfos: []MarketInfo{{ID: "m", MinOutAmount: &minOut, LpFee: &Fee{Mint: "a", Pct: 0.1}}},
+		Fees: &struct {
+			SignatureFee             int64   `json:"signatureFee"`
+			OpenOrdersDeposits       []int64 `json:"openOrdersDeposits"`
+			AtaDeposits              []int64 `json:"ataDeposits"`
+			TotalFeeAndDeposits      int64   `json:"totalFeeAndDeposits"`
+			MinimumSolForTransaction int64   `json:"minimumSOLForTransaction"`
+		}{SignatureFee: 5000, AtaDeposits: []int64{2039280}},
+	}}
+	want := shared.clone()
+	if !reflect.DeepEqual(want, shared) {
+		t.Fatalf("clone = %+v, want %+v", want, shared)
+	}
+
+	c := &JupagImpl{coalescer: &coalescer{}}
+	quotes, err := c.coalesceQuote(context.Background(), QuoteParams{InputMint: "a"}, func(context.Context) (QuoteResponse, error) {
+		return shared, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	quotes[0].MarketInfos[0].ID = "changed"
+	quotes[0].MarketInfos[0].LpFee.Pct = 1
+	*quotes[0].MarketInfos[0].MinOutAmount = NewAmount(2)
+	quotes[0].Fees.SignatureFee = 0
+	quotes[0].Fees.AtaDeposits[0] = 0
+	if !reflect.DeepEqual(shared, want) {
+		t.Errorf("shared quotes modified by a caller: %+v", shared)
+	}
+}
@@ -0,0 +1,22 @@
+package jupag
+
+import "encoding/json"
+
+// Codec encodes the request payloads and decodes the API responses, e.g. an adapter of a faster JSON library
+// such as sonic or go-json. It must honor the json struct tags and the json.Marshaler and json.Unmarshaler
+// implementations of the package types. See WithCodec.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// stdCodec is the encoding/json Codec used by default.
+type stdCodec struct{}
+
+func (stdCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (stdCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
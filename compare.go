@@ -0,0 +1,152 @@
+package jupag
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ScoreFunc scores a route, the highest score wins.
+type ScoreFunc func(Route) float64
+
+// ScoreByOutAmount prefers the highest out amount, or the lowest in amount for ExactOut routes.
+func ScoreByOutAmount(r Route) float64 {
+	if r.SwapMode == SwapModeExactOut {
+		return -r.InAmount.Float64()
+	}
+	return r.OutAmount.Float64()
+}
+
+// ScoreByPriceImpact prefers the lowest price impact.
+func ScoreByPriceImpact(r Route) float64 {
+	return -r.PriceImpactPct
+}
+
+// ScoreByHops prefers the routes with the fewest markets.
+func ScoreByHops(r Route) float64 {
+	return -float64(len(r.MarketInfos))
+}
+
+// ScoreByFees prefers the lowest total fee percentage of the route legs.
+func ScoreByFees(r Route) float64 {
+	return -routeFeePct(r)
+}
+
+// ScoreWeighted sums the scores of funcs multiplied by the weight at the same index, missing weights are 1.
+func ScoreWeighted(funcs []ScoreFunc, weights []float64) ScoreFunc {
+	return func(r Route) float64 {
+		var score float64
+		for i, f := range funcs {
+			w := 1.0
+			if i < len(weights) {
+				w = weights[i]
+			}
+			score += w * f(r)
+		}
+		return score
+	}
+}
+
+// RouteScore is a scored route of a quote.
+type RouteScore struct {
+	QuoteIndex int // index of the quote in the compared quotes
+	RouteIndex int // index of the route in the quote
+	Route      Route
+	Score      float64
+}
+
+// QuoteComparison is the result of CompareQuotes.
+type QuoteComparison struct {
+	Best       RouteScore
+	Candidates []RouteScore // all the routes, sorted by score, highest first
+}
+
+// CompareQuotes scores all the routes of the given quotes and returns them ranked, score defaults to ScoreByOutAmount.
+// Ties keep the order of the quotes.
+func CompareQuotes(quotes []QuoteResponse, score ScoreFunc) (QuoteComparison, error) {
+	if score == nil {
+		score = ScoreByOutAmount
+	}
+
+	var candidates []RouteScore
+	for qi, q := range quotes {
+		for ri, r := range q {
+			candidates = append(candidates, RouteScore{QuoteIndex: qi, RouteIndex: ri, Route: r, Score: score(r)})
+		}
+	}
+	if len(candidates) == 0 {
+		return QuoteComparison{}, errors.New("no route found")
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	return QuoteComparison{Best: candidates[0], Candidates: candidates}, nil
+}
+
+// String explains the choice for logs, comparing the best route to the runner-up.
+func (c QuoteComparison) String() string {
+	if len(c.Candidates) == 0 {
+		return "no route"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "chose quote %d route %d (score %.6g): %s", c.Best.QuoteIndex, c.Best.RouteIndex, c.Best.Score, describeRoute(c.Best.Route))
+	if len(c.Candidates) == 1 {
+		return b.String()
+	}
+
+	next := c.Candidates[1]
+	fmt.Fprintf(&b, "; runner-up quote %d route %d (score %.6g): %s", next.QuoteIndex, next.RouteIndex, next.Score, describeRoute(next.Route))
+	var diffs []string
+	if c.Best.Route.OutAmount.Cmp(next.Route.OutAmount) != 0 {
+		diffs = append(diffs, "out "+signedAmount(c.Best.Route.OutAmount.Sub(next.Route.OutAmount)))
+	}
+	if c.Best.Route.InAmount.Cmp(next.Route.InAmount) != 0 {
+		diffs = append(diffs, "in "+signedAmount(c.Best.Route.InAmount.Sub(next.Route.InAmount)))
+	}
+	if d := c.Best.Route.PriceImpactPct - next.Route.PriceImpactPct; d != 0 {
+		diffs = append(diffs, fmt.Sprintf("impact %+.4f%%", d*100))
+	}
+	if d := len(c.Best.Route.MarketInfos) - len(next.Route.MarketInfos); d != 0 {
+		diffs = append(diffs, fmt.Sprintf("hops %+d", d))
+	}
+	if d := routeFeePct(c.Best.Route) - routeFeePct(next.Route); d != 0 {
+		diffs = append(diffs, fmt.Sprintf("fees %+.4f%%", d*100))
+	}
+	if len(diffs) > 0 {
+		fmt.Fprintf(&b, "; diff: %s", strings.Join(diffs, ", "))
+	}
+	return b.String()
+}
+
+func describeRoute(r Route) string {
+	labels := make([]string, len(r.MarketInfos))
+	for i, m := range r.MarketInfos {
+		labels[i] = m.Label
+	}
+	return fmt.Sprintf("in %s out %s impact %.4f%% fees %.4f%% via [%s]",
+		r.InAmount, r.OutAmount, r.PriceImpactPct*100, routeFeePct(r)*100, strings.Join(labels, " > "))
+}
+
+func signedAmount(a Amount) string {
+	if a.BigInt().Sign() > 0 {
+		return "+" + a.String()
+	}
+	return a.String()
+}
+
+// routeFeePct sums the LP and platform fee percentages of the route legs.
+func routeFeePct(r Route) float64 {
+	var pct float64
+	for _, m := range r.MarketInfos {
+		if m.LpFee != nil {
+			pct += m.LpFee.Pct
+		}
+		if m.PlatformFee != nil {
+			pct += m.PlatformFee.Pct
+		}
+	}
+	return pct
+}
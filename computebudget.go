@@ -0,0 +1,88 @@
+package jupag
+
+import (
+	"crypto/ed25519"
+	"encoding/binary"
+	"math"
+)
+
+// SetComputeUnitPriceInstruction returns the instruction setting the compute unit price, in micro lamports.
+func SetComputeUnitPriceInstruction(microLamports uint64) Instruction {
+	return Instruction{
+		ProgramID: ComputeBudgetProgramID,
+		Data:      binary.LittleEndian.AppendUint64([]byte{computeBudgetSetComputeUnitPrice}, microLamports),
+	}
+}
+
+// SetComputeUnitLimitInstruction returns the instruction setting the compute unit limit.
+func SetComputeUnitLimitInstruction(units uint32) Instruction {
+	return Instruction{
+		ProgramID: ComputeBudgetProgramID,
+		Data:      binary.LittleEndian.AppendUint32([]byte{computeBudgetSetComputeUnitLimit}, units),
+	}
+}
+
+// SetComputeUnitPrice rewrites the compute unit price of an already built transaction, or inserts the instruction
+// when absent, e.g. to bump the priority fee on retry. The signatures are cleared, the transaction must be signed again.
+func (tx *Transaction) SetComputeUnitPrice(microLamports uint64) error {
+	return tx.setComputeBudget(SetComputeUnitPriceInstruction(microLamports).Data)
+}
+
+// SetComputeUnitLimit rewrites the compute unit limit of an already built transaction, or inserts the instruction
+// when absent. The signatures are cleared, the transaction must be signed again.
+func (tx *Transaction) SetComputeUnitLimit(units uint32) error {
+	return tx.setComputeBudget(SetComputeUnitLimitInstruction(units).Data)
+}
+
+func (tx *Transaction) setComputeBudget(data []byte) error {
+	if err := tx.Message.setComputeBudget(data); err != nil {
+		return err
+	}
+	for i := range tx.Signatures {
+		tx.Signatures[i] = make([]byte, ed25519.SignatureSize)
+	}
+	return nil
+}
+
+// setComputeBudget replaces the compute budget instruction with the same discriminator as data, or prepends it.
+func (m *Message) setComputeBudget(data []byte) error {
+	program := -1
+	for i, key := range m.AccountKeys {
+		if key == ComputeBudgetProgramID {
+			program = i
+			break
+		}
+	}
+
+	if program >= 0 {
+		for i, ix := range m.Instructions {
+			if int(ix.ProgramIDIndex) == program && len(ix.Data) > 0 && ix.Data[0] == data[0] {
+				m.Instructions[i].Data = data
+				return nil
+			}
+		}
+	} else {
+		// append the program as a read-only unsigned static key, shifting the indexes of the lookup table accounts
+		accounts := len(m.AccountKeys)
+		for _, lookup := range m.AddressTableLookups {
+			accounts += len(lookup.WritableIndexes) + len(lookup.ReadonlyIndexes)
+		}
+		if accounts > math.MaxUint8 {
+			return errTooManyAccounts
+		}
+		program = len(m.AccountKeys)
+		m.AccountKeys = append(m.AccountKeys, ComputeBudgetProgramID)
+		m.Header.NumReadonlyUnsignedAccounts++
+		for i := range m.Instructions {
+			for j, a := range m.Instructions[i].Accounts {
+				if int(a) >= program {
+					m.Instructions[i].Accounts[j]++
+				}
+			}
+		}
+	}
+
+	ix := CompiledInstruction{ProgramIDIndex: uint8(program), Data: data}
+	m.Instructions = append([]CompiledInstruction{ix}, m.Instructions...)
+	return nil
+}
@@ -0,0 +1,192 @@
+package jupag
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Duration is a time.Duration read from a string such as "1.5s" in the configuration files.
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Config is the deployment configuration of a client, see FromEnv and FromConfigFile. Zero values keep the defaults.
+type Config struct {
+	BaseURL     string                `json:"baseUrl" yaml:"baseUrl"`
+	APIKey      string                `json:"apiKey" yaml:"apiKey"`
+	Timeout     Duration              `json:"timeout" yaml:"timeout"`     // timeout of all the endpoints without their own
+	Timeouts    map[Endpoint]Duration `json:"timeouts" yaml:"timeouts"`   // timeouts by endpoint, e.g. quote: 1s
+	RateLimit   float64               `json:"rateLimit" yaml:"rateLimit"` // requests per second
+	RateBurst   int                   `json:"rateBurst" yaml:"rateBurst"` // default: 1
+	SlippageBps uint64                `json:"slippageBps" yaml:"slippageBps"`
+	APIVersion  APIVersion            `json:"apiVersion" yaml:"apiVersion"` // v4 or v6
+}
+
+// Options returns the options applying the configuration, the retries of the endpoint policies are kept.
+func (cfg Config) Options() []Option {
+	var opts []Option
+	if cfg.BaseURL != "" {
+		opts = append(opts, WithBaseURL(cfg.BaseURL))
+	}
+	if cfg.APIKey != "" {
+		opts = append(opts, WithAPIKey(cfg.APIKey))
+	}
+	if cfg.APIVersion != "" {
+		opts = append(opts, WithAPIVersion(cfg.APIVersion))
+	}
+	if cfg.Timeout > 0 || len(cfg.Timeouts) > 0 {
+		for e := range defaultEndpoints {
+			timeout, ok := cfg.Timeouts[e]
+			if !ok {
+				timeout = cfg.Timeout
+			}
+			if timeout <= 0 {
+				continue
+			}
+			policy, ok := defaultEndpointPolicies[e]
+			if !ok {
+				policy = defaultEndpointPolicy
+			}
+			policy.Timeout = time.Duration(timeout)
+			opts = append(opts, WithEndpointPolicy(e, policy))
+		}
+	}
+	if cfg.RateLimit > 0 {
+		opts = append(opts, WithRateLimiter(NewRateLimiter(cfg.RateLimit, max(cfg.RateBurst, 1))))
+	}
+	if cfg.SlippageBps > 0 {
+		opts = append(opts, WithSlippageRegistry(NewSlippageRegistry().SetDefault(SlippageLimits{SlippageBps: cfg.SlippageBps})))
+	}
+	return opts
+}
+
+// FromEnv returns the options configured by the environment variables JUPAG_BASE_URL, JUPAG_API_KEY,
+// JUPAG_TIMEOUT, JUPAG_TIMEOUT_<ENDPOINT> such as JUPAG_TIMEOUT_QUOTE or JUPAG_TIMEOUT_ROUTES_MAP,
+// JUPAG_RATE_LIMIT, JUPAG_RATE_BURST, JUPAG_SLIPPAGE_BPS and JUPAG_API_VERSION.
+//
+//	opts, err := jupag.FromEnv()
+//	client := jupag.NewJupag(opts...)
+func FromEnv() ([]Option, error) {
+	cfg, err := configFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Options(), nil
+}
+
+func configFromEnv() (Config, error) {
+	cfg := Config{
+		BaseURL:    os.Getenv("JUPAG_BASE_URL"),
+		APIKey:     os.Getenv("JUPAG_API_KEY"),
+		APIVersion: APIVersion(os.Getenv("JUPAG_API_VERSION")),
+	}
+
+	var err error
+	parse := func(name string, fn func(string) error) {
+		if v := os.Getenv(name); v != "" && err == nil {
+			if e := fn(v); e != nil {
+				err = fmt.Errorf("invalid %s %q: %w", name, v, e)
+			}
+		}
+	}
+	parse("JUPAG_TIMEOUT", func(v string) error { return cfg.Timeout.UnmarshalText([]byte(v)) })
+	for e := range defaultEndpoints {
+		parse("JUPAG_TIMEOUT_"+envName(string(e)), func(v string) error {
+			var d Duration
+			if err := d.UnmarshalText([]byte(v)); err != nil {
+				return err
+			}
+			if cfg.Timeouts == nil {
+				cfg.Timeouts = make(map[Endpoint]Duration)
+			}
+			cfg.Timeouts[e] = d
+			return nil
+		})
+	}
+	parse("JUPAG_RATE_LIMIT", func(v string) (err error) {
+		cfg.RateLimit, err = strconv.ParseFloat(v, 64)
+		return err
+	})
+	parse("JUPAG_RATE_BURST", func(v string) (err error) {
+		cfg.RateBurst, err = strconv.Atoi(v)
+		return err
+	})
+	parse("JUPAG_SLIPPAGE_BPS", func(v string) (err error) {
+		cfg.SlippageBps, err = strconv.ParseUint(v, 10, 64)
+		return err
+	})
+	if err == nil {
+		err = cfg.validate()
+	}
+	return cfg, err
+}
+
+func (cfg Config) validate() error {
+	switch cfg.APIVersion {
+	case "", APIVersionV4, APIVersionV6:
+		return nil
+	}
+	return fmt.Errorf("unsupported api version %q", cfg.APIVersion)
+}
+
+// envName converts a camel case name to upper snake case, e.g. routesMap to ROUTES_MAP.
+func envName(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) && i > 0 {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
+
+// FromConfigFile returns the options configured by a JSON or YAML file, by extension, holding a Config.
+// Unknown keys are rejected to catch typos.
+func FromConfigFile(path string) ([]Option, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var cfg Config
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&cfg)
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&cfg)
+	default:
+		return nil, fmt.Errorf("unsupported config file extension %q", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg.Options(), nil
+}
@@ -0,0 +1,166 @@
+package jupag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrNoRPC = errors.New("rpc client is not configured, use WithRPC")
+
+// Commitment is the level of finality requested from the RPC node.
+type Commitment string
+
+const (
+	CommitmentProcessed Commitment = "processed"
+	CommitmentConfirmed Commitment = "confirmed"
+	CommitmentFinalized Commitment = "finalized"
+)
+
+// ConfirmationStatus is the outcome of waiting for a transaction.
+type ConfirmationStatus string
+
+const (
+	ConfirmationConfirmed ConfirmationStatus = "confirmed" // landed with the requested commitment
+	ConfirmationExpired   ConfirmationStatus = "expired"   // the blockhash expired before the transaction landed
+	ConfirmationFailed    ConfirmationStatus = "failed"    // landed but failed on-chain
+)
+
+var confirmationPollInterval = 500 * time.Millisecond
+
+// ConfirmationResult is the result of WaitForConfirmation.
+type ConfirmationResult struct {
+	Signature string
+	Status    ConfirmationStatus
+	Slot      uint64
+	Err       json.RawMessage // on-chain transaction error when Status is ConfirmationFailed
+	Reason    error           // decoded on-chain error, nil if not recognized
+}
+
+type signatureStatus struct {
+	Slot               uint64          `json:"slot"`
+	Confirmations      *uint64         `json:"confirmations"`
+	Err                json.RawMessage `json:"err"`
+	ConfirmationStatus string          `json:"confirmationStatus"`
+}
+
+// WaitForConfirmation polls the signature status until the transaction reaches the given commitment,
+// fails on-chain, or the block height passes lastValidBlockHeight without the transaction being seen.
+// A transaction seen below the commitment when the block height passes is still waited on, it may not be resent.
+// A lastValidBlockHeight of 0 disables expiry detection, waiting until ctx is done.
+func (c *JupagImpl) WaitForConfirmation(ctx context.Context, signature string, commitment Commitment, lastValidBlockHeight uint64) (ConfirmationResult, error) {
+	if c.rpc == nil {
+		return ConfirmationResult{}, ErrNoRPC
+	}
+	return waitForConfirmation(ctx, c.rpc, signature, commitment, lastValidBlockHeight)
+}
+
+func waitForConfirmation(ctx context.Context, rpc RPCClient, signature string, commitment Commitment, lastValidBlockHeight uint64) (ConfirmationResult, error) {
+	return waitForAnyConfirmation(ctx, rpc, []string{signature}, commitment, lastValidBlockHeight)
+}
+
+// waitForAnyConfirmation waits until one of the signatures, e.g. the resubmissions of a transaction, lands or fails.
+// The result is the one of the first signature when none landed.
+func waitForAnyConfirmation(ctx context.Context, rpc RPCClient, signatures []string, commitment Commitment, lastValidBlockHeight uint64) (ConfirmationResult, error) {
+	if commitment == "" {
+		commitment = CommitmentConfirmed
+	}
+	result := ConfirmationResult{Signature: signatures[0]}
+
+	ticker := time.NewTicker(confirmationPollInterval)
+	defer ticker.Stop()
+
+	for {
+		statuses, err := getSignatureStatuses(ctx, rpc, signatures)
+		if err != nil {
+			return result, err
+		}
+		if landed, ok := landedResult(signatures, statuses, commitment); ok {
+			return landed, nil
+		}
+
+		if lastValidBlockHeight > 0 {
+			var height uint64
+			if err := rpc.Call(ctx, "getBlockHeight", []any{map[string]any{"commitment": CommitmentConfirmed}}, &height); err != nil {
+				return result, fmt.Errorf("failed to get block height: %w", err)
+			}
+			if height > lastValidBlockHeight {
+				// a transaction may have landed since the status check, or be below the commitment:
+				// it is only expired when none of the signatures is known
+				statuses, err := getSignatureStatuses(ctx, rpc, signatures)
+				if err != nil {
+					return result, err
+				}
+				if landed, ok := landedResult(signatures, statuses, commitment); ok {
+					return landed, nil
+				}
+				if !anyStatus(statuses) {
+					result.Status = ConfirmationExpired
+					return result, nil
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// landedResult returns the result of the first signature that failed or reached the commitment.
+func landedResult(signatures []string, statuses []*signatureStatus, commitment Commitment) (ConfirmationResult, bool) {
+	for i, status := range statuses {
+		if status == nil || i >= len(signatures) {
+			continue
+		}
+		landed := ConfirmationResult{Signature: signatures[i], Slot: status.Slot}
+		if len(status.Err) > 0 && string(status.Err) != "null" {
+			landed.Status = ConfirmationFailed
+			landed.Err = status.Err
+			landed.Reason = decodeTransactionError(status.Err, nil)
+			return landed, true
+		}
+		if commitmentReached(status.ConfirmationStatus, commitment) {
+			landed.Status = ConfirmationConfirmed
+			return landed, true
+		}
+	}
+	return ConfirmationResult{}, false
+}
+
+// anyStatus reports whether one of the signatures is known to the cluster, at any commitment.
+func anyStatus(statuses []*signatureStatus) bool {
+	for _, status := range statuses {
+		if status != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func getSignatureStatuses(ctx context.Context, rpc RPCClient, signatures []string) ([]*signatureStatus, error) {
+	var out struct {
+		Value []*signatureStatus `json:"value"`
+	}
+	err := rpc.Call(ctx, "getSignatureStatuses", []any{
+		signatures,
+		map[string]any{"searchTransactionHistory": true},
+	}, &out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signature status: %w", err)
+	}
+	return out.Value, nil
+}
+
+func commitmentReached(status string, want Commitment) bool {
+	rank := map[string]int{
+		string(CommitmentProcessed): 1,
+		string(CommitmentConfirmed): 2,
+		string(CommitmentFinalized): 3,
+	}
+	return rank[status] > 0 && rank[status] >= rank[string(want)]
+}
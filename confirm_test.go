@@ -0,0 +1,88 @@
+package jupag
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// fakeRPC answers the RPC calls with fn, the result is encoded to JSON and decoded into the result of the call.
+type fakeRPC func(method string, params []any) any
+
+func (f fakeRPC) Call(_ context.Context, method string, params []any, result any) error {
+	b, err := json.Marshal(f(method, params))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, result)
+}
+
+func statusesValue(statuses ...*signatureStatus) any {
+	return map[string]any{"value": statuses}
+}
+
+func TestWaitForConfirmationExpiry(t *testing.T) {
+	defer func(interval time.Duration) { confirmationPollInterval = interval }(confirmationPollInterval)
+	confirmationPollInterval = time.Millisecond
+
+	tests := []struct {
+		name     string
+		statuses []*signatureStatus // successive getSignatureStatuses answers, the last one repeats
+		want     ConfirmationStatus
+	}{
+		{
+			name:     "never seen",
+			statuses: []*signatureStatus{nil},
+			want:     ConfirmationExpired,
+		},
+		{
+			name:     "landed between the status and the block height calls",
+			statuses: []*signatureStatus{nil, {Slot: 7, ConfirmationStatus: "finalized"}},
+			want:     ConfirmationConfirmed,
+		},
+		{
+			name: "below the commitment when the block height passes",
+			statuses: []*signatureStatus{
+				{Slot: 7, ConfirmationStatus: "confirmed"},
+				{Slot: 7, ConfirmationStatus: "confirmed"},
+				{Slot: 7, ConfirmationStatus: "confirmed"},
+				{Slot: 7, ConfirmationStatus: "finalized"},
+			},
+			want: ConfirmationConfirmed,
+		},
+		{
+			name:     "dropped after being processed",
+			statuses: []*signatureStatus{{Slot: 7, ConfirmationStatus: "processed"}, {Slot: 7, ConfirmationStatus: "processed"}, nil},
+			want:     ConfirmationExpired,
+		},
+		{
+			name:     "failed on-chain",
+			statuses: []*signatureStatus{nil, {Slot: 7, ConfirmationStatus: "processed", Err: json.RawMessage(`{"InstructionError":[0,"InvalidAccountData"]}`)}},
+			want:     ConfirmationFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			rpc := fakeRPC(func(method string, _ []any) any {
+				if method == "getBlockHeight" {
+					return 101
+				}
+				status := tt.statuses[min(calls, len(tt.statuses)-1)]
+				calls++
+				return statusesValue(status)
+			})
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			result, err := waitForConfirmation(ctx, rpc, "sig", CommitmentFinalized, 100)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Status != tt.want {
+				t.Errorf("status = %q, want %q", result.Status, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package jupag
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// CrossRateSource is how a cross rate was derived.
+type CrossRateSource string
+
+const (
+	CrossRateDirect   CrossRateSource = "direct"   // price of the base token in the quote token
+	CrossRateInverted CrossRateSource = "inverted" // inverse of the price of the quote token in the base token
+	CrossRateViaUSDC  CrossRateSource = "usdc"     // ratio of the USDC prices of both tokens
+)
+
+// CrossRateResult is the price of a base token in a quote token.
+type CrossRateResult struct {
+	BaseMint  string
+	QuoteMint string
+	Rate      Decimal // quote tokens per base token, in UI units
+	Source    CrossRateSource
+	Stale     bool      // one of the prices was served from cache while the API was degraded
+	FetchedAt time.Time // fetch time of the oldest price used when served from cache, zero when fetched live
+}
+
+// CrossRate returns the price of baseMint in quoteMint. It uses the direct price, then the inverse of the reverse
+// price, then derives the rate from the USDC prices of both tokens, since the price API doesn't cover every pair.
+// It fails with ErrNoPrice when none is available.
+func (c *JupagImpl) CrossRate(ctx context.Context, baseMint, quoteMint string) (CrossRateResult, error) {
+	result := CrossRateResult{BaseMint: baseMint, QuoteMint: quoteMint}
+	if baseMint == quoteMint {
+		result.Rate = ratDecimal(big.NewRat(1, 1))
+		result.Source = CrossRateDirect
+		return result, nil
+	}
+
+	var lastErr error
+	if prices, err := c.price(ctx, PriceParams{IDs: baseMint, VsToken: quoteMint}); err != nil {
+		lastErr = err
+	} else if p, ok := prices[baseMint]; ok && !p.decimal().IsZero() {
+		result.Source = CrossRateDirect
+		result.Rate = p.decimal()
+		result.withPrices(p)
+		return result, nil
+	}
+
+	if prices, err := c.price(ctx, PriceParams{IDs: quoteMint, VsToken: baseMint}); err != nil {
+		lastErr = err
+	} else if p, ok := prices[quoteMint]; ok && !p.decimal().IsZero() {
+		result.Source = CrossRateInverted
+		result.Rate = ratDecimal(new(big.Rat).Inv(p.decimal().Rat()))
+		result.withPrices(p)
+		return result, nil
+	}
+
+	prices, err := c.price(ctx, PriceParams{IDs: baseMint + "," + quoteMint})
+	if err != nil {
+		return result, err
+	}
+	base, baseOk := prices[baseMint]
+	quote, quoteOk := prices[quoteMint]
+	if !baseOk || !quoteOk || base.decimal().IsZero() || quote.decimal().IsZero() {
+		if lastErr != nil {
+			return result, lastErr
+		}
+		return result, fmt.Errorf("%w: %s in %s", ErrNoPrice, baseMint, quoteMint)
+	}
+	result.Source = CrossRateViaUSDC
+	result.Rate = ratDecimal(new(big.Rat).Quo(base.decimal().Rat(), quote.decimal().Rat()))
+	result.withPrices(base, quote)
+	return result, nil
+}
+
+// withPrices sets the staleness metadata from the prices the rate was derived from.
+func (r *CrossRateResult) withPrices(prices ...Price) {
+	for _, p := range prices {
+		r.Stale = r.Stale || p.Stale
+		if !p.FetchedAt.IsZero() && (r.FetchedAt.IsZero() || p.FetchedAt.Before(r.FetchedAt)) {
+			r.FetchedAt = p.FetchedAt
+		}
+	}
+}
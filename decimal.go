@@ -0,0 +1,247 @@
+package jupag
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Decimal is an exact decimal number, e.g. a price impact or a fee percentage.
+// It keeps the text of the number as returned by the API, so low values don't lose precision in a float64.
+// It unmarshals from a JSON string or number and marshals to a JSON number. The zero value is 0.
+type Decimal struct {
+	s string
+}
+
+// ParseDecimal parses a decimal number, in plain or scientific notation, following the JSON number grammar:
+// ".5", "+1", "1/3" or "0x10" are rejected.
+func ParseDecimal(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if !isJSONNumber(s) {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	return Decimal{s: s}, nil
+}
+
+// isJSONNumber reports whether s is a JSON number, a valid JSON value starting with a minus or a digit.
+func isJSONNumber(s string) bool {
+	return s != "" && (s[0] == '-' || '0' <= s[0] && s[0] <= '9') && json.Valid([]byte(s))
+}
+
+// NewDecimalFromFloat returns the shortest Decimal representing f.
+func NewDecimalFromFloat(f float64) Decimal {
+	return Decimal{s: strconv.FormatFloat(f, 'g', -1, 64)}
+}
+
+// String returns the text of the number.
+func (d Decimal) String() string {
+	if d.s == "" {
+		return "0"
+	}
+	return d.s
+}
+
+// Rat returns the exact value of the number.
+func (d Decimal) Rat() *big.Rat {
+	r, ok := new(big.Rat).SetString(d.String())
+	if !ok {
+		return new(big.Rat)
+	}
+	return r
+}
+
+// Float64 returns the nearest float64 value of the number.
+func (d Decimal) Float64() float64 {
+	f, err := strconv.ParseFloat(d.String(), 64)
+	if err != nil {
+		f, _ = d.Rat().Float64()
+	}
+	return f
+}
+
+// Number returns the number as a json.Number.
+func (d Decimal) Number() json.Number {
+	return json.Number(d.String())
+}
+
+// IsZero reports whether the number is 0.
+func (d Decimal) IsZero() bool {
+	return d.Rat().Sign() == 0
+}
+
+// Cmp compares two numbers and returns -1, 0 or +1.
+func (d Decimal) Cmp(e Decimal) int {
+	return d.Rat().Cmp(e.Rat())
+}
+
+// MarshalJSON encodes the number as a JSON number.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON decodes the number from a JSON string or number, null and "" leave it 0.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s, ok, err := jsonNumberText(data)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		*d = Decimal{}
+		return nil
+	}
+
+	v, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// exactDecimal returns d, the exact value of f set with WithDecimalNumbers, or the shortest Decimal of f when unset.
+func exactDecimal(d Decimal, f float64) Decimal {
+	if d.s == "" {
+		return NewDecimalFromFloat(f)
+	}
+	return d
+}
+
+// decimal returns the price as a Decimal, 0 when it isn't a number.
+func (p Price) decimal() Decimal {
+	d, _ := ParseDecimal(p.Price)
+	return d
+}
+
+// setDecimals sets the fields tagged decimal of v, e.g. `json:"-" decimal:"priceImpactPct"`, to the number of
+// the tagged key in data, walking data along the type of v like the schema drift check.
+func setDecimals(data []byte, v reflect.Value) {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	if !typeHasDecimals(v.Type()) {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		var obj map[string]json.RawMessage
+		if json.Unmarshal(data, &obj) != nil {
+			return
+		}
+		values := make(map[string]json.RawMessage, len(obj))
+		for key, val := range obj {
+			values[strings.ToLower(key)] = val
+		}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			if key, ok := f.Tag.Lookup("decimal"); ok {
+				if val, ok := values[strings.ToLower(key)]; ok {
+					_ = json.Unmarshal(val, v.Field(i).Addr().Interface())
+				}
+				continue
+			}
+			tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			switch {
+			case tag == "-":
+			case f.Anonymous && tag == "":
+				setDecimals(data, v.Field(i).Addr())
+			default:
+				if tag == "" {
+					tag = f.Name
+				}
+				if val, ok := values[strings.ToLower(tag)]; ok {
+					setDecimals(val, v.Field(i).Addr())
+				}
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		var items []json.RawMessage
+		if json.Unmarshal(data, &items) != nil {
+			return
+		}
+		for i := 0; i < len(items) && i < v.Len(); i++ {
+			setDecimals(items[i], v.Index(i).Addr())
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return
+		}
+		var obj map[string]json.RawMessage
+		if json.Unmarshal(data, &obj) != nil {
+			return
+		}
+		for key, val := range obj {
+			k := reflect.ValueOf(key).Convert(v.Type().Key())
+			elem := v.MapIndex(k)
+			if !elem.IsValid() {
+				continue
+			}
+			// map elements aren't addressable, the copy is set back
+			e := reflect.New(elem.Type())
+			e.Elem().Set(elem)
+			setDecimals(val, e)
+			v.SetMapIndex(k, e.Elem())
+		}
+	}
+}
+
+var decimalTypes sync.Map // reflect.Type to bool, see typeHasDecimals
+
+// typeHasDecimals reports whether the type has decimal tagged fields, so the JSON of the other types isn't walked.
+func typeHasDecimals(t reflect.Type) bool {
+	if ok, cached := decimalTypes.Load(t); cached {
+		return ok.(bool)
+	}
+	ok := hasDecimals(t, map[reflect.Type]bool{})
+	decimalTypes.Store(t, ok)
+	return ok
+}
+
+func hasDecimals(t reflect.Type, seen map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if reflect.PointerTo(t).Implements(unmarshalerType) {
+			return false
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if _, ok := f.Tag.Lookup("decimal"); ok && f.IsExported() {
+				return true
+			}
+			if f.IsExported() && hasDecimals(f.Type, seen) {
+				return true
+			}
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return hasDecimals(t.Elem(), seen)
+	}
+	return false
+}
+
+// ratDecimal returns a Decimal of a rational number, rounded to 18 decimal places.
+func ratDecimal(r *big.Rat) Decimal {
+	s := strings.TrimRight(strings.TrimRight(r.FloatString(18), "0"), ".")
+	if s == "" || s == "-0" {
+		s = "0"
+	}
+	return Decimal{s: s}
+}
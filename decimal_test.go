@@ -0,0 +1,130 @@
+package jupag
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string // empty when invalid
+	}{
+		{"0", "0"},
+		{"1.5", "1.5"},
+		{"-0.00000123", "-0.00000123"},
+		{" 42 ", "42"},
+		{"1e-9", "1e-9"},
+		{"2.5E+3", "2.5E+3"},
+		{"", ""},
+		{".5", ""},
+		{"5.", ""},
+		{"+1", ""},
+		{"1/3", ""},
+		{"0x10", ""},
+		{"01", ""},
+		{"-", ""},
+		{"1e", ""},
+		{"NaN", ""},
+		{`"1"`, ""},
+	}
+	for _, tt := range tests {
+		d, err := ParseDecimal(tt.in)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("ParseDecimal(%q) = %s, want an error", tt.in, d)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseDecimal(%q): %v", tt.in, err)
+		} else if d.String() != tt.want {
+			t.Errorf("ParseDecimal(%q) = %s, want %s", tt.in, d, tt.want)
+		}
+	}
+}
+
+func TestDecodeDecimalNumbers(t *testing.T) {
+	const quote = `[{"inAmount":"1000","priceImpactPct":0.000000123456789012345,"marketInfos":[` +
+		`{"id":"a","priceImpactPct":1e-20,"lpFee":{"amount":"3","mint":"m","pct":0.0025}}]}]`
+	const prices = `{"SOL":{"id":"SOL","price":"0.000000000123456789123","extraInfo":{"depth":` +
+		`{"buyPriceImpactRatio":{"depth":{"10":0.0001}}}}}}`
+
+	for _, exact := range []bool{false, true} {
+		c := &JupagImpl{decimalNumbers: exact}
+
+		var q QuoteResponse
+		if err := c.decodeJSON([]byte(quote), &q); err != nil {
+			t.Fatal(err)
+		}
+		var p PriceMap
+		if err := c.decodeJSON([]byte(prices), &p); err != nil {
+			t.Fatal(err)
+		}
+		if q[0].PriceImpactPct != 0.000000123456789012345 || p["SOL"].Price != "0.000000000123456789123" {
+			t.Errorf("exact %v: float64 and string fields changed: %v %q", exact, q[0].PriceImpactPct, p["SOL"].Price)
+		}
+
+		got := []string{
+			q[0].PriceImpactPctDecimal.String(),
+			q[0].MarketInfos[0].PriceImpactPctDecimal.String(),
+			q[0].MarketInfos[0].LpFee.PctDecimal.String(),
+			p["SOL"].PriceDecimal.String(),
+			p["SOL"].ExtraInfo.Depth.BuyPriceImpactRatio.DepthDecimal["10"].String(),
+		}
+		want := []string{"0.000000123456789012345", "1e-20", "0.0025", "0.000000000123456789123", "0.0001"}
+		if !exact {
+			want = []string{"0", "0", "0", "0", "0"}
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("exact %v: decimal %d = %s, want %s", exact, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestDecimalJSON(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: `0.0025`, want: "0.0025"},
+		{in: `"0.000000000123456789123"`, want: "0.000000000123456789123"},
+		{in: `1e-20`, want: "1e-20"},
+		{in: `"-3"`, want: "-3"},
+		{in: `null`, want: "0"},
+		{in: `""`, want: "0"},
+		{in: `".5"`, wantErr: true},
+		{in: `"1/3"`, wantErr: true},
+		{in: `true`, wantErr: true},
+	}
+	for _, tt := range tests {
+		d := NewDecimalFromFloat(7)
+		err := json.Unmarshal([]byte(tt.in), &d)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Decimal %s: error = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if d.String() != tt.want {
+			t.Errorf("Decimal %s = %s, want %s", tt.in, d, tt.want)
+		}
+		if b, err := json.Marshal(d); err != nil || string(b) != tt.want {
+			t.Errorf("Marshal(Decimal %s) = %s, %v, want %s", tt.in, b, err, tt.want)
+		}
+	}
+
+	a, _ := ParseDecimal("0.1")
+	b, _ := ParseDecimal("1e-1")
+	if a.Cmp(b) != 0 || a.IsZero() || a.Float64() != 0.1 {
+		t.Errorf("0.1 and 1e-1: Cmp = %d, IsZero = %v, Float64 = %v", a.Cmp(b), a.IsZero(), a.Float64())
+	}
+	if got := ratDecimal(big.NewRat(1, 3)).String(); got != "0.333333333333333333" {
+		t.Errorf("ratDecimal(1/3) = %s", got)
+	}
+}
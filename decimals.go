@@ -0,0 +1,67 @@
+package jupag
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// DecimalsResolver resolves the number of decimals of a mint.
+type DecimalsResolver interface {
+	Decimals(ctx context.Context, mint string) (uint8, error)
+}
+
+// TokenSource returns the token information of a mint, e.g. the client.
+type TokenSource interface {
+	Token(ctx context.Context, mint string) (TokenInfo, error)
+}
+
+// TokenDecimalsResolver resolves decimals from the token API, results are cached forever since decimals never change.
+type TokenDecimalsResolver struct {
+	client TokenSource
+
+	mu    sync.RWMutex
+	cache map[string]uint8
+}
+
+// NewTokenDecimalsResolver returns a resolver backed by the token API of the given client.
+func NewTokenDecimalsResolver(client TokenSource) *TokenDecimalsResolver {
+	r := &TokenDecimalsResolver{client: client, cache: make(map[string]uint8, len(wellKnownTokens))}
+	for _, t := range wellKnownTokens {
+		r.cache[t.mint] = t.decimals
+	}
+	return r
+}
+
+// Decimals returns the decimals of the mint.
+func (r *TokenDecimalsResolver) Decimals(ctx context.Context, mint string) (uint8, error) {
+	r.mu.RLock()
+	decimals, ok := r.cache[mint]
+	r.mu.RUnlock()
+	if ok {
+		return decimals, nil
+	}
+
+	token, err := r.client.Token(ctx, mint)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve decimals of %s: %w", mint, err)
+	}
+
+	r.mu.Lock()
+	r.cache[mint] = token.Decimals
+	r.mu.Unlock()
+
+	return token.Decimals, nil
+}
+
+// Set stores the decimals of a mint, e.g. for tokens unknown to the token API.
+func (r *TokenDecimalsResolver) Set(mint string, decimals uint8) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cache[mint] = decimals
+}
+
+// Decimals returns the decimals of the mint using the configured DecimalsResolver.
+func (c *JupagImpl) Decimals(ctx context.Context, mint string) (uint8, error) {
+	return c.decimals.Decimals(ctx, mint)
+}
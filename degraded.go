@@ -0,0 +1,117 @@
+package jupag
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var ErrDegraded = errors.New("jupiter api is degraded")
+
+// DegradedModeConfig configures the degraded mode.
+type DegradedModeConfig struct {
+	FailureThreshold int           // consecutive failures before entering degraded mode, default: 5
+	ProbeInterval    time.Duration // interval between requests let through to detect recovery, default: 10s
+}
+
+type degradation struct {
+	cfg DegradedModeConfig
+
+	mu        sync.Mutex
+	failures  int
+	degraded  bool
+	lastProbe time.Time
+	prices    map[string]Price
+}
+
+func newDegradation(cfg DegradedModeConfig) *degradation {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.ProbeInterval <= 0 {
+		cfg.ProbeInterval = 10 * time.Second
+	}
+	return &degradation{cfg: cfg, prices: make(map[string]Price)}
+}
+
+// allow reports whether a request may be sent, in degraded mode one probe request is let through per interval.
+func (d *degradation) allow() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.degraded {
+		return true
+	}
+	if time.Since(d.lastProbe) < d.cfg.ProbeInterval {
+		return false
+	}
+	d.lastProbe = time.Now()
+	return true
+}
+
+// record updates the health state from the outcome of a request, the requests cancelled by the caller are ignored.
+func (d *degradation) record(resp *http.Response, err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	failed := err != nil || resp == nil ||
+		resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !failed {
+		d.failures = 0
+		d.degraded = false
+		return
+	}
+
+	d.failures++
+	if !d.degraded && d.failures >= d.cfg.FailureThreshold {
+		d.degraded = true
+		d.lastProbe = time.Now()
+	}
+}
+
+func (d *degradation) isDegraded() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.degraded
+}
+
+func (d *degradation) rememberPrices(params PriceParams, prices PriceMap) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	now := time.Now()
+	for id, p := range prices {
+		p.FetchedAt = now
+		d.prices[priceCacheKey(id, params.VsToken)] = p
+	}
+}
+
+// cachedPrices returns the cached prices flagged as stale, it reports false when none of the ids is cached.
+func (d *degradation) cachedPrices(params PriceParams) (PriceMap, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	result := make(PriceMap)
+	for _, id := range splitIDs(params.IDs) {
+		if p, ok := d.prices[priceCacheKey(id, params.VsToken)]; ok {
+			p.Stale = true
+			result[id] = p
+		}
+	}
+	return result, len(result) > 0
+}
+
+func priceCacheKey(id, vsToken string) string {
+	return id + "|" + vsToken
+}
+
+// Degraded reports whether the client is in degraded mode.
+func (c *JupagImpl) Degraded() bool {
+	return c.degradation != nil && c.degradation.isDegraded()
+}
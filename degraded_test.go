@@ -0,0 +1,39 @@
+package jupag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestDegradationRecord(t *testing.T) {
+	d := newDegradation(DegradedModeConfig{FailureThreshold: 2})
+
+	canceled := fmt.Errorf("failed to send: %w", context.Canceled)
+	for i := 0; i < 3; i++ {
+		d.record(nil, canceled)
+	}
+	if d.isDegraded() || d.failures != 0 {
+		t.Fatalf("degraded by cancelled requests: failures = %d", d.failures)
+	}
+
+	d.record(&http.Response{StatusCode: http.StatusBadGateway}, nil)
+	d.record(nil, canceled)
+	if d.failures != 1 {
+		t.Errorf("failures = %d after a cancelled request, want 1", d.failures)
+	}
+	d.record(nil, errors.New("connection refused"))
+	if !d.isDegraded() {
+		t.Fatal("not degraded after 2 failures")
+	}
+	d.record(nil, canceled)
+	if !d.isDegraded() {
+		t.Error("recovered by a cancelled request")
+	}
+	d.record(&http.Response{StatusCode: http.StatusOK}, nil)
+	if d.isDegraded() {
+		t.Error("still degraded after a success")
+	}
+}
@@ -0,0 +1,154 @@
+package jupag
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"sort"
+)
+
+const (
+	depthProbeScaleSteps  = 12 // times the probed amount is scaled by 10 to bracket the limit
+	depthProbeSearchSteps = 10 // bisections of the bracket, about 0.1% of its upper bound
+)
+
+// ImpactPoint is the quote of a trade size.
+type ImpactPoint struct {
+	Amount    uint64  // raw input amount
+	OutAmount Amount  // raw output amount
+	ImpactPct float64 // price impact in percent, e.g. 1 for 1%
+	Routable  bool    // false when no route was found for the amount
+}
+
+// DepthProbe is the result of ProbeDepth.
+type DepthProbe struct {
+	Pair         Pair
+	MaxImpactPct float64
+	MaxAmount    uint64        // largest raw input amount found under the price impact limit, 0 if none
+	Points       []ImpactPoint // quoted sizes, by increasing amount
+}
+
+// ProbeDepth searches the largest input amount whose quote has a price impact under maxImpactPct, e.g. to size
+// large orders. It brackets the limit by scaling 1 UI unit of the input token by powers of 10, then bisects the
+// bracket. The quoted points form the depth curve of the pair. Amounts without route count as above the limit.
+func (c *JupagImpl) ProbeDepth(ctx context.Context, inputMint, outputMint string, maxImpactPct float64) (DepthProbe, error) {
+	probe := DepthProbe{Pair: Pair{InputMint: inputMint, OutputMint: outputMint}, MaxImpactPct: maxImpactPct}
+	decimals, err := c.Decimals(ctx, inputMint)
+	if err != nil {
+		return probe, err
+	}
+
+	under := func(amount uint64) (bool, error) {
+		point, err := c.impactPoint(ctx, probe.Pair, amount)
+		if err != nil {
+			return false, err
+		}
+		probe.Points = append(probe.Points, point)
+		return point.Routable && point.ImpactPct <= maxImpactPct, nil
+	}
+
+	// bracket the limit in [lo, hi), lo under it and hi above it
+	var lo, hi uint64
+	amount := pow10(decimals).Uint64()
+	ok, err := under(amount)
+	if err != nil {
+		return probe, err
+	}
+	for i := 0; i < depthProbeScaleSteps; i++ {
+		if ok {
+			lo = amount
+			if amount > math.MaxUint64/10 {
+				break
+			}
+			amount *= 10
+		} else {
+			hi = amount
+			if amount < 10 {
+				break
+			}
+			amount /= 10
+		}
+		if lo > 0 && hi > 0 {
+			break
+		}
+		if ok, err = under(amount); err != nil {
+			return probe, err
+		}
+	}
+
+	if lo > 0 && hi > 0 {
+		for i := 0; i < depthProbeSearchSteps && hi-lo > 1; i++ {
+			mid := lo + (hi-lo)/2
+			ok, err := under(mid)
+			if err != nil {
+				return probe, err
+			}
+			if ok {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+	}
+
+	probe.MaxAmount = lo
+	sort.Slice(probe.Points, func(i, j int) bool { return probe.Points[i].Amount < probe.Points[j].Amount })
+	return probe, nil
+}
+
+// ImpactCurve quotes a ladder of input amounts of the pair concurrently and returns their points in the order
+// of amounts, e.g. to plot the price impact or size TWAP slices. Amounts without route are not routable.
+func (c *JupagImpl) ImpactCurve(ctx context.Context, pair Pair, amounts []uint64) ([]ImpactPoint, error) {
+	params := make([]QuoteParams, len(amounts))
+	for i, amount := range amounts {
+		params[i] = impactQuoteParams(pair, amount)
+	}
+
+	points := make([]ImpactPoint, len(amounts))
+	for i, result := range c.QuoteAll(ctx, params, QuoteAllOptions{}) {
+		point, err := impactPointOf(amounts[i], result.Quotes, result.Err)
+		if err != nil {
+			return nil, err
+		}
+		points[i] = point
+	}
+	return points, nil
+}
+
+// impactPoint quotes an input amount of the pair.
+func (c *JupagImpl) impactPoint(ctx context.Context, pair Pair, amount uint64) (ImpactPoint, error) {
+	quotes, err := c.quote(ctx, impactQuoteParams(pair, amount))
+	return impactPointOf(amount, quotes, err)
+}
+
+func impactQuoteParams(pair Pair, amount uint64) QuoteParams {
+	return QuoteParams{
+		InputMint:  pair.InputMint,
+		OutputMint: pair.OutputMint,
+		Amount:     amount,
+		SwapMode:   SwapModeExactIn,
+	}
+}
+
+// impactPointOf returns the point of a quote, an amount the API found no route for is not routable.
+func impactPointOf(amount uint64, quotes QuoteResponse, err error) (ImpactPoint, error) {
+	point := ImpactPoint{Amount: amount}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode >= http.StatusBadRequest && apiErr.StatusCode < http.StatusInternalServerError &&
+		apiErr.StatusCode != http.StatusTooManyRequests {
+		return point, nil
+	}
+	if err != nil {
+		return point, err
+	}
+	route, err := quotes.GetBestRoute()
+	if err != nil {
+		return point, nil
+	}
+
+	point.OutAmount = route.OutAmount
+	point.ImpactPct = route.PriceImpactPct * 100
+	point.Routable = true
+	return point, nil
+}
@@ -0,0 +1,140 @@
+package jupag
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DiskCache is a Cache storing each value gzip compressed in a file of a directory, e.g. to restart warm.
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache returns a cache in dir, created if missing.
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+// path returns the file of a key, keys are hashed as they may not be valid file names.
+func (d *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:16])+".gz")
+}
+
+// Get returns the value of the key, a corrupted file is a miss.
+func (d *DiskCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	f, err := os.Open(d.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, false, nil
+	}
+	defer zr.Close()
+
+	// the value is prefixed with its expiration in unix nanoseconds, 0 when it never expires
+	var expiresAt int64
+	if err := binary.Read(zr, binary.BigEndian, &expiresAt); err != nil {
+		return nil, false, nil
+	}
+	if expiresAt != 0 && time.Now().UnixNano() >= expiresAt {
+		return nil, false, nil
+	}
+	value, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, false, nil
+	}
+	return value, true, nil
+}
+
+// Set writes the value of the key to a temporary file renamed over the previous one, so readers never see a partial value.
+func (d *DiskCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	var expiresAt int64
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl).UnixNano()
+	}
+	binary.Write(zw, binary.BigEndian, expiresAt)
+	zw.Write(value)
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(d.dir, "tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), d.path(key)); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+func (d *DiskCache) Delete(_ context.Context, key string) error {
+	err := os.Remove(d.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// tieredCache reads from the first cache having the key and writes to all of them.
+type tieredCache []Cache
+
+func (t tieredCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	var errs error
+	for _, cache := range t {
+		value, ok, err := cache.Get(ctx, key)
+		if ok {
+			return value, true, nil
+		}
+		errs = errors.Join(errs, err)
+	}
+	return nil, false, errs
+}
+
+func (t tieredCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	var errs error
+	for _, cache := range t {
+		errs = errors.Join(errs, cache.Set(ctx, key, value, ttl))
+	}
+	return errs
+}
+
+func (t tieredCache) Delete(ctx context.Context, key string) error {
+	var errs error
+	for _, cache := range t {
+		errs = errors.Join(errs, cache.Delete(ctx, key))
+	}
+	return errs
+}
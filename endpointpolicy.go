@@ -0,0 +1,127 @@
+package jupag
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gojek/heimdall/v7"
+	"github.com/gojek/heimdall/v7/httpclient"
+)
+
+// EndpointPolicy is the timeout and retry count of the requests to an endpoint.
+type EndpointPolicy struct {
+	Timeout time.Duration // timeout of each attempt, default: 3s
+	Retries int           // retries on server errors and network failures, 0 for none
+}
+
+// defaultEndpointPolicy applies to the endpoints without a policy of their own.
+var defaultEndpointPolicy = EndpointPolicy{Timeout: 3 * time.Second, Retries: 1}
+
+// defaultEndpointPolicies favor latency for quotes, and completion for the large payloads.
+var defaultEndpointPolicies = map[Endpoint]EndpointPolicy{
+	EndpointQuote:            {Timeout: 2 * time.Second, Retries: 0},
+	EndpointSwap:             {Timeout: 5 * time.Second, Retries: 1},
+	EndpointSwapInstructions: {Timeout: 5 * time.Second, Retries: 1},
+	EndpointRoutesMap:        {Timeout: 30 * time.Second, Retries: 2},
+	EndpointTaggedTokens:     {Timeout: 30 * time.Second, Retries: 2},
+	EndpointHealth:           {Timeout: 3 * time.Second, Retries: 0},
+}
+
+// endpointPolicy returns the policy of an endpoint, set with WithEndpointPolicy or its default.
+func (c *JupagImpl) endpointPolicy(e Endpoint) EndpointPolicy {
+	p, ok := c.policies[e]
+	if !ok {
+		if p, ok = defaultEndpointPolicies[e]; !ok {
+			p = defaultEndpointPolicy
+		}
+	}
+	if p.Timeout <= 0 {
+		p.Timeout = defaultEndpointPolicy.Timeout
+	}
+	if p.Retries < 0 {
+		p.Retries = 0
+	}
+	return p
+}
+
+// buildHTTPClients creates an http client per distinct endpoint policy, sharing the transport.
+func (c *JupagImpl) buildHTTPClients() {
+	var transport http.RoundTripper
+	if c.proxy != nil || c.dialContext != nil {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		if c.proxy != nil {
+			t.Proxy = http.ProxyURL(c.proxy)
+		}
+		if c.dialContext != nil {
+			t.DialContext = c.dialContext
+		}
+		transport = t
+	}
+	httpClient := c.httpClient
+	if len(c.wrapTransport) > 0 {
+		if client, ok := httpClient.(*http.Client); ok {
+			wrapped := *client
+			wrapped.Transport = c.wrappedTransport(client.Transport)
+			httpClient = &wrapped
+		} else if httpClient == nil {
+			transport = c.wrappedTransport(transport)
+		}
+	}
+
+	c.clients = make(map[EndpointPolicy]*httpclient.Client)
+	newClient := func(p EndpointPolicy) *httpclient.Client {
+		if client, ok := c.clients[p]; ok {
+			return client
+		}
+		retrier := heimdall.Retriable(heimdall.NewRetrier(heimdall.NewConstantBackoff(500*time.Millisecond, 1000*time.Millisecond)))
+		if c.retrierSet {
+			retrier = c.retrier
+		}
+		retries := p.Retries
+		if retrier == nil {
+			retries = 0
+			retrier = heimdall.NewNoRetrier()
+		}
+		clientOpts := []httpclient.Option{
+			httpclient.WithHTTPTimeout(p.Timeout),
+			httpclient.WithRetryCount(retries),
+			httpclient.WithRetrier(retrier),
+		}
+		switch {
+		case httpClient != nil:
+			clientOpts = append(clientOpts, httpclient.WithHTTPClient(httpClient))
+		case transport != nil:
+			clientOpts = append(clientOpts, httpclient.WithHTTPClient(&http.Client{Timeout: p.Timeout, Transport: transport}))
+		}
+		client := httpclient.NewClient(clientOpts...)
+		if c.logger != nil {
+			client.AddPlugin(requestLogger{logger: c.logger})
+		}
+		c.clients[p] = client
+		return client
+	}
+
+	c.jupagImpl = newClient(c.endpointPolicy(""))
+	for e := range defaultEndpoints {
+		newClient(c.endpointPolicy(e))
+	}
+}
+
+// wrappedTransport applies the WithTransportWrapper wrappers to the transport, nil is http.DefaultTransport.
+func (c *JupagImpl) wrappedTransport(transport http.RoundTripper) http.RoundTripper {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	for _, wrap := range c.wrapTransport {
+		transport = wrap(transport)
+	}
+	return transport
+}
+
+// httpClientFor returns the http client applying the policy of an endpoint.
+func (c *JupagImpl) httpClientFor(e Endpoint) *httpclient.Client {
+	if client, ok := c.clients[c.endpointPolicy(e)]; ok {
+		return client
+	}
+	return c.jupagImpl
+}
@@ -0,0 +1,76 @@
+package jupag
+
+import "strings"
+
+// APIFamily is a group of endpoints served by the same host.
+type APIFamily string
+
+const (
+	APISwap      APIFamily = "swap"  // quote, swap and routes map
+	APIPrice     APIFamily = "price" // prices
+	APIToken     APIFamily = "token" // token info, tags and search
+	APIUltra     APIFamily = "ultra" // shield
+	APITrigger   APIFamily = "trigger"
+	APIRecurring APIFamily = "recurring"
+)
+
+// Endpoint is an API endpoint whose path can be overridden with WithEndpointPath.
+type Endpoint string
+
+const (
+	EndpointQuote            Endpoint = "quote"
+	EndpointSwap             Endpoint = "swap"
+	EndpointSwapInstructions Endpoint = "swapInstructions"
+	EndpointRoutesMap        Endpoint = "routesMap"
+	EndpointPrice            Endpoint = "price"
+	EndpointToken            Endpoint = "token"
+	EndpointTaggedTokens     Endpoint = "taggedTokens"
+	EndpointSearchTokens     Endpoint = "searchTokens"
+	EndpointShield           Endpoint = "shield"
+	EndpointMarkets          Endpoint = "markets" // self-hosted only
+	EndpointHealth           Endpoint = "health"
+	EndpointTriggerOrders    Endpoint = "triggerOrders"
+	EndpointRecurringOrders  Endpoint = "recurringOrders"
+)
+
+type endpointInfo struct {
+	family APIFamily
+	path   string
+}
+
+var defaultEndpoints = map[Endpoint]endpointInfo{
+	EndpointQuote:            {APISwap, "/quote"},
+	EndpointSwap:             {APISwap, "/swap"},
+	EndpointSwapInstructions: {APISwap, "/swap-instructions"},
+	EndpointRoutesMap:        {APISwap, "/indexed-route-map"},
+	EndpointPrice:            {APIPrice, "/price/v2"},
+	EndpointToken:            {APIToken, "/tokens/v1/token"},
+	EndpointTaggedTokens:     {APIToken, "/tokens/v1/tagged"},
+	EndpointSearchTokens:     {APIToken, "/tokens/v2/search"},
+	EndpointShield:           {APIUltra, "/ultra/v1/shield"},
+	EndpointMarkets:          {APISwap, "/markets"},
+	EndpointHealth:           {APISwap, "/tokens/v1/token/" + MintUSDC}, // lightweight request, "/health" when self-hosted
+	EndpointTriggerOrders:    {APITrigger, "/trigger/v1/getTriggerOrders"},
+	EndpointRecurringOrders:  {APIRecurring, "/recurring/v1/getRecurringOrders"},
+}
+
+// endpoint returns the URL of an endpoint, joining the base URL of its family and its path.
+func (c *JupagImpl) endpoint(e Endpoint) string {
+	return c.endpointAt(c.baseURL(defaultEndpoints[e].family), e)
+}
+
+// endpointAt returns the URL of an endpoint on the given base URL.
+func (c *JupagImpl) endpointAt(base string, e Endpoint) string {
+	path := defaultEndpoints[e].path
+	if p, ok := c.paths[e]; ok {
+		path = p
+	}
+	return strings.TrimRight(base, "/") + path
+}
+
+func (c *JupagImpl) baseURL(family APIFamily) string {
+	if u, ok := c.baseURLs[family]; ok {
+		return u
+	}
+	return c.apiUrl
+}
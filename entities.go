@@ -3,7 +3,9 @@ package jupag
 import (
 	"encoding/json"
 	"errors"
+	"slices"
 	"strconv"
+	"time"
 )
 
 const (
@@ -17,6 +19,17 @@ type Response struct {
 	ContextSlot int64           `json:"contextSlot"`
 }
 
+// Pair is a trading pair.
+type Pair struct {
+	InputMint  string `json:"inputMint"`
+	OutputMint string `json:"outputMint"`
+}
+
+// String returns the pair as "inputMint/outputMint".
+func (p Pair) String() string {
+	return p.InputMint + "/" + p.OutputMint
+}
+
 // MarketInfo is a market info object structure.
 type MarketInfo struct {
 	ID                 string  `json:"id"`
@@ -24,31 +37,35 @@ type MarketInfo struct {
 	InputMint          string  `json:"inputMint"`
 	OutputMint         string  `json:"outputMint"`
 	NotEnoughLiquidity bool    `json:"notEnoughLiquidity"`
-	InAmount           string  `json:"inAmount"`
-	OutAmount          string  `json:"outAmount"`
-	MinInAmount        string  `json:"minInAmount,omitempty"`
-	MinOutAmount       string  `json:"minOutAmount,omitempty"`
+	InAmount           Amount  `json:"inAmount"`
+	OutAmount          Amount  `json:"outAmount"`
+	MinInAmount        *Amount `json:"minInAmount,omitempty"`
+	MinOutAmount       *Amount `json:"minOutAmount,omitempty"`
 	PriceImpactPct     float64 `json:"priceImpactPct"`
 	LpFee              *Fee    `json:"lpFee"`
 	PlatformFee        *Fee    `json:"platformFee"`
+
+	PriceImpactPctDecimal Decimal `json:"-" decimal:"priceImpactPct"` // exact PriceImpactPct, set with WithDecimalNumbers
 }
 
 // Fee is a fee object structure.
 type Fee struct {
-	Amount string  `json:"amount"`
+	Amount Amount  `json:"amount"`
 	Mint   string  `json:"mint"`
 	Pct    float64 `json:"pct"`
+
+	PctDecimal Decimal `json:"-" decimal:"pct"` // exact Pct, set with WithDecimalNumbers
 }
 
 // Route is a route object structure.
 type Route struct {
-	InAmount             string       `json:"inAmount"`
-	OutAmount            string       `json:"outAmount"`
+	InAmount             Amount       `json:"inAmount"`
+	OutAmount            Amount       `json:"outAmount"`
 	PriceImpactPct       float64      `json:"priceImpactPct"`
 	MarketInfos          []MarketInfo `json:"marketInfos"`
-	Amount               string       `json:"amount"`
+	Amount               Amount       `json:"amount"`
 	SlippageBps          int64        `json:"slippageBps"`          // minimum: 0, maximum: 10000
-	OtherAmountThreshold string       `json:"otherAmountThreshold"` // The threshold for the swap based on the provided slippage: when swapMode is ExactIn the minimum out amount, when swapMode is ExactOut the maximum in amount
+	OtherAmountThreshold Amount       `json:"otherAmountThreshold"` // The threshold for the swap based on the provided slippage: when swapMode is ExactIn the minimum out amount, when swapMode is ExactOut the maximum in amount
 	SwapMode             string       `json:"swapMode"`
 	Fees                 *struct {
 		SignatureFee             int64   `json:"signatureFee"`             // This inidicate the total amount needed for signing transaction(s). Value in lamports.
@@ -57,6 +74,12 @@ type Route struct {
 		TotalFeeAndDeposits      int64   `json:"totalFeeAndDeposits"`      // This indicate the total lamports needed for fees and deposits above.
 		MinimumSolForTransaction int64   `json:"minimumSOLForTransaction"` // This inidicate the minimum lamports needed for transaction(s). Might be used to create wrapped SOL and will be returned when the wrapped SOL is closed. Also ensures rent exemption of the wallet.
 	} `json:"fees,omitempty"`
+
+	ContextSlot uint64 `json:"contextSlot,omitempty"` // slot of the data the route was computed on, set by the client
+
+	PriceImpactPctDecimal Decimal `json:"-" decimal:"priceImpactPct"` // exact PriceImpactPct, set with WithDecimalNumbers
+
+	raw json.RawMessage // quote object of a self-hosted swap api, sent back as is to build the swap
 }
 
 // Price is a price object structure.
@@ -67,6 +90,36 @@ type Price struct {
 	VsTokenSymbol string `json:"vsTokenSymbol"` // Symbol of the token to compare against
 	Price         string `json:"price"`         // Price of the token in relation to the vsToken. Default to 1 unit of the token worth in USDC if vsToken is not specified.
 	Type          string `json:"type"`          // Type of price
+
+	PriceDecimal Decimal `json:"-" decimal:"price"` // Price as a number, set with WithDecimalNumbers
+
+	ExtraInfo *PriceExtraInfo `json:"extraInfo,omitempty"` // only returned when PriceParams.ShowExtraInfo is set
+
+	Stale     bool      `json:"-"` // true when served from cache while the API is degraded
+	FetchedAt time.Time `json:"-"` // time the price was fetched from the API, set when served from cache
+}
+
+// PriceExtraInfo is the extra info of a price, returned when PriceParams.ShowExtraInfo is set.
+type PriceExtraInfo struct {
+	ConfidenceLevel string `json:"confidenceLevel"` // high, medium or low
+	QuotedPrice     *struct {
+		BuyPrice  string `json:"buyPrice"`
+		BuyAt     int64  `json:"buyAt"`
+		SellPrice string `json:"sellPrice"`
+		SellAt    int64  `json:"sellAt"`
+	} `json:"quotedPrice,omitempty"`
+	Depth *struct {
+		BuyPriceImpactRatio  PriceDepth `json:"buyPriceImpactRatio"`
+		SellPriceImpactRatio PriceDepth `json:"sellPriceImpactRatio"`
+	} `json:"depth,omitempty"`
+}
+
+// PriceDepth is the price impact ratio for trade sizes in USD (e.g. "10", "100", "1000").
+type PriceDepth struct {
+	Depth     map[string]float64 `json:"depth"`
+	Timestamp int64              `json:"timestamp"`
+
+	DepthDecimal map[string]Decimal `json:"-" decimal:"depth"` // exact Depth, set with WithDecimalNumbers
 }
 
 // PriceMap is a price map objects structure.
@@ -84,11 +137,56 @@ type QuoteParams struct {
 	OnlyDirectRoutes    bool   `url:"onlyDirectRoutes,omitempty"`    // Only return direct routes (no hoppings and split trade)
 	AsLegacyTransaction bool   `url:"asLegacyTransaction,omitempty"` // Only return routes that can be done in a single legacy transaction. (Routes might be limited)
 	UserPublicKey       string `url:"userPublicKey,omitempty"`       // Public key of the user (only pass in if you want deposit and fee being returned, might slow down query)
+	MaxAccounts         uint64 `url:"maxAccounts,omitempty"`         // Maximum number of accounts of the route, lower values keep the transaction smaller. Default to 64.
 }
 
 // QuoteResponse is the response from a quote request.
 type QuoteResponse []Route
 
+// ContextSlot returns the highest context slot of the routes, 0 when unknown.
+func (q QuoteResponse) ContextSlot() uint64 {
+	var slot uint64
+	for _, r := range q {
+		slot = max(slot, r.ContextSlot)
+	}
+	return slot
+}
+
+// clone returns a deep copy of the quotes, so a caller modifying its routes doesn't change them for the others.
+// The amounts are immutable and shared.
+func (q QuoteResponse) clone() QuoteResponse {
+	if q == nil {
+		return nil
+	}
+	quotes := make(QuoteResponse, len(q))
+	for i, r := range q {
+		r.MarketInfos = slices.Clone(r.MarketInfos)
+		for j := range r.MarketInfos {
+			m := &r.MarketInfos[j]
+			m.MinInAmount = clonePtr(m.MinInAmount)
+			m.MinOutAmount = clonePtr(m.MinOutAmount)
+			m.LpFee = clonePtr(m.LpFee)
+			m.PlatformFee = clonePtr(m.PlatformFee)
+		}
+		if r.Fees != nil {
+			fees := *r.Fees
+			fees.OpenOrdersDeposits = slices.Clone(fees.OpenOrdersDeposits)
+			fees.AtaDeposits = slices.Clone(fees.AtaDeposits)
+			r.Fees = &fees
+		}
+		quotes[i] = r
+	}
+	return quotes
+}
+
+func clonePtr[T any](v *T) *T {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
+
 // GetBestRoute returns the best route from a quote response.
 func (q QuoteResponse) GetBestRoute() (Route, error) {
 	if len(q) == 0 {
@@ -109,18 +207,48 @@ func (q QuoteResponse) GetBestRoute() (Route, error) {
 
 // SwapParams are the parameters for a swap request.
 type SwapParams struct {
-	Route                         Route  `json:"route"`                   // required
-	UserPublicKey                 string `json:"userPublicKey,omitempty"` // required
-	WrapUnwrapSol                 *bool  `json:"wrapUnwrapSOL,omitempty"`
-	FeeAccount                    string `json:"feeAccount,omitempty"`                    // Fee token account for the platform fee (only pass in if you set a feeBps), the mint is outputMint for the default swapMode.ExactOut and inputMint for swapMode.ExactIn.
-	AsLegacyTransaction           *bool  `json:"asLegacyTransaction,omitempty"`           // Request a legacy transaction rather than the default versioned transaction, needs to be paired with a quote using asLegacyTransaction otherwise the transaction might be too large.
-	ComputeUnitPriceMicroLamports *int64 `json:"computeUnitPriceMicroLamports,omitempty"` // Compute unit price to prioritize the transaction, the additional fee will be compute unit consumed * computeUnitPriceMicroLamports.
-	DestinationWallet             string `json:"destinationWallet,omitempty"`             // Public key of the wallet that will receive the output of the swap, this assumes the associated token account exists, currently adds a token transfer.
+	Route                         Route            `json:"route"`                   // required
+	UserPublicKey                 string           `json:"userPublicKey,omitempty"` // required
+	WrapUnwrapSol                 *bool            `json:"wrapUnwrapSOL,omitempty"`
+	FeeAccount                    string           `json:"feeAccount,omitempty"`                    // Fee token account for the platform fee (only pass in if you set a feeBps), the mint is outputMint for the default swapMode.ExactOut and inputMint for swapMode.ExactIn.
+	AsLegacyTransaction           *bool            `json:"asLegacyTransaction,omitempty"`           // Request a legacy transaction rather than the default versioned transaction, needs to be paired with a quote using asLegacyTransaction otherwise the transaction might be too large.
+	ComputeUnitPriceMicroLamports FeeSetting       `json:"computeUnitPriceMicroLamports,omitempty"` // Compute unit price to prioritize the transaction, the additional fee will be compute unit consumed * computeUnitPriceMicroLamports. FixedFee or FeeAuto.
+	PrioritizationFeeLamports     FeeSetting       `json:"prioritizationFeeLamports,omitempty"`     // Total priority fee of the transaction (v6 only), exclusive with ComputeUnitPriceMicroLamports. FixedFee or FeeAuto.
+	DestinationWallet             string           `json:"destinationWallet,omitempty"`             // Public key of the wallet that will receive the output of the swap, this assumes the associated token account exists, currently adds a token transfer.
+	DestinationTokenAccount       string           `json:"destinationTokenAccount,omitempty"`       // Token account that will receive the output of the swap, e.g. an exchange deposit account, it must exist. See DeriveATA.
+	TrackingAccount               string           `json:"trackingAccount,omitempty"`               // Public key added as a read-only account of the swap instruction to attribute the volume of an integrator, any key works.
+	BlockhashSlotsToExpiry        uint64           `json:"blockhashSlotsToExpiry,omitempty"`        // Slots before the transaction expires, at most MaxBlockhashSlotsToExpiry. Default to the lifetime of the blockhash, about 150 slots.
+	CorrectLastValidBlockHeight   bool             `json:"correctLastValidBlockHeight,omitempty"`   // Return the last valid block height of the blockhash used by the transaction rather than the one of the API node, they may differ.
+	DynamicSlippage               *DynamicSlippage `json:"dynamicSlippage,omitempty"`               // Let the API estimate the slippage of the swap by simulating it, within the given bounds (v6 only, ExactIn only). The estimate is in SwapResponse.DynamicSlippageReport.
+
+	PriorityFeeEstimator PriorityFeeEstimator `json:"-"` // optional; Estimates ComputeUnitPriceMicroLamports for this swap when it is not set.
 }
 
 // SwapResponse is the response from a swap request.
 type SwapResponse struct {
-	SwapTransaction string `json:"swapTransaction"` // base64 encoded transaction string
+	SwapTransaction      string `json:"swapTransaction"`                // base64 encoded transaction string
+	LastValidBlockHeight uint64 `json:"lastValidBlockHeight,omitempty"` // block height after which the transaction expires
+
+	PrioritizationFeeLamports Int64 `json:"prioritizationFeeLamports,omitempty"` // priority fee of the transaction, in lamports
+	ComputeUnitLimit          Int64 `json:"computeUnitLimit,omitempty"`          // compute unit limit of the transaction
+
+	DynamicSlippageReport *DynamicSlippageReport `json:"dynamicSlippageReport,omitempty"` // slippage estimated by the API, when SwapParams.DynamicSlippage is set
+}
+
+// DynamicSlippage bounds the slippage estimated by the API, in basis points. Zero bounds use the defaults of the API.
+type DynamicSlippage struct {
+	MinBps uint64 `json:"minBps,omitempty"`
+	MaxBps uint64 `json:"maxBps,omitempty"`
+}
+
+// DynamicSlippageReport is the slippage estimated by the API for a swap built with dynamic slippage.
+type DynamicSlippageReport struct {
+	SlippageBps                  Int64   `json:"slippageBps"`                  // slippage set on the transaction
+	OtherAmount                  Amount  `json:"otherAmount"`                  // simulated output amount, zero when not simulated
+	SimulatedIncurredSlippageBps Int64   `json:"simulatedIncurredSlippageBps"` // slippage of the simulation, negative when the output is above the quote
+	AmplificationRatio           Decimal `json:"amplificationRatio"`           // ratio applied to the simulated slippage
+	CategoryName                 string  `json:"categoryName"`                 // category of the pair, e.g. stable, lst or bluechip
+	HeuristicMaxSlippageBps      Int64   `json:"heuristicMaxSlippageBps"`      // maximum slippage of the category
 }
 
 // PriceParams are the parameters for a price request.
@@ -128,43 +256,125 @@ type PriceParams struct {
 	IDs      string  `url:"ids"`                // required; Symbol or address of a token, (e.g. SOL or EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v). Use `,` to query multiple tokens, e.g. (sol,btc,mer,...)
 	VsToken  string  `url:"vsToken,omitempty"`  // optional; Default to USDC. Symbol or address of a token, (e.g. SOL or EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v).
 	VsAmount float64 `url:"vsAmount,omitempty"` // optional; Unit amount of specified input token. Default to 1.
+
+	ShowExtraInfo bool `url:"showExtraInfo,omitempty"` // optional; Return the confidence level, quoted prices and depth of the price.
 }
 
 // IndexedRoutesMap is a map of routes indexed by the route ID.
 type IndexedRoutesMap struct {
 	MintKeys        []string         `json:"mintKeys"`        // All the mints that are indexed to match in indexedRouteMap.
 	IndexedRouteMap map[string][]int `json:"indexedRouteMap"` // All the possible route and their corresponding output mints.
+
+	index *routesIndex
+}
+
+type routesIndex struct {
+	mints   map[string]int           // mint -> position in MintKeys
+	outputs map[int]map[int]struct{} // input position -> output positions
+}
+
+// BuildIndex builds the lookup index of the routes map, giving O(1) lookups.
+// It is built lazily by the lookup methods otherwise, call it before sharing the map between goroutines.
+// RoutesMap returns maps with the index already built.
+func (r *IndexedRoutesMap) BuildIndex() {
+	idx := &routesIndex{
+		mints:   make(map[string]int, len(r.MintKeys)),
+		outputs: make(map[int]map[int]struct{}, len(r.IndexedRouteMap)),
+	}
+	for i, mint := range r.MintKeys {
+		idx.mints[mint] = i
+	}
+	for key, outputs := range r.IndexedRouteMap {
+		in, err := strconv.Atoi(key)
+		if err != nil || in < 0 || in >= len(r.MintKeys) {
+			continue
+		}
+		set := make(map[int]struct{}, len(outputs))
+		for _, out := range outputs {
+			if out >= 0 && out < len(r.MintKeys) {
+				set[out] = struct{}{}
+			}
+		}
+		idx.outputs[in] = set
+	}
+	r.index = idx
+}
+
+func (r *IndexedRoutesMap) ensureIndex() *routesIndex {
+	if r.index == nil {
+		r.BuildIndex()
+	}
+	return r.index
 }
 
 // GetRoutesForMint returns the routes for a given mint.
 func (r *IndexedRoutesMap) GetRoutesForMint(mint string) []string {
-	// Find index of mint in mintKeys.
-	var mintKeys []int
-	for key, val := range r.MintKeys {
-		if val == mint {
-			mintKeys = r.IndexedRouteMap[strconv.Itoa(key)]
-		}
+	idx := r.ensureIndex()
+	in, ok := idx.mints[mint]
+	if !ok {
+		return []string{}
 	}
 
-	// Find the mint in mintKeys.
-	result := make([]string, 0, len(mintKeys))
-	for _, key := range mintKeys {
-		result = append(result, r.MintKeys[key])
+	outputs := r.IndexedRouteMap[strconv.Itoa(in)]
+	result := make([]string, 0, len(outputs))
+	for _, out := range outputs {
+		if out >= 0 && out < len(r.MintKeys) {
+			result = append(result, r.MintKeys[out])
+		}
 	}
 
 	return result
 }
 
+// HasRoute reports whether the input mint can be swapped to the output mint.
+func (r *IndexedRoutesMap) HasRoute(inputMint, outputMint string) bool {
+	idx := r.ensureIndex()
+	in, ok := idx.mints[inputMint]
+	if !ok {
+		return false
+	}
+	out, ok := idx.mints[outputMint]
+	if !ok {
+		return false
+	}
+	_, ok = idx.outputs[in][out]
+	return ok
+}
+
+// ForEachPair calls fn for each pair of the routes map until it returns false.
+func (r *IndexedRoutesMap) ForEachPair(fn func(Pair) bool) {
+	idx := r.ensureIndex()
+	for in, outputs := range idx.outputs {
+		for out := range outputs {
+			if !fn(Pair{InputMint: r.MintKeys[in], OutputMint: r.MintKeys[out]}) {
+				return
+			}
+		}
+	}
+}
+
+// Pairs returns all the pairs of the routes map.
+func (r *IndexedRoutesMap) Pairs() []Pair {
+	pairs := make([]Pair, 0)
+	r.ForEachPair(func(p Pair) bool {
+		pairs = append(pairs, p)
+		return true
+	})
+	return pairs
+}
+
 // BestSwapParams contains the parameters for the best swap route.
 type BestSwapParams struct {
-	UserPublicKey        string // user base58 encoded public key
-	DestinationPublicKey string // destination base58 encoded public key (optional)
-	FeeAmount            uint64 // fee amount in token basis points (optional)
-	FeeAccount           string // fee token account for the platform fee (only pass in if you set a FeeAmount).
-	InputMint            string // input mint
-	OutputMint           string // output mint
-	Amount               uint64 // amount of output token
-	SwapMode             string // swap mode, default: ExactIn (Available: ExactIn, ExactOut)
+	UserPublicKey        string  // user base58 encoded public key
+	DestinationPublicKey string  // destination base58 encoded public key (optional)
+	FeeAmount            uint64  // fee amount in token basis points (optional)
+	FeeAccount           string  // fee token account for the platform fee (only pass in if you set a FeeAmount).
+	InputMint            string  // input mint
+	OutputMint           string  // output mint
+	Amount               uint64  // amount of output token
+	SwapMode             string  // swap mode, default: ExactIn (Available: ExactIn, ExactOut)
+	SlippageBps          uint64  // slippage tolerance in basis points, default: slippage registry, slippage engine or API default (optional)
+	MaxPriceImpactPct    float64 // rejects routes with a higher price impact in percent, e.g. 1 for 1%, default: slippage registry (optional)
 }
 
 // ExchangeRateParams contains the parameters for the exchange rate request.
@@ -182,3 +392,47 @@ type Rate struct {
 	InAmount   uint64 `json:"inAmount"`   // amount of input token
 	OutAmount  uint64 `json:"outAmount"`  // amount of output token
 }
+
+// TokenInfo is a token object structure of the token API.
+type TokenInfo struct {
+	Address           string            `json:"address"`
+	Name              string            `json:"name"`
+	Symbol            string            `json:"symbol"`
+	Decimals          uint8             `json:"decimals"`
+	LogoURI           string            `json:"logoURI"`
+	Tags              []string          `json:"tags"`
+	DailyVolume       float64           `json:"daily_volume"`
+	CreatedAt         string            `json:"created_at"`
+	FreezeAuthority   *string           `json:"freeze_authority"`
+	MintAuthority     *string           `json:"mint_authority"`
+	PermanentDelegate *string           `json:"permanent_delegate"`
+	MintedAt          *string           `json:"minted_at"`
+	Extensions        map[string]string `json:"extensions"`
+}
+
+// TokenSearchResult is a token returned by the token search API.
+type TokenSearchResult struct {
+	ID                string   `json:"id"` // mint address
+	Name              string   `json:"name"`
+	Symbol            string   `json:"symbol"`
+	Icon              string   `json:"icon"`
+	Decimals          uint8    `json:"decimals"`
+	TokenProgram      string   `json:"tokenProgram"`
+	Tags              []string `json:"tags"`
+	IsVerified        bool     `json:"isVerified"`
+	MintAuthority     *string  `json:"mintAuthority"`
+	FreezeAuthority   *string  `json:"freezeAuthority"`
+	HolderCount       int64    `json:"holderCount"`
+	OrganicScore      float64  `json:"organicScore"`
+	OrganicScoreLabel string   `json:"organicScoreLabel"`
+	USDPrice          float64  `json:"usdPrice"`
+	Liquidity         float64  `json:"liquidity"`
+	MCap              float64  `json:"mcap"`
+}
+
+// ShieldWarning is a warning of the Shield API about a mint.
+type ShieldWarning struct {
+	Type     string `json:"type"` // e.g. NOT_VERIFIED, LOW_LIQUIDITY, HAS_FREEZE_AUTHORITY
+	Message  string `json:"message"`
+	Severity string `json:"severity"` // info, warning or critical
+}
@@ -0,0 +1,54 @@
+package jupag
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// envelopeKeys are the keys of the data envelope of the legacy endpoints.
+var envelopeKeys = map[string]bool{"data": true, "timeTaken": true, "contextSlot": true}
+
+// unwrapEnvelope returns the data envelope of a response body. A bare body, as returned by the newer
+// endpoints, is the data of an envelope whose context slot and time taken are read from the body if any.
+// Only objects with a data key and no other key than those of the envelope are envelopes.
+func (c *JupagImpl) unwrapEnvelope(body []byte) (Response, error) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || body[0] != '{' {
+		return Response{Data: body}, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := c.jsonCodec().Unmarshal(body, &fields); err != nil {
+		return Response{}, err
+	}
+	enveloped := fields["data"] != nil
+	for key := range fields {
+		enveloped = enveloped && envelopeKeys[key]
+	}
+
+	response := Response{Data: body}
+	if enveloped {
+		response.Data = fields["data"]
+	}
+	if v, ok := fields["contextSlot"]; ok {
+		_ = json.Unmarshal(v, &response.ContextSlot)
+	}
+	if v, ok := fields["timeTaken"]; ok {
+		_ = json.Unmarshal(v, &response.TimeTaken)
+	}
+	return response, nil
+}
+
+// decodeData decodes a response body into v, unwrapping the data envelope if any.
+func (c *JupagImpl) decodeData(r io.Reader, v any) error {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	response, err := c.unwrapEnvelope(body)
+	if err != nil {
+		return err
+	}
+	return c.decodeJSON(response.Data, v)
+}
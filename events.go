@@ -0,0 +1,122 @@
+package jupag
+
+import "context"
+
+// EventStage is the stage of a swap execution at which an event occurred.
+type EventStage string
+
+const (
+	StageQuote   EventStage = "quote"   // quote request
+	StageSwap    EventStage = "swap"    // swap transaction build
+	StageSubmit  EventStage = "submit"  // transaction signing and submission
+	StageConfirm EventStage = "confirm" // confirmation of a submitted transaction
+)
+
+// Events are callbacks invoked by the client, e.g. for accounting, alerting or persistence. All are optional.
+// They are called synchronously on the calling goroutine and must not block.
+type Events struct {
+	OnQuote     func(ctx context.Context, params QuoteParams, quote QuoteResponse)
+	OnSwapBuilt func(ctx context.Context, params SwapParams, swap SwapResponse)
+	OnSubmitted func(ctx context.Context, signature string, route Route)
+	OnConfirmed func(ctx context.Context, result ConfirmationResult)
+	OnFailed    func(ctx context.Context, stage EventStage, err error)
+}
+
+// MergeEvents returns events calling the callbacks of all the given events in order, e.g. to add
+// a WebhookNotifier to existing events.
+func MergeEvents(events ...Events) Events {
+	var merged Events
+	for _, e := range events {
+		e := e
+		if f := e.OnQuote; f != nil {
+			prev := merged.OnQuote
+			merged.OnQuote = func(ctx context.Context, params QuoteParams, quote QuoteResponse) {
+				if prev != nil {
+					prev(ctx, params, quote)
+				}
+				f(ctx, params, quote)
+			}
+		}
+		if f := e.OnSwapBuilt; f != nil {
+			prev := merged.OnSwapBuilt
+			merged.OnSwapBuilt = func(ctx context.Context, params SwapParams, swap SwapResponse) {
+				if prev != nil {
+					prev(ctx, params, swap)
+				}
+				f(ctx, params, swap)
+			}
+		}
+		if f := e.OnSubmitted; f != nil {
+			prev := merged.OnSubmitted
+			merged.OnSubmitted = func(ctx context.Context, signature string, route Route) {
+				if prev != nil {
+					prev(ctx, signature, route)
+				}
+				f(ctx, signature, route)
+			}
+		}
+		if f := e.OnConfirmed; f != nil {
+			prev := merged.OnConfirmed
+			merged.OnConfirmed = func(ctx context.Context, result ConfirmationResult) {
+				if prev != nil {
+					prev(ctx, result)
+				}
+				f(ctx, result)
+			}
+		}
+		if f := e.OnFailed; f != nil {
+			prev := merged.OnFailed
+			merged.OnFailed = func(ctx context.Context, stage EventStage, err error) {
+				if prev != nil {
+					prev(ctx, stage, err)
+				}
+				f(ctx, stage, err)
+			}
+		}
+	}
+	return merged
+}
+
+func (e *Events) quote(ctx context.Context, params QuoteParams, quote QuoteResponse, err error) {
+	if e == nil {
+		return
+	}
+	if err != nil {
+		e.failed(ctx, StageQuote, err)
+		return
+	}
+	if e.OnQuote != nil {
+		e.OnQuote(ctx, params, quote)
+	}
+}
+
+func (e *Events) swapBuilt(ctx context.Context, params SwapParams, swap SwapResponse, err error) {
+	if e == nil {
+		return
+	}
+	if err != nil {
+		e.failed(ctx, StageSwap, err)
+		return
+	}
+	if e.OnSwapBuilt != nil {
+		e.OnSwapBuilt(ctx, params, swap)
+	}
+}
+
+func (e *Events) submitted(ctx context.Context, signature string, route Route) {
+	if e != nil && e.OnSubmitted != nil {
+		e.OnSubmitted(ctx, signature, route)
+	}
+}
+
+func (e *Events) confirmed(ctx context.Context, result ConfirmationResult) {
+	if e != nil && e.OnConfirmed != nil {
+		e.OnConfirmed(ctx, result)
+	}
+}
+
+func (e *Events) failed(ctx context.Context, stage EventStage, err error) {
+	if e != nil && e.OnFailed != nil {
+		e.OnFailed(ctx, stage, err)
+	}
+}
@@ -9,7 +9,7 @@ import (
 func main() {
 	client := jupag.NewJupag()
 	prcs, e := client.Price(jupag.PriceParams{
-		IDs: "JitoSOL,SOL",
+		IDs: jupag.MintJitoSOL + "," + jupag.MintSOL,
 	})
 
 	if e != nil {
@@ -17,6 +17,6 @@ func main() {
 	}
 
 	for _, prc := range prcs {
-		fmt.Printf("%s [%s]: %.06f %s\n", prc.ID, prc.MintSymbol, prc.Price, prc.VsTokenSymbol)
+		fmt.Printf("%s [%s]: %s %s\n", prc.ID, prc.MintSymbol, prc.Price, prc.VsTokenSymbol)
 	}
 }
@@ -0,0 +1,303 @@
+package jupag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrNoSigner           = errors.New("signer is required")
+	ErrTransactionExpired = errors.New("transaction expired before landing")
+	ErrTransactionFailed  = errors.New("transaction failed on-chain")
+)
+
+// SwapOptions are the execution options of SwapAndSend.
+type SwapOptions struct {
+	Signer     Signer     // required; signs the swap transaction, must match BestSwapParams.UserPublicKey
+	Commitment Commitment // commitment to wait for, default: confirmed
+
+	// MaxResends is the number of times the swap is re-quoted, rebuilt and resubmitted
+	// when the transaction expires before landing. Default: 0 (no resend).
+	MaxResends int
+	// ComputeUnitPriceMicroLamports is the compute unit price of the first attempt (optional).
+	ComputeUnitPriceMicroLamports int64
+	// FeeEscalation multiplies the compute unit price on each resend, e.g. 1.5. Default: 1 (no escalation).
+	// Without ComputeUnitPriceMicroLamports, the price set by the API in the first transaction is escalated.
+	FeeEscalation float64
+	// SkipPreflight disables the RPC node preflight simulation.
+	SkipPreflight bool
+	// CheckBalance verifies that the wallet holds the input amount and the SOL for fees and deposits
+	// before each submission, failing with an InsufficientBalanceError otherwise.
+	CheckBalance bool
+	// MaxPriceImpactPct rejects the routes with a higher price impact in percent with a PriceImpactError,
+	// the stricter of it and BestSwapParams.MaxPriceImpactPct applies.
+	MaxPriceImpactPct float64
+	// MinOut is the minimum raw amount of output token to receive. Routes whose worst case output is below it
+	// are rejected, and the realized output of the landed transaction is checked against it, failing with
+	// a ReceivedBelowMinimumError in both cases.
+	MinOut uint64
+}
+
+// SendAttempt is a single submission attempt of SwapAndSend.
+type SendAttempt struct {
+	Signature                     string
+	ComputeUnitPriceMicroLamports int64
+	Route                         Route
+	Confirmation                  ConfirmationResult
+	Err                           error
+
+	lastValidBlockHeight uint64
+}
+
+// SwapResult is the result of SwapAndSend.
+type SwapResult struct {
+	Signature    string // signature of the landed transaction
+	Route        Route  // route of the landed transaction
+	Confirmation ConfirmationResult
+	Attempts     []SendAttempt
+	Received     Amount // realized raw output amount, set when SwapOptions.MinOut is set
+}
+
+// SwapAndSend quotes the best route, builds, signs and submits the swap, then waits for confirmation.
+// If the transaction expires before landing, the quote is refreshed and the swap rebuilt and resubmitted
+// up to opts.MaxResends times, escalating the compute unit price by opts.FeeEscalation.
+// When the landed swap fails the opts.MinOut check, the result is returned along with the error.
+func (c *JupagImpl) SwapAndSend(ctx context.Context, params BestSwapParams, opts SwapOptions) (SwapResult, error) {
+	result, err := c.swapAndSend(ctx, params, opts)
+	c.storeResult(ctx, result, err)
+	return result, err
+}
+
+func (c *JupagImpl) swapAndSend(ctx context.Context, params BestSwapParams, opts SwapOptions) (SwapResult, error) {
+	if c.rpc == nil {
+		return SwapResult{}, ErrNoRPC
+	}
+	if opts.Signer == nil {
+		return SwapResult{}, ErrNoSigner
+	}
+	if params.UserPublicKey == "" {
+		params.UserPublicKey = opts.Signer.PublicKey().String()
+	}
+	if opts.FeeEscalation <= 0 {
+		opts.FeeEscalation = 1
+	}
+	if opts.MaxPriceImpactPct > 0 && (params.MaxPriceImpactPct == 0 || opts.MaxPriceImpactPct < params.MaxPriceImpactPct) {
+		params.MaxPriceImpactPct = opts.MaxPriceImpactPct
+	}
+
+	var result SwapResult
+	price := float64(opts.ComputeUnitPriceMicroLamports)
+	for i := 0; i <= opts.MaxResends; i++ {
+		attempt := c.sendAttempt(ctx, params, opts, int64(price))
+		result.Attempts = append(result.Attempts, attempt)
+
+		if errors.Is(attempt.Err, ErrTransactionExpired) {
+			// an earlier attempt may have landed since its expiry was detected, resending would swap twice
+			landed, err := c.landedAttempt(ctx, result.Attempts, opts.Commitment)
+			if err != nil {
+				return result, err
+			}
+			if landed >= 0 {
+				attempt = result.Attempts[landed]
+			}
+		}
+		if attempt.Err == nil {
+			result.Signature = attempt.Signature
+			result.Route = attempt.Route
+			result.Confirmation = attempt.Confirmation
+			if opts.MinOut > 0 {
+				return result, c.checkReceived(ctx, &result, params, opts.MinOut)
+			}
+			return result, nil
+		}
+		if !errors.Is(attempt.Err, ErrTransactionExpired) {
+			return result, attempt.Err
+		}
+
+		if price == 0 {
+			price = float64(attempt.ComputeUnitPriceMicroLamports)
+		}
+		price *= opts.FeeEscalation
+	}
+
+	return result, fmt.Errorf("swap not landed after %d attempts: %w", len(result.Attempts), ErrTransactionExpired)
+}
+
+func (c *JupagImpl) sendAttempt(ctx context.Context, params BestSwapParams, opts SwapOptions, computeUnitPrice int64) SendAttempt {
+	attempt := SendAttempt{ComputeUnitPriceMicroLamports: computeUnitPrice}
+
+	route, err := c.bestRoute(ctx, params)
+	if err != nil {
+		attempt.Err = err
+		return attempt
+	}
+	attempt.Route = route
+
+	if err := checkMinOut(route, params.OutputMint, opts.MinOut); err != nil {
+		attempt.Err = err
+		c.events.failed(ctx, StageQuote, err)
+		return attempt
+	}
+	if opts.CheckBalance {
+		if err := c.checkBalance(ctx, params.UserPublicKey, route, computeUnitPrice); err != nil {
+			attempt.Err = err
+			c.events.failed(ctx, StageSubmit, err)
+			return attempt
+		}
+	}
+
+	var price FeeSetting
+	if computeUnitPrice > 0 {
+		price = FixedFee(computeUnitPrice)
+	}
+	swap, err := c.buildSwap(ctx, params, route, price)
+	if err != nil {
+		attempt.Err = err
+		return attempt
+	}
+
+	attempt.lastValidBlockHeight = swap.LastValidBlockHeight
+
+	tx, err := DecodeTransaction(swap.SwapTransaction)
+	if err != nil {
+		attempt.Err = err
+		c.events.failed(ctx, StageSubmit, err)
+		return attempt
+	}
+	if computeUnitPrice == 0 {
+		if price, ok := tx.Message.computeUnitPrice(); ok {
+			attempt.ComputeUnitPriceMicroLamports = int64(price)
+		}
+	}
+	if err := tx.Sign(opts.Signer); err != nil {
+		attempt.Err = err
+		c.events.failed(ctx, StageSubmit, err)
+		return attempt
+	}
+	if err := tx.CheckSize(); err != nil {
+		attempt.Err = err
+		c.events.failed(ctx, StageSubmit, err)
+		return attempt
+	}
+
+	submitSlot := c.submitSlot(ctx)
+	attempt.Signature, err = SendTransaction(ctx, c.rpc, tx.Base64(), opts.SkipPreflight)
+	if err != nil {
+		attempt.Err = err
+		c.events.failed(ctx, StageSubmit, err)
+		return attempt
+	}
+	c.stats.submitted()
+	c.events.submitted(ctx, attempt.Signature, route)
+
+	attempt.Confirmation, err = waitForConfirmation(ctx, c.rpc, attempt.Signature, opts.Commitment, swap.LastValidBlockHeight)
+	if err != nil {
+		attempt.Err = err
+		c.events.failed(ctx, StageConfirm, err)
+		return attempt
+	}
+	c.stats.confirmed(attempt.Confirmation, submitSlot)
+
+	attempt.Err = confirmationError(attempt.Confirmation)
+	if attempt.Err != nil {
+		c.events.failed(ctx, StageConfirm, attempt.Err)
+	} else {
+		c.events.confirmed(ctx, attempt.Confirmation)
+	}
+
+	return attempt
+}
+
+// landedAttempt checks the expired attempts once more and returns the index of the one that landed or failed
+// after all, or -1. The attempts seen below the commitment are waited on.
+func (c *JupagImpl) landedAttempt(ctx context.Context, attempts []SendAttempt, commitment Commitment) (int, error) {
+	var (
+		signatures []string
+		height     uint64
+	)
+	for _, attempt := range attempts {
+		if attempt.Signature != "" && errors.Is(attempt.Err, ErrTransactionExpired) {
+			signatures = append(signatures, attempt.Signature)
+			height = max(height, attempt.lastValidBlockHeight)
+		}
+	}
+	if len(signatures) == 0 {
+		return -1, nil
+	}
+
+	confirmation, err := waitForAnyConfirmation(ctx, c.rpc, signatures, commitment, max(height, 1))
+	if err != nil {
+		return -1, err
+	}
+	if confirmation.Status == ConfirmationExpired {
+		return -1, nil
+	}
+	for i := range attempts {
+		if attempts[i].Signature == confirmation.Signature {
+			attempts[i].Confirmation = confirmation
+			attempts[i].Err = confirmationError(confirmation)
+			if attempts[i].Err != nil {
+				c.events.failed(ctx, StageConfirm, attempts[i].Err)
+			} else {
+				c.events.confirmed(ctx, confirmation)
+			}
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+// confirmationError returns the error of an expired or failed transaction, nil when confirmed.
+func confirmationError(confirmation ConfirmationResult) error {
+	switch confirmation.Status {
+	case ConfirmationExpired:
+		return ErrTransactionExpired
+	case ConfirmationFailed:
+		if confirmation.Reason != nil {
+			return fmt.Errorf("%w: %w", ErrTransactionFailed, confirmation.Reason)
+		}
+		return fmt.Errorf("%w: %s", ErrTransactionFailed, confirmation.Err)
+	}
+	return nil
+}
+
+// checkReceived sets the realized output of a landed swap and returns a ReceivedBelowMinimumError
+// when it is below minOut.
+func (c *JupagImpl) checkReceived(ctx context.Context, result *SwapResult, params BestSwapParams, minOut uint64) error {
+	receiver := params.DestinationPublicKey
+	if receiver == "" {
+		receiver = params.UserPublicKey
+	}
+	received, err := ReceivedAmount(ctx, c.rpc, result.Signature, receiver, params.OutputMint)
+	if err != nil {
+		return fmt.Errorf("failed to check received amount: %w", err)
+	}
+	result.Received = received
+	if received.Cmp(NewAmount(minOut)) < 0 {
+		err := &ReceivedBelowMinimumError{Mint: params.OutputMint, MinOut: NewAmount(minOut), Received: received, Realized: true}
+		c.events.failed(ctx, StageConfirm, err)
+		return err
+	}
+	return nil
+}
+
+// SendTransaction submits a base64 encoded signed transaction and returns its signature.
+// The RPC node retries are disabled, the caller is responsible for confirmation and resubmission.
+func SendTransaction(ctx context.Context, rpc RPCClient, tx string, skipPreflight bool) (string, error) {
+	var signature string
+	err := rpc.Call(ctx, "sendTransaction", []any{
+		tx,
+		map[string]any{
+			"encoding":            "base64",
+			"skipPreflight":       skipPreflight,
+			"preflightCommitment": CommitmentProcessed,
+			"maxRetries":          0,
+		},
+	}, &signature)
+	if err != nil {
+		return "", fmt.Errorf("failed to send transaction: %w", err)
+	}
+
+	return signature, nil
+}
@@ -0,0 +1,193 @@
+package jupag
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FailoverConfig configures the failover between base URLs.
+type FailoverConfig struct {
+	BaseURLs         []string      // required; in order of preference, e.g. paid api, lite-api, self-hosted instance
+	FailureThreshold int           // consecutive failures before a base URL is marked unhealthy, default: 3
+	RecoveryInterval time.Duration // interval between health checks of an unhealthy base URL, default: 10s
+}
+
+// EndpointStats are the statistics of a failover base URL.
+type EndpointStats struct {
+	URL                 string
+	Healthy             bool
+	Requests            uint64
+	Failures            uint64
+	ConsecutiveFailures int
+	LastLatency         time.Duration
+	AvgLatency          time.Duration // exponentially weighted moving average of the successful requests
+	LastError           string
+	LastFailure         time.Time
+	LastCheck           time.Time // last health check, while unhealthy
+}
+
+type failover struct {
+	cfg   FailoverConfig
+	check func(ctx context.Context, base string) HealthStatus
+
+	mu        sync.Mutex
+	endpoints []*failoverEndpoint
+}
+
+type failoverEndpoint struct {
+	stats   EndpointStats
+	probing bool
+}
+
+func newFailover(cfg FailoverConfig) *failover {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 3
+	}
+	if cfg.RecoveryInterval <= 0 {
+		cfg.RecoveryInterval = 10 * time.Second
+	}
+	f := &failover{cfg: cfg}
+	for _, u := range cfg.BaseURLs {
+		f.endpoints = append(f.endpoints, &failoverEndpoint{stats: EndpointStats{URL: strings.TrimRight(u, "/"), Healthy: true}})
+	}
+	return f
+}
+
+// do sends the request to the healthy base URLs in order of preference until one succeeds.
+// The unhealthy ones are only tried when all the others failed. URLs not on the primary base URL are sent as is.
+func (f *failover) do(ctx context.Context, completeUrl string, send func(completeUrl string) (*http.Response, error)) (*http.Response, error) {
+	primary := f.endpoints[0].stats.URL
+	if !strings.HasPrefix(completeUrl, primary) {
+		return send(completeUrl)
+	}
+	path := strings.TrimPrefix(completeUrl, primary)
+
+	var (
+		resp *http.Response
+		err  error
+	)
+	for i, endpoint := range f.candidates() {
+		if i > 0 && resp != nil {
+			resp.Body.Close()
+		}
+
+		start := time.Now()
+		resp, err = send(endpoint.stats.URL + path)
+		if errors.Is(err, context.Canceled) {
+			// cancelled by the caller, says nothing about the endpoint
+			return resp, err
+		}
+		failed := failedResponse(resp, err)
+		f.record(endpoint, time.Since(start), failed, resp, err)
+		if !failed || ctx.Err() != nil {
+			return resp, err
+		}
+	}
+	return resp, err
+}
+
+// candidates returns the healthy endpoints followed by the unhealthy ones, scheduling the due health checks.
+func (f *failover) candidates() []*failoverEndpoint {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	healthy := make([]*failoverEndpoint, 0, len(f.endpoints))
+	var unhealthy []*failoverEndpoint
+	for _, e := range f.endpoints {
+		if e.stats.Healthy {
+			healthy = append(healthy, e)
+			continue
+		}
+		unhealthy = append(unhealthy, e)
+		if !e.probing && f.check != nil && time.Since(e.stats.LastCheck) >= f.cfg.RecoveryInterval {
+			e.probing = true
+			e.stats.LastCheck = time.Now()
+			go f.probe(e)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+// probe marks an unhealthy endpoint healthy again when its health check succeeds.
+func (f *failover) probe(e *failoverEndpoint) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	f.mu.Lock()
+	base := e.stats.URL
+	f.mu.Unlock()
+
+	status := f.check(ctx, base)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e.probing = false
+	if status.Healthy {
+		e.stats.Healthy = true
+		e.stats.ConsecutiveFailures = 0
+	}
+}
+
+func (f *failover) record(e *failoverEndpoint, latency time.Duration, failed bool, resp *http.Response, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	e.stats.Requests++
+	e.stats.LastLatency = latency
+	if !failed {
+		e.stats.ConsecutiveFailures = 0
+		e.stats.Healthy = true
+		if e.stats.AvgLatency == 0 {
+			e.stats.AvgLatency = latency
+		} else {
+			e.stats.AvgLatency = (e.stats.AvgLatency*4 + latency) / 5
+		}
+		return
+	}
+
+	e.stats.Failures++
+	e.stats.ConsecutiveFailures++
+	e.stats.LastFailure = time.Now()
+	switch {
+	case err != nil:
+		e.stats.LastError = err.Error()
+	case resp != nil:
+		e.stats.LastError = http.StatusText(resp.StatusCode)
+	}
+	if e.stats.Healthy && e.stats.ConsecutiveFailures >= f.cfg.FailureThreshold {
+		e.stats.Healthy = false
+		e.stats.LastCheck = time.Now()
+	}
+}
+
+func (f *failover) stats() []EndpointStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stats := make([]EndpointStats, len(f.endpoints))
+	for i, e := range f.endpoints {
+		stats[i] = e.stats
+	}
+	return stats
+}
+
+// failedResponse reports whether a request should be retried on another base URL.
+func failedResponse(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	return resp == nil || resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests
+}
+
+// EndpointStats returns the statistics of the failover base URLs, in order of preference.
+// It returns nil when the failover isn't configured.
+func (c *JupagImpl) EndpointStats() []EndpointStats {
+	if c.failover == nil {
+		return nil
+	}
+	return c.failover.stats()
+}
@@ -0,0 +1,95 @@
+package jupag
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFailover(t *testing.T) {
+	var down atomic.Bool
+	down.Store(true)
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer primary.Close()
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer secondary.Close()
+
+	f := newFailover(FailoverConfig{BaseURLs: []string{primary.URL, secondary.URL}, FailureThreshold: 2, RecoveryInterval: time.Millisecond})
+	f.check = func(ctx context.Context, base string) HealthStatus {
+		return HealthStatus{URL: base, Healthy: !down.Load()}
+	}
+	var sent []string
+	send := func(completeUrl string) (*http.Response, error) {
+		sent = append(sent, completeUrl)
+		return http.Get(completeUrl)
+	}
+	do := func() {
+		t.Helper()
+		resp, err := f.do(context.Background(), primary.URL+"/quote", send)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("status = %d, want 200", resp.StatusCode)
+		}
+	}
+
+	// failed over to the secondary until the primary is marked unhealthy
+	do()
+	do()
+	stats := f.stats()
+	if stats[0].Healthy || stats[0].ConsecutiveFailures != 2 || stats[0].Failures != 2 || !stats[1].Healthy || stats[1].Requests != 2 {
+		t.Fatalf("stats after the failures = %+v", stats)
+	}
+	if len(sent) != 4 || sent[2] != primary.URL+"/quote" || sent[3] != secondary.URL+"/quote" {
+		t.Errorf("sent = %q", sent)
+	}
+
+	// the unhealthy primary is tried last, and recovers with its health check
+	sent = nil
+	down.Store(false)
+	time.Sleep(2 * time.Millisecond)
+	do()
+	if len(sent) != 1 || sent[0] != secondary.URL+"/quote" {
+		t.Errorf("sent while unhealthy = %q", sent)
+	}
+	deadline := time.Now().Add(time.Second)
+	for !f.stats()[0].Healthy {
+		if time.Now().After(deadline) {
+			t.Fatal("primary not recovered")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	sent = nil
+	do()
+	if len(sent) != 1 || sent[0] != primary.URL+"/quote" {
+		t.Errorf("sent after the recovery = %q", sent)
+	}
+
+	// a cancelled request is neither a failure nor a success
+	down.Store(true)
+	if resp, err := f.do(context.Background(), primary.URL+"/quote", send); err == nil {
+		resp.Body.Close()
+	}
+	before := f.stats()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := f.do(ctx, primary.URL+"/quote", func(completeUrl string) (*http.Response, error) {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, completeUrl, nil)
+		return http.DefaultClient.Do(req)
+	})
+	if err == nil {
+		t.Fatal("cancelled request succeeded")
+	}
+	if after := f.stats(); after[0] != before[0] || after[1] != before[1] {
+		t.Errorf("stats changed by a cancelled request: %+v, want %+v", after, before)
+	}
+}
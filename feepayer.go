@@ -0,0 +1,99 @@
+package jupag
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+
+	"github.com/ipanardian/go-jup-ag/utils"
+)
+
+// SponsoredSwap is a swap transaction whose fees are paid by another account than the user.
+type SponsoredSwap struct {
+	Transaction          *Transaction
+	Payer                PublicKey   // fee payer, always the first signer
+	Signers              []PublicKey // all the required signers, in signature slot order
+	LastValidBlockHeight uint64
+}
+
+// SwapWithFeePayer builds the swap as a legacy transaction paid by payer, e.g. a sponsor, from the swap instructions.
+// The user and the payer sign it independently with PartialSign. Accounts created by the swap are still funded by
+// the user. It needs a RPC client for the blockhash.
+func (c *JupagImpl) SwapWithFeePayer(ctx context.Context, params SwapParams, payer string) (SponsoredSwap, error) {
+	if c.rpc == nil {
+		return SponsoredSwap{}, ErrNoRPC
+	}
+	payerKey, err := ParsePublicKey(payer)
+	if err != nil {
+		return SponsoredSwap{}, err
+	}
+	if params.AsLegacyTransaction == nil {
+		params.AsLegacyTransaction = utils.Pointer(true)
+	}
+
+	instructions, err := c.SwapInstructions(ctx, params)
+	if err != nil {
+		return SponsoredSwap{}, err
+	}
+	blockhash, lastValidBlockHeight, err := LatestBlockhash(ctx, c.rpc, CommitmentConfirmed)
+	if err != nil {
+		return SponsoredSwap{}, err
+	}
+	tx, err := NewTransaction(payerKey, blockhash, instructions.Instructions(ComposeOptions{})...)
+	if err != nil {
+		return SponsoredSwap{}, fmt.Errorf("failed to compile swap transaction: %w", err)
+	}
+	if err := tx.CheckSize(); err != nil {
+		return SponsoredSwap{}, err
+	}
+
+	return SponsoredSwap{
+		Transaction:          tx,
+		Payer:                payerKey,
+		Signers:              tx.Signers(),
+		LastValidBlockHeight: lastValidBlockHeight,
+	}, nil
+}
+
+// Signers returns the accounts required to sign the transaction, in signature slot order. The first one pays the fees.
+func (tx *Transaction) Signers() []PublicKey {
+	n := min(int(tx.Message.Header.NumRequiredSignatures), len(tx.Message.AccountKeys))
+	return append([]PublicKey(nil), tx.Message.AccountKeys[:n]...)
+}
+
+// MissingSigners returns the required signers whose signature slot is still empty.
+func (tx *Transaction) MissingSigners() []PublicKey {
+	var missing []PublicKey
+	for i, key := range tx.Signers() {
+		if i >= len(tx.Signatures) || isEmptySignature(tx.Signatures[i]) {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+// PartialSign adds the signatures of the given signers, keeping the signatures already present,
+// so each party of a sponsored transaction can sign on its own. Use MissingSigners to know who is left.
+func (tx *Transaction) PartialSign(signers ...Signer) error {
+	return tx.Sign(signers...)
+}
+
+// VerifySignatures reports whether every required signature is present and valid.
+func (tx *Transaction) VerifySignatures() bool {
+	message := tx.Message.Marshal()
+	for i, key := range tx.Signers() {
+		if i >= len(tx.Signatures) || !ed25519.Verify(key[:], message, tx.Signatures[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isEmptySignature(sig []byte) bool {
+	for _, b := range sig {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package jupag
+
+import (
+	"context"
+	"sync"
+)
+
+// flightGroup deduplicates concurrent calls sharing the same key, like golang.org/x/sync/singleflight.
+type flightGroup[T any] struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall[T]
+}
+
+type flightCall[T any] struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int // callers still waiting for the result
+	val     T
+	err     error
+}
+
+// do executes fn once for all the concurrent callers with the same key. The shared call keeps the values of the
+// context of the first caller and is canceled once all the callers have given up, each caller returns when its
+// own ctx is done.
+func (g *flightGroup[T]) do(ctx context.Context, key string, fn func(context.Context) (T, error)) (T, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall[T])
+	}
+	call, ok := g.calls[key]
+	if ok {
+		call.waiters++
+	} else {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		call = &flightCall[T]{done: make(chan struct{}), cancel: cancel, waiters: 1}
+		g.calls[key] = call
+		go g.run(callCtx, key, call, fn)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.val, call.err
+	case <-ctx.Done():
+	}
+
+	g.mu.Lock()
+	call.waiters--
+	if call.waiters == 0 {
+		call.cancel()
+		if g.calls[key] == call {
+			delete(g.calls, key) // later callers start a new call rather than joining the canceled one
+		}
+	}
+	g.mu.Unlock()
+
+	var zero T
+	return zero, ctx.Err()
+}
+
+func (g *flightGroup[T]) run(ctx context.Context, key string, call *flightCall[T], fn func(context.Context) (T, error)) {
+	call.val, call.err = fn(ctx)
+
+	g.mu.Lock()
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+	g.mu.Unlock()
+
+	close(call.done)
+	call.cancel()
+}
@@ -0,0 +1,110 @@
+package jupag
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFlightGroupShared(t *testing.T) {
+	var g flightGroup[int]
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (int, error) {
+		calls.Add(1)
+		<-release
+		return 42, nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]int, 5)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = g.do(context.Background(), "k", fn)
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls.Load() != 1 {
+		t.Errorf("fn called %d times, want 1", calls.Load())
+	}
+	for i, v := range results {
+		if v != 42 {
+			t.Errorf("caller %d got %d, want 42", i, v)
+		}
+	}
+}
+
+func TestFlightGroupCancel(t *testing.T) {
+	var g flightGroup[int]
+	started := make(chan struct{})
+	canceled := make(chan struct{})
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (int, error) {
+		close(started)
+		select {
+		case <-ctx.Done():
+			close(canceled)
+			return 0, ctx.Err()
+		case <-release:
+			return 42, nil
+		}
+	}
+
+	// the first caller gives up, the call goes on for the second one
+	first, cancelFirst := context.WithCancel(context.Background())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := g.do(first, "k", fn)
+		firstErr <- err
+	}()
+	<-started
+	second := make(chan int, 1)
+	go func() {
+		v, _ := g.do(context.Background(), "k", fn)
+		second <- v
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancelFirst()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("first caller error = %v, want context.Canceled", err)
+	}
+	select {
+	case <-canceled:
+		t.Fatal("shared call canceled while a caller is still waiting")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+	if v := <-second; v != 42 {
+		t.Errorf("second caller got %d, want 42", v)
+	}
+
+	// the only caller gives up, the call is canceled
+	started = make(chan struct{})
+	canceled = make(chan struct{})
+	release = make(chan struct{})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := g.do(ctx, "k", fn)
+		done <- err
+	}()
+	<-started
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("shared call not canceled once all the callers gave up")
+	}
+}
@@ -0,0 +1,124 @@
+package jupag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var ErrStaleQuote = errors.New("quote is stale")
+
+// slotDuration is the target duration of a Solana slot.
+const slotDuration = 400 * time.Millisecond
+
+// SlotSource returns the current slot.
+type SlotSource interface {
+	Slot(ctx context.Context) (uint64, error)
+}
+
+// RPCSlotSource returns the current slot from a Solana RPC node.
+type RPCSlotSource struct {
+	rpc        RPCClient
+	commitment Commitment
+}
+
+// NewRPCSlotSource returns a slot source calling getSlot with the processed commitment.
+func NewRPCSlotSource(rpc RPCClient) *RPCSlotSource {
+	return &RPCSlotSource{rpc: rpc, commitment: CommitmentProcessed}
+}
+
+// Slot implements SlotSource.
+func (s *RPCSlotSource) Slot(ctx context.Context) (uint64, error) {
+	var slot uint64
+	if err := s.rpc.Call(ctx, "getSlot", []any{map[string]any{"commitment": s.commitment}}, &slot); err != nil {
+		return 0, fmt.Errorf("failed to get slot: %w", err)
+	}
+	return slot, nil
+}
+
+// SlotEstimator extrapolates the current slot from a reference slot at the target slot duration,
+// resynchronizing from an underlying source every interval. It saves an RPC call per check.
+type SlotEstimator struct {
+	source   SlotSource
+	interval time.Duration
+
+	mu     sync.Mutex
+	slot   uint64
+	syncAt time.Time
+}
+
+// NewSlotEstimator returns an estimator resynchronized from source every interval, default: 30s.
+func NewSlotEstimator(source SlotSource, interval time.Duration) *SlotEstimator {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	return &SlotEstimator{source: source, interval: interval}
+}
+
+// Observe sets the reference slot, e.g. from the context slot of a fresh API response.
+func (e *SlotEstimator) Observe(slot uint64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if slot > e.estimate() {
+		e.slot, e.syncAt = slot, time.Now()
+	}
+}
+
+// Slot implements SlotSource.
+func (e *SlotEstimator) Slot(ctx context.Context) (uint64, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.syncAt.IsZero() || time.Since(e.syncAt) >= e.interval {
+		slot, err := e.source.Slot(ctx)
+		if err != nil {
+			if e.syncAt.IsZero() {
+				return 0, err
+			}
+			return e.estimate(), nil
+		}
+		e.slot, e.syncAt = slot, time.Now()
+	}
+	return e.estimate(), nil
+}
+
+func (e *SlotEstimator) estimate() uint64 {
+	if e.syncAt.IsZero() {
+		return 0
+	}
+	return e.slot + uint64(time.Since(e.syncAt)/slotDuration)
+}
+
+// staleQuoteGuard rejects quotes older than maxSlots.
+type staleQuoteGuard struct {
+	maxSlots uint64
+	source   SlotSource
+}
+
+// CheckQuoteFreshness returns ErrStaleQuote when the context slot of the route is more than the configured
+// number of slots behind the current slot. Routes without context slot are considered fresh.
+// It returns nil when the guard isn't configured.
+func (c *JupagImpl) CheckQuoteFreshness(ctx context.Context, route Route) error {
+	if c.staleGuard == nil || route.ContextSlot == 0 {
+		return nil
+	}
+
+	source := c.staleGuard.source
+	if source == nil {
+		if c.rpc == nil {
+			return ErrNoRPC
+		}
+		source = NewRPCSlotSource(c.rpc)
+	}
+	slot, err := source.Slot(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check quote freshness: %w", err)
+	}
+
+	if slot > route.ContextSlot && slot-route.ContextSlot > c.staleGuard.maxSlots {
+		return fmt.Errorf("%w: context slot %d is %d slots behind %d", ErrStaleQuote, route.ContextSlot, slot-route.ContextSlot, slot)
+	}
+	return nil
+}
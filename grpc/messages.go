@@ -0,0 +1,360 @@
+package grpc
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	jupag "github.com/ipanardian/go-jup-ag"
+)
+
+// The messages of jupag.proto, converted from and to the client types.
+
+func decodeQuoteRequest(b []byte) (jupag.QuoteParams, error) {
+	var p jupag.QuoteParams
+	err := decodeFields(b, func(num int, f *field) (bool, error) {
+		var err error
+		switch num {
+		case 1:
+			p.InputMint, err = f.string()
+		case 2:
+			p.OutputMint, err = f.string()
+		case 3:
+			p.Amount, err = f.uint()
+		case 4:
+			p.SwapMode, err = f.string()
+		case 5:
+			p.SlippageBps, err = f.uint()
+		case 6:
+			p.FeeBps, err = f.uint()
+		case 7:
+			p.OnlyDirectRoutes, err = f.bool()
+		case 8:
+			p.AsLegacyTransaction, err = f.bool()
+		case 9:
+			p.UserPublicKey, err = f.string()
+		case 10:
+			p.MaxAccounts, err = f.uint()
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+	return p, err
+}
+
+func encodeQuoteResponse(quote jupag.QuoteResponse) []byte {
+	var e encoder
+	for _, route := range quote {
+		e.message(1, func(e *encoder) { encodeRoute(e, route) })
+	}
+	return e.b
+}
+
+func encodeRoute(e *encoder, r jupag.Route) {
+	e.string(1, r.InAmount.String())
+	e.string(2, r.OutAmount.String())
+	e.string(3, decimalString(r.PriceImpactPctDecimal, r.PriceImpactPct))
+	for _, m := range r.MarketInfos {
+		e.message(4, func(e *encoder) { encodeMarketInfo(e, m) })
+	}
+	e.string(5, r.Amount.String())
+	e.int(6, r.SlippageBps)
+	e.string(7, r.OtherAmountThreshold.String())
+	e.string(8, r.SwapMode)
+	e.uint(9, r.ContextSlot)
+}
+
+func encodeMarketInfo(e *encoder, m jupag.MarketInfo) {
+	e.string(1, m.ID)
+	e.string(2, m.Label)
+	e.string(3, m.InputMint)
+	e.string(4, m.OutputMint)
+	e.bool(5, m.NotEnoughLiquidity)
+	e.string(6, m.InAmount.String())
+	e.string(7, m.OutAmount.String())
+	e.string(8, decimalString(m.PriceImpactPctDecimal, m.PriceImpactPct))
+	if m.LpFee != nil {
+		e.message(9, func(e *encoder) { encodeFee(e, *m.LpFee) })
+	}
+	if m.PlatformFee != nil {
+		e.message(10, func(e *encoder) { encodeFee(e, *m.PlatformFee) })
+	}
+}
+
+func encodeFee(e *encoder, f jupag.Fee) {
+	e.string(1, f.Amount.String())
+	e.string(2, f.Mint)
+	e.string(3, decimalString(f.PctDecimal, f.Pct))
+}
+
+func decodeRoute(b []byte) (jupag.Route, error) {
+	var r jupag.Route
+	err := decodeFields(b, func(num int, f *field) (bool, error) {
+		switch num {
+		case 1:
+			return true, amountField(f, &r.InAmount)
+		case 2:
+			return true, amountField(f, &r.OutAmount)
+		case 3:
+			return true, decimalField(f, &r.PriceImpactPctDecimal, &r.PriceImpactPct)
+		case 4:
+			b, err := f.bytes()
+			if err != nil {
+				return true, err
+			}
+			m, err := decodeMarketInfo(b)
+			r.MarketInfos = append(r.MarketInfos, m)
+			return true, err
+		case 5:
+			return true, amountField(f, &r.Amount)
+		case 6:
+			var err error
+			r.SlippageBps, err = f.int()
+			return true, err
+		case 7:
+			return true, amountField(f, &r.OtherAmountThreshold)
+		case 8:
+			var err error
+			r.SwapMode, err = f.string()
+			return true, err
+		case 9:
+			var err error
+			r.ContextSlot, err = f.uint()
+			return true, err
+		}
+		return false, nil
+	})
+	return r, err
+}
+
+func decodeMarketInfo(b []byte) (jupag.MarketInfo, error) {
+	var m jupag.MarketInfo
+	err := decodeFields(b, func(num int, f *field) (bool, error) {
+		var err error
+		switch num {
+		case 1:
+			m.ID, err = f.string()
+		case 2:
+			m.Label, err = f.string()
+		case 3:
+			m.InputMint, err = f.string()
+		case 4:
+			m.OutputMint, err = f.string()
+		case 5:
+			m.NotEnoughLiquidity, err = f.bool()
+		case 6:
+			err = amountField(f, &m.InAmount)
+		case 7:
+			err = amountField(f, &m.OutAmount)
+		case 8:
+			err = decimalField(f, &m.PriceImpactPctDecimal, &m.PriceImpactPct)
+		case 9, 10:
+			var b []byte
+			if b, err = f.bytes(); err != nil {
+				return true, err
+			}
+			fee, err := decodeFee(b)
+			if num == 9 {
+				m.LpFee = &fee
+			} else {
+				m.PlatformFee = &fee
+			}
+			return true, err
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+	return m, err
+}
+
+func decodeFee(b []byte) (jupag.Fee, error) {
+	var fee jupag.Fee
+	err := decodeFields(b, func(num int, f *field) (bool, error) {
+		var err error
+		switch num {
+		case 1:
+			err = amountField(f, &fee.Amount)
+		case 2:
+			fee.Mint, err = f.string()
+		case 3:
+			err = decimalField(f, &fee.PctDecimal, &fee.Pct)
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+	return fee, err
+}
+
+func decodeSwapRequest(b []byte) (jupag.SwapParams, error) {
+	var p jupag.SwapParams
+	err := decodeFields(b, func(num int, f *field) (bool, error) {
+		var err error
+		switch num {
+		case 1:
+			var b []byte
+			if b, err = f.bytes(); err == nil {
+				p.Route, err = decodeRoute(b)
+			}
+		case 2:
+			p.UserPublicKey, err = f.string()
+		case 3:
+			var v bool
+			v, err = f.bool()
+			p.WrapUnwrapSol = &v
+		case 4:
+			p.FeeAccount, err = f.string()
+		case 5:
+			var v bool
+			v, err = f.bool()
+			p.AsLegacyTransaction = &v
+		case 6:
+			var v int64
+			v, err = f.int()
+			p.ComputeUnitPriceMicroLamports = jupag.FixedFee(v)
+		case 7:
+			p.DestinationWallet, err = f.string()
+		case 8:
+			p.DestinationTokenAccount, err = f.string()
+		case 9:
+			var auto bool
+			if auto, err = f.bool(); auto {
+				p.ComputeUnitPriceMicroLamports = jupag.FeeAuto
+			}
+		case 10:
+			p.TrackingAccount, err = f.string()
+		case 11:
+			p.BlockhashSlotsToExpiry, err = f.uint()
+		case 12:
+			p.CorrectLastValidBlockHeight, err = f.bool()
+		case 13:
+			var b []byte
+			if b, err = f.bytes(); err == nil {
+				p.DynamicSlippage, err = decodeDynamicSlippage(b)
+			}
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+	return p, err
+}
+
+func encodeSwapResponse(swap jupag.SwapResponse) []byte {
+	var e encoder
+	e.string(1, swap.SwapTransaction)
+	e.uint(2, swap.LastValidBlockHeight)
+	e.int(3, int64(swap.PrioritizationFeeLamports))
+	e.int(4, int64(swap.ComputeUnitLimit))
+	if r := swap.DynamicSlippageReport; r != nil {
+		e.message(5, func(e *encoder) {
+			e.int(1, int64(r.SlippageBps))
+			e.string(2, r.OtherAmount.String())
+			e.int(3, int64(r.SimulatedIncurredSlippageBps))
+			e.string(4, r.AmplificationRatio.String())
+			e.string(5, r.CategoryName)
+			e.int(6, int64(r.HeuristicMaxSlippageBps))
+		})
+	}
+	return e.b
+}
+
+func decodeDynamicSlippage(b []byte) (*jupag.DynamicSlippage, error) {
+	var d jupag.DynamicSlippage
+	err := decodeFields(b, func(num int, f *field) (bool, error) {
+		var err error
+		switch num {
+		case 1:
+			d.MinBps, err = f.uint()
+		case 2:
+			d.MaxBps, err = f.uint()
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+	return &d, err
+}
+
+func decodePriceRequest(b []byte) (jupag.PriceParams, error) {
+	var (
+		p   jupag.PriceParams
+		ids []string
+	)
+	err := decodeFields(b, func(num int, f *field) (bool, error) {
+		var err error
+		switch num {
+		case 1:
+			var id string
+			id, err = f.string()
+			ids = append(ids, id)
+		case 2:
+			p.VsToken, err = f.string()
+		default:
+			return false, nil
+		}
+		return true, err
+	})
+	p.IDs = strings.Join(ids, ",")
+	return p, err
+}
+
+func encodePriceResponse(prices jupag.PriceMap) []byte {
+	ids := make([]string, 0, len(prices))
+	for id := range prices {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var e encoder
+	for _, id := range ids {
+		p := prices[id]
+		// map entries are messages of a key and a value field
+		e.message(1, func(e *encoder) {
+			e.string(1, id)
+			e.message(2, func(e *encoder) {
+				e.string(1, p.ID)
+				e.string(2, p.MintSymbol)
+				e.string(3, p.VsToken)
+				e.string(4, p.VsTokenSymbol)
+				e.string(5, p.Price)
+				e.string(6, p.Type)
+			})
+		})
+	}
+	return e.b
+}
+
+func amountField(f *field, a *jupag.Amount) error {
+	s, err := f.string()
+	if err != nil || s == "" {
+		return err
+	}
+	if *a, err = jupag.ParseAmount(s); err != nil {
+		return fmt.Errorf("field %d: %w", f.num, err)
+	}
+	return nil
+}
+
+// decimalField decodes a decimal string field into both the exact and the float64 values of a number.
+func decimalField(f *field, d *jupag.Decimal, v *float64) error {
+	s, err := f.string()
+	if err != nil || s == "" {
+		return err
+	}
+	if *d, err = jupag.ParseDecimal(s); err != nil {
+		return fmt.Errorf("field %d: %w", f.num, err)
+	}
+	*v = d.Float64()
+	return nil
+}
+
+// decimalString returns the exact value of a number when set, the float64 value otherwise.
+func decimalString(d jupag.Decimal, v float64) string {
+	if d == (jupag.Decimal{}) {
+		return jupag.NewDecimalFromFloat(v).String()
+	}
+	return d.String()
+}
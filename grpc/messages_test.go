@@ -0,0 +1,200 @@
+package grpc
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	jupag "github.com/ipanardian/go-jup-ag"
+)
+
+// The fixtures are the proto3 encoding of the messages of jupag.proto, computed by hand.
+
+// route encodes a Route with one market, the zero amounts and numbers are written as "0".
+const routeHex = "" +
+	"0a0431303030" + // in_amount "1000"
+	"120135" + // out_amount "5"
+	"1a06302e30303235" + // price_impact_pct "0.0025"
+	"2219" + // market_infos, 25 bytes
+	"0a016d" + // id "m"
+	"320130" + "3a0130" + "420130" + // in_amount, out_amount, price_impact_pct "0"
+	"4a0b" + "0a0133" + "120163" + "1a03302e35" + // lp_fee {amount "3", mint "c", pct "0.5"}
+	"2a0431303030" + // amount "1000"
+	"3032" + // slippage_bps 50
+	"3a0134" + // other_amount_threshold "4"
+	"42074578616374496e" + // swap_mode "ExactIn"
+	"48ac02" // context_slot 300
+
+func fromHex(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func testRoute() jupag.Route {
+	return jupag.Route{
+		InAmount:       jupag.NewAmount(1000),
+		OutAmount:      jupag.NewAmount(5),
+		PriceImpactPct: 0.0025,
+		MarketInfos: []jupag.MarketInfo{{
+			ID:    "m",
+			LpFee: &jupag.Fee{Amount: jupag.NewAmount(3), Mint: "c", Pct: 0.5},
+		}},
+		Amount:               jupag.NewAmount(1000),
+		SlippageBps:          50,
+		OtherAmountThreshold: jupag.NewAmount(4),
+		SwapMode:             "ExactIn",
+		ContextSlot:          300,
+	}
+}
+
+func TestDecodeQuoteRequest(t *testing.T) {
+	b := fromHex(t, ""+
+		"0a0161"+ // input_mint "a"
+		"120162"+ // output_mint "b"
+		"18e807"+ // amount 1000
+		"22074578616374496e"+ // swap_mode "ExactIn"
+		"2832"+ // slippage_bps 50
+		"3801"+ // only_direct_routes true
+		"5040"+ // max_accounts 64
+		"7801"+ // unknown varint field 15
+		"8201027a7a") // unknown bytes field 16
+
+	p, err := decodeQuoteRequest(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := jupag.QuoteParams{InputMint: "a", OutputMint: "b", Amount: 1000, SwapMode: "ExactIn", SlippageBps: 50, OnlyDirectRoutes: true, MaxAccounts: 64}
+	if p != want {
+		t.Errorf("decodeQuoteRequest = %+v, want %+v", p, want)
+	}
+}
+
+func TestQuoteResponseRoundTrip(t *testing.T) {
+	want := fromHex(t, "0a43"+routeHex)
+	got := encodeQuoteResponse(jupag.QuoteResponse{testRoute()})
+	if !bytes.Equal(got, want) {
+		t.Fatalf("encodeQuoteResponse = %x, want %x", got, want)
+	}
+
+	r, err := decodeRoute(fromHex(t, routeHex))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.PriceImpactPct != 0.0025 || r.PriceImpactPctDecimal.String() != "0.0025" || r.ContextSlot != 300 ||
+		r.MarketInfos[0].LpFee.Pct != 0.5 || r.OtherAmountThreshold.String() != "4" {
+		t.Errorf("decodeRoute = %+v", r)
+	}
+	var e encoder
+	encodeRoute(&e, r)
+	if got := hex.EncodeToString(e.b); got != routeHex {
+		t.Errorf("encodeRoute(decodeRoute) = %s, want %s", got, routeHex)
+	}
+}
+
+func TestDecodeSwapRequest(t *testing.T) {
+	b := fromHex(t, "0a43"+routeHex+
+		"120177"+ // user_public_key "w"
+		"1801"+ // wrap_unwrap_sol true
+		"4801"+ // compute_unit_price_auto true
+		"6a05083210ac02") // dynamic_slippage {min_bps 50, max_bps 300}
+
+	p, err := decodeSwapRequest(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Route.ContextSlot != 300 || p.UserPublicKey != "w" || p.WrapUnwrapSol == nil || !*p.WrapUnwrapSol ||
+		p.AsLegacyTransaction != nil || p.ComputeUnitPriceMicroLamports != jupag.FeeAuto ||
+		p.DynamicSlippage == nil || *p.DynamicSlippage != (jupag.DynamicSlippage{MinBps: 50, MaxBps: 300}) {
+		t.Errorf("decodeSwapRequest = %+v", p)
+	}
+
+	p, err = decodeSwapRequest(fromHex(t, "308827")) // compute_unit_price_micro_lamports 5000
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.ComputeUnitPriceMicroLamports != jupag.FixedFee(5000) {
+		t.Errorf("compute unit price = %q, want 5000", p.ComputeUnitPriceMicroLamports)
+	}
+}
+
+func TestEncodeSwapResponse(t *testing.T) {
+	got := encodeSwapResponse(jupag.SwapResponse{
+		SwapTransaction:           "tx",
+		LastValidBlockHeight:      1000,
+		PrioritizationFeeLamports: 5000,
+		ComputeUnitLimit:          200000,
+		DynamicSlippageReport: &jupag.DynamicSlippageReport{
+			SlippageBps:                  50,
+			SimulatedIncurredSlippageBps: -1,
+			CategoryName:                 "stable",
+			HeuristicMaxSlippageBps:      100,
+		},
+	})
+	want := "" +
+		"0a027478" + // swap_transaction "tx"
+		"10e807" + // last_valid_block_height 1000
+		"188827" + // prioritization_fee_lamports 5000
+		"20c09a0c" + // compute_unit_limit 200000
+		"2a1d" + // dynamic_slippage_report, 29 bytes
+		"0832" + // slippage_bps 50
+		"120130" + // other_amount "0"
+		"18ffffffffffffffffff01" + // simulated_incurred_slippage_bps -1, ten bytes
+		"220130" + // amplification_ratio "0"
+		"2a06737461626c65" + // category_name "stable"
+		"3064" // heuristic_max_slippage_bps 100
+	if hex.EncodeToString(got) != want {
+		t.Errorf("encodeSwapResponse = %x, want %s", got, want)
+	}
+}
+
+func TestPriceRoundTrip(t *testing.T) {
+	p, err := decodePriceRequest(fromHex(t, ""+
+		"0a03534f4c"+ // ids "SOL"
+		"0a04424f4e4b"+ // ids "BONK"
+		"120455534443")) // vs_token "USDC"
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.IDs != "SOL,BONK" || p.VsToken != "USDC" {
+		t.Errorf("decodePriceRequest = %+v", p)
+	}
+
+	got := encodePriceResponse(jupag.PriceMap{
+		"SOL":  {ID: "So1", MintSymbol: "SOL", Price: "150.5"},
+		"BONK": {ID: "b", Price: "1e-5"},
+	})
+	want := "" +
+		"0a11" + "0a04424f4e4b" + "1209" + "0a0162" + "2a0431652d35" + // BONK: {id "b", price "1e-5"}
+		"0a18" + "0a03534f4c" + "1211" + "0a03536f31" + "1203534f4c" + "2a053135302e35" // SOL: {id "So1", mint_symbol "SOL", price "150.5"}
+	if hex.EncodeToString(got) != want {
+		t.Errorf("encodePriceResponse = %x, want %s", got, want)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		hex  string
+		want string
+	}{
+		{"truncated length", "0a0561", "truncated"},
+		{"truncated varint", "18e8", "truncated"},
+		{"field number 0", "0001", "field number 0"},
+		{"wire type mismatch", "0801", "invalid wire type 0 of protobuf field 1"},
+		{"unsupported wire type", "7b", "unsupported protobuf wire type 3"},
+	}
+	for _, tt := range tests {
+		if _, err := decodeQuoteRequest(fromHex(t, tt.hex)); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+
+	if _, err := decodeRoute(fromHex(t, "0a0178")); err == nil || !strings.Contains(err.Error(), "field 1") {
+		t.Errorf("invalid amount: error = %v", err)
+	}
+}
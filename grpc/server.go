@@ -0,0 +1,257 @@
+// Package grpc serves the quotes, swap builds and prices of a jupag client as the gRPC service of jupag.proto,
+// so services in other languages consume the client pipeline with generated stubs: its API key, caches, rate
+// limit, slippage and fee configuration. It implements the gRPC protocol on net/http and has no dependency.
+//
+// gRPC runs on HTTP/2, which net/http serves over TLS, or over cleartext with an h2c handler such as
+// golang.org/x/net/http2/h2c.
+//
+//	srv := &http.Server{Addr: ":8443", Handler: grpc.NewServer(jupag.NewJupag())}
+//	log.Fatal(srv.ListenAndServeTLS("cert.pem", "key.pem"))
+package grpc
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	jupag "github.com/ipanardian/go-jup-ag"
+)
+
+// serviceName is the full name of the service of jupag.proto.
+const serviceName = "jupag.v1.Jupag"
+
+// maxMessageSize is the maximum size of a request message, as the 4MB default of gRPC.
+const maxMessageSize = 4 << 20
+
+// Status codes of gRPC.
+const (
+	codeOK                = 0
+	codeCanceled          = 1
+	codeUnknown           = 2
+	codeInvalidArgument   = 3
+	codeDeadlineExceeded  = 4
+	codeNotFound          = 5
+	codePermissionDenied  = 7
+	codeResourceExhausted = 8
+	codeUnimplemented     = 12
+	codeUnavailable       = 14
+	codeUnauthenticated   = 16
+)
+
+// Server is an http.Handler serving the gRPC service of jupag.proto with a client.
+type Server struct {
+	client  jupag.ContextClient
+	methods map[string]func(ctx context.Context, req []byte) ([]byte, error)
+}
+
+// NewServer returns a server calling client.
+func NewServer(client jupag.ContextClient) *Server {
+	s := &Server{client: client}
+	s.methods = map[string]func(ctx context.Context, req []byte) ([]byte, error){
+		"/" + serviceName + "/Quote": s.quote,
+		"/" + serviceName + "/Swap":  s.swap,
+		"/" + serviceName + "/Price": s.price,
+	}
+	return s
+}
+
+// statusError is an error with a gRPC status code.
+type statusError struct {
+	code int
+	err  error
+}
+
+func (e *statusError) Error() string {
+	return e.err.Error()
+}
+
+func (e *statusError) Unwrap() error {
+	return e.err
+}
+
+func invalidArgument(err error) error {
+	return &statusError{code: codeInvalidArgument, err: err}
+}
+
+func (s *Server) quote(ctx context.Context, req []byte) ([]byte, error) {
+	params, err := decodeQuoteRequest(req)
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	if err := params.Validate(); err != nil {
+		return nil, invalidArgument(err)
+	}
+	quote, err := s.client.QuoteWithContext(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return encodeQuoteResponse(quote), nil
+}
+
+func (s *Server) swap(ctx context.Context, req []byte) ([]byte, error) {
+	params, err := decodeSwapRequest(req)
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	if err := params.Validate(); err != nil {
+		return nil, invalidArgument(err)
+	}
+	swap, err := s.client.SwapWithContext(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return encodeSwapResponse(swap), nil
+}
+
+func (s *Server) price(ctx context.Context, req []byte) ([]byte, error) {
+	params, err := decodePriceRequest(req)
+	if err != nil {
+		return nil, invalidArgument(err)
+	}
+	if err := params.Validate(); err != nil {
+		return nil, invalidArgument(err)
+	}
+	prices, err := s.client.PriceWithContext(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return encodePriceResponse(prices), nil
+}
+
+// ServeHTTP serves a unary call: a length-prefixed request message, answered by a length-prefixed response
+// message and the status in the trailers.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc") {
+		http.Error(w, "gRPC requests only", http.StatusUnsupportedMediaType)
+		return
+	}
+	w.Header().Set("Content-Type", "application/grpc+proto")
+	w.Header().Add("Trailer", "Grpc-Status")
+	w.Header().Add("Trailer", "Grpc-Message")
+
+	method, ok := s.methods[r.URL.Path]
+	if !ok {
+		writeStatus(w, codeUnimplemented, fmt.Sprintf("unknown method %s", r.URL.Path))
+		return
+	}
+
+	ctx := r.Context()
+	if timeout, ok := parseTimeout(r.Header.Get("Grpc-Timeout")); ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	req, err := readMessage(r.Body)
+	if err != nil {
+		writeStatus(w, statusCode(ctx, err), err.Error())
+		return
+	}
+	resp, err := method(ctx, req)
+	if err != nil {
+		writeStatus(w, statusCode(ctx, err), err.Error())
+		return
+	}
+
+	frame := make([]byte, 5, 5+len(resp))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(resp)))
+	w.Write(append(frame, resp...))
+	writeStatus(w, codeOK, "")
+}
+
+// readMessage reads the length-prefixed message of a unary request.
+func readMessage(r io.Reader) ([]byte, error) {
+	var prefix [5]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return nil, invalidArgument(fmt.Errorf("failed to read request message: %w", err))
+	}
+	if prefix[0] != 0 {
+		return nil, &statusError{code: codeUnimplemented, err: errors.New("compressed messages are not supported")}
+	}
+	n := binary.BigEndian.Uint32(prefix[1:])
+	if n > maxMessageSize {
+		return nil, &statusError{code: codeResourceExhausted, err: fmt.Errorf("request message of %d bytes exceeds %d bytes", n, maxMessageSize)}
+	}
+	msg := make([]byte, n)
+	if _, err := io.ReadFull(r, msg); err != nil {
+		return nil, invalidArgument(fmt.Errorf("failed to read request message: %w", err))
+	}
+	return msg, nil
+}
+
+func writeStatus(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Grpc-Status", strconv.Itoa(code))
+	if message != "" {
+		// the message is percent-encoded as the header values are ASCII
+		w.Header().Set("Grpc-Message", strings.ReplaceAll(url.QueryEscape(message), "+", "%20"))
+	}
+}
+
+// statusCode maps an error of the client to a gRPC status code.
+func statusCode(ctx context.Context, err error) int {
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return statusErr.code
+	}
+	switch {
+	case errors.Is(err, jupag.ErrInvalidParams):
+		return codeInvalidArgument
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return codeDeadlineExceeded
+	case errors.Is(err, context.Canceled) || ctx.Err() != nil:
+		return codeCanceled
+	case errors.Is(err, jupag.ErrDegraded), errors.Is(err, jupag.ErrCircuitOpen):
+		return codeUnavailable
+	}
+
+	var apiErr *jupag.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusBadRequest, http.StatusUnprocessableEntity:
+			return codeInvalidArgument
+		case http.StatusUnauthorized:
+			return codeUnauthenticated
+		case http.StatusForbidden:
+			return codePermissionDenied
+		case http.StatusNotFound:
+			return codeNotFound
+		case http.StatusTooManyRequests:
+			return codeResourceExhausted
+		}
+		if apiErr.StatusCode >= http.StatusInternalServerError {
+			return codeUnavailable
+		}
+	}
+	return codeUnknown
+}
+
+// parseTimeout parses a grpc-timeout header, e.g. "100m" for 100 milliseconds.
+func parseTimeout(v string) (time.Duration, bool) {
+	if len(v) < 2 {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(v[:len(v)-1], 10, 64)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	units := map[byte]time.Duration{
+		'H': time.Hour,
+		'M': time.Minute,
+		'S': time.Second,
+		'm': time.Millisecond,
+		'u': time.Microsecond,
+		'n': time.Nanosecond,
+	}
+	unit, ok := units[v[len(v)-1]]
+	if !ok {
+		return 0, false
+	}
+	return time.Duration(n) * unit, true
+}
@@ -0,0 +1,193 @@
+package grpc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Protobuf wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errTruncated = errors.New("truncated protobuf message")
+
+// encoder appends protobuf fields to a buffer, omitting the zero values as in proto3.
+type encoder struct {
+	b []byte
+}
+
+func (e *encoder) tag(num, typ int) {
+	e.b = binary.AppendUvarint(e.b, uint64(num)<<3|uint64(typ))
+}
+
+func (e *encoder) uint(num int, v uint64) {
+	if v != 0 {
+		e.tag(num, wireVarint)
+		e.b = binary.AppendUvarint(e.b, v)
+	}
+}
+
+// int encodes an int64 field, negative values take ten bytes as in protobuf.
+func (e *encoder) int(num int, v int64) {
+	e.uint(num, uint64(v))
+}
+
+func (e *encoder) bool(num int, v bool) {
+	if v {
+		e.uint(num, 1)
+	}
+}
+
+func (e *encoder) string(num int, v string) {
+	if v != "" {
+		e.tag(num, wireBytes)
+		e.b = binary.AppendUvarint(e.b, uint64(len(v)))
+		e.b = append(e.b, v...)
+	}
+}
+
+// message encodes a nested message, always present even when empty.
+func (e *encoder) message(num int, fn func(e *encoder)) {
+	var m encoder
+	fn(&m)
+	e.tag(num, wireBytes)
+	e.b = binary.AppendUvarint(e.b, uint64(len(m.b)))
+	e.b = append(e.b, m.b...)
+}
+
+// decoder reads the fields of a protobuf message.
+type decoder struct {
+	b []byte
+}
+
+// next returns the number and wire type of the next field, false at the end of the message.
+func (d *decoder) next() (int, int, bool, error) {
+	if len(d.b) == 0 {
+		return 0, 0, false, nil
+	}
+	key, err := d.varint()
+	if err != nil {
+		return 0, 0, false, err
+	}
+	if key>>3 == 0 || key>>3 > math.MaxInt32 {
+		return 0, 0, false, fmt.Errorf("invalid protobuf field number %d", key>>3)
+	}
+	return int(key >> 3), int(key & 7), true, nil
+}
+
+func (d *decoder) varint() (uint64, error) {
+	v, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		return 0, errTruncated
+	}
+	d.b = d.b[n:]
+	return v, nil
+}
+
+func (d *decoder) bytes() ([]byte, error) {
+	n, err := d.varint()
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(d.b)) {
+		return nil, errTruncated
+	}
+	v := d.b[:n]
+	d.b = d.b[n:]
+	return v, nil
+}
+
+// skip discards the value of a field of the wire type, e.g. a field of a newer version of the messages.
+func (d *decoder) skip(typ int) error {
+	var n int
+	switch typ {
+	case wireVarint:
+		_, err := d.varint()
+		return err
+	case wireBytes:
+		_, err := d.bytes()
+		return err
+	case wireFixed64:
+		n = 8
+	case wireFixed32:
+		n = 4
+	default:
+		return fmt.Errorf("unsupported protobuf wire type %d", typ)
+	}
+	if len(d.b) < n {
+		return errTruncated
+	}
+	d.b = d.b[n:]
+	return nil
+}
+
+// decodeFields calls fn with each field of the message, fn returns false for the fields it doesn't know.
+// Wire types mismatching the field are rejected by the typed readers.
+func decodeFields(b []byte, fn func(num int, f *field) (bool, error)) error {
+	d := &decoder{b: b}
+	for {
+		num, typ, ok, err := d.next()
+		if err != nil || !ok {
+			return err
+		}
+		f := &field{d: d, num: num, typ: typ}
+		known, err := fn(num, f)
+		if err != nil {
+			return err
+		}
+		if !known {
+			if err := d.skip(typ); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// field is a field of a message being decoded, read by one of its methods.
+type field struct {
+	d   *decoder
+	num int
+	typ int
+}
+
+func (f *field) expect(typ int) error {
+	if f.typ != typ {
+		return fmt.Errorf("invalid wire type %d of protobuf field %d", f.typ, f.num)
+	}
+	return nil
+}
+
+func (f *field) uint() (uint64, error) {
+	if err := f.expect(wireVarint); err != nil {
+		return 0, err
+	}
+	return f.d.varint()
+}
+
+func (f *field) int() (int64, error) {
+	v, err := f.uint()
+	return int64(v), err
+}
+
+func (f *field) bool() (bool, error) {
+	v, err := f.uint()
+	return v != 0, err
+}
+
+func (f *field) bytes() ([]byte, error) {
+	if err := f.expect(wireBytes); err != nil {
+		return nil, err
+	}
+	return f.d.bytes()
+}
+
+func (f *field) string() (string, error) {
+	b, err := f.bytes()
+	return string(b), err
+}
@@ -0,0 +1,40 @@
+package jupag
+
+import (
+	"context"
+	"net/http"
+)
+
+// CorrelationIDHeader is the header carrying the correlation ID of a request.
+const CorrelationIDHeader = "X-Request-Id"
+
+type headersKey struct{}
+
+// ContextWithHeaders returns a context setting headers on the API requests made with it, e.g. A/B flags or custom Jupiter headers.
+// They are merged into the headers of the parent context and override the default headers of the client.
+func ContextWithHeaders(ctx context.Context, headers http.Header) context.Context {
+	return withHeaders(ctx, headers)
+}
+
+// ContextWithCorrelationID returns a context sending id in the X-Request-Id header of the API requests made with it.
+// The id is also logged with the requests when WithLogger is set.
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return withHeaders(ctx, http.Header{CorrelationIDHeader: []string{id}})
+}
+
+// withHeaders returns a context carrying headers to set on the requests made with it.
+func withHeaders(ctx context.Context, headers http.Header) context.Context {
+	merged := headersFromContext(ctx).Clone()
+	if merged == nil {
+		merged = make(http.Header, len(headers))
+	}
+	for key, values := range headers {
+		merged[http.CanonicalHeaderKey(key)] = values
+	}
+	return context.WithValue(ctx, headersKey{}, merged)
+}
+
+func headersFromContext(ctx context.Context) http.Header {
+	headers, _ := ctx.Value(headersKey{}).(http.Header)
+	return headers
+}
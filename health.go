@@ -0,0 +1,66 @@
+package jupag
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HealthStatus is the result of a health check.
+type HealthStatus struct {
+	URL        string
+	Healthy    bool
+	StatusCode int
+	Latency    time.Duration
+	Version    string // API version reported by the server, empty when it doesn't report it
+	CheckedAt  time.Time
+	Err        error
+}
+
+// Ping checks the swap API and returns its latency.
+func (c *JupagImpl) Ping(ctx context.Context) (time.Duration, error) {
+	status := c.healthCheck(ctx, c.baseURL(APISwap))
+	return status.Latency, status.Err
+}
+
+// Health checks the swap API with a lightweight request, "/health" on a self-hosted instance.
+// The error is also set in the status, which is returned in both cases.
+func (c *JupagImpl) Health(ctx context.Context) (HealthStatus, error) {
+	status := c.healthCheck(ctx, c.baseURL(APISwap))
+	return status, status.Err
+}
+
+// healthCheck requests the health endpoint of a base URL, bypassing the degraded mode. It is sent like the other
+// requests, with the API key, the proxy and the dialer, and isn't retried unless the health policy is set.
+func (c *JupagImpl) healthCheck(ctx context.Context, base string) HealthStatus {
+	status := HealthStatus{URL: c.endpointAt(base, EndpointHealth), CheckedAt: time.Now()}
+
+	resp, err := c.send(ctx, EndpointHealth, http.MethodGet, status.URL, nil)
+	status.Latency = time.Since(status.CheckedAt)
+	if err != nil {
+		status.Err = err
+		return status
+	}
+	defer resp.Body.Close()
+
+	status.StatusCode = resp.StatusCode
+	if resp.StatusCode != http.StatusOK {
+		status.Err = newAPIError(resp)
+		return status
+	}
+	status.Healthy = true
+
+	status.Version = resp.Header.Get("X-Api-Version")
+	if status.Version == "" {
+		var body struct {
+			Version string `json:"version"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		if json.Unmarshal(data, &body) == nil {
+			status.Version = body.Version
+		}
+	}
+	return status
+}
@@ -0,0 +1,44 @@
+package jupag
+
+import (
+	"context"
+	"time"
+)
+
+type hedgeResult[T any] struct {
+	value T
+	err   error
+}
+
+// hedge calls fn and, if it hasn't returned after delay and allow permits it, calls it a second time.
+// The first successful result wins and cancels the other call, the last error is returned when both fail.
+func hedge[T any](ctx context.Context, delay time.Duration, allow func() bool, fn func(ctx context.Context) (T, error)) (T, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan hedgeResult[T], 2)
+	call := func(ctx context.Context) {
+		v, err := fn(ctx)
+		results <- hedgeResult[T]{v, err}
+	}
+	go call(ctx)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	pending := 1
+	for {
+		select {
+		case <-timer.C:
+			if allow() {
+				pending++
+				go call(withRateReserved(ctx))
+			}
+		case r := <-results:
+			pending--
+			if r.err == nil || pending == 0 {
+				return r.value, r.err
+			}
+		}
+	}
+}
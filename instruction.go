@@ -0,0 +1,76 @@
+package jupag
+
+import (
+	"encoding/binary"
+)
+
+// Instruction is an instruction referencing its accounts by public key.
+type Instruction struct {
+	ProgramID PublicKey     `json:"programId"`
+	Accounts  []AccountMeta `json:"accounts"`
+	Data      []byte        `json:"data"` // base64 encoded in JSON
+}
+
+// AccountMeta is an account of an instruction.
+type AccountMeta struct {
+	PublicKey  PublicKey `json:"pubkey"`
+	IsSigner   bool      `json:"isSigner"`
+	IsWritable bool      `json:"isWritable"`
+}
+
+// System and token program instruction discriminators.
+const (
+	systemTransfer    = 2
+	tokenCloseAccount = 9
+	tokenSyncNative   = 17
+)
+
+// TransferInstruction returns a system program instruction transferring lamports from one account to another.
+func TransferInstruction(from, to PublicKey, lamports uint64) Instruction {
+	data := binary.LittleEndian.AppendUint32(nil, systemTransfer)
+	data = binary.LittleEndian.AppendUint64(data, lamports)
+	return Instruction{
+		ProgramID: SystemProgramID,
+		Accounts: []AccountMeta{
+			{PublicKey: from, IsSigner: true, IsWritable: true},
+			{PublicKey: to, IsWritable: true},
+		},
+		Data: data,
+	}
+}
+
+// SyncNativeInstruction returns a token program instruction updating the amount of a wSOL account to its lamports.
+func SyncNativeInstruction(account PublicKey) Instruction {
+	return Instruction{
+		ProgramID: TokenProgramID,
+		Accounts:  []AccountMeta{{PublicKey: account, IsWritable: true}},
+		Data:      []byte{tokenSyncNative},
+	}
+}
+
+// CloseAccountInstruction returns a token program instruction closing a token account owned by owner,
+// its lamports go to destination. Closing a wSOL account unwraps it.
+func CloseAccountInstruction(account, destination, owner PublicKey) Instruction {
+	return Instruction{
+		ProgramID: TokenProgramID,
+		Accounts: []AccountMeta{
+			{PublicKey: account, IsWritable: true},
+			{PublicKey: destination, IsWritable: true},
+			{PublicKey: owner, IsSigner: true},
+		},
+		Data: []byte{tokenCloseAccount},
+	}
+}
+
+// WrapSOLInstructions returns the instructions wrapping lamports of owner into its existing wSOL account.
+func WrapSOLInstructions(owner, account PublicKey, lamports uint64) []Instruction {
+	return []Instruction{
+		TransferInstruction(owner, account, lamports),
+		SyncNativeInstruction(account),
+	}
+}
+
+// UnwrapSOLInstruction returns the instruction closing the wSOL account of owner, returning its lamports to owner.
+func UnwrapSOLInstruction(owner, account PublicKey) Instruction {
+	return CloseAccountInstruction(account, owner, owner)
+}
@@ -0,0 +1,250 @@
+package jupag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var ErrPriceImpactTooHigh = errors.New("price impact too high")
+
+// SwapIntent is a fluent builder resolving a swap described with symbols and UI amounts into a transaction.
+type SwapIntent struct {
+	client *JupagImpl
+
+	from, to         string
+	amountUI         float64
+	amountRaw        uint64
+	maxImpactPct     float64
+	slippageBps      uint64
+	wallet           string
+	swapMode         string
+	onlyDirect       bool
+	feeBps           uint64
+	feeAccount       string
+	destination      string
+	computeUnitPrice FeeSetting
+	defaults         SlippageLimits // applied after the slippage registry
+}
+
+// PreparedSwap is a swap transaction ready to be signed.
+type PreparedSwap struct {
+	InputMint  string
+	OutputMint string
+	Route      Route
+	SwapResponse
+}
+
+// NewSwap returns a swap intent builder.
+func (c *JupagImpl) NewSwap() *SwapIntent {
+	return &SwapIntent{client: c, swapMode: SwapModeExactIn}
+}
+
+// From sets the input token, as a symbol or a mint address.
+func (s *SwapIntent) From(symbolOrMint string) *SwapIntent {
+	s.from = symbolOrMint
+	return s
+}
+
+// To sets the output token, as a symbol or a mint address.
+func (s *SwapIntent) To(symbolOrMint string) *SwapIntent {
+	s.to = symbolOrMint
+	return s
+}
+
+// AmountUI sets the amount in UI units of the input token (or output token for ExactOut), e.g. 1.5 SOL.
+func (s *SwapIntent) AmountUI(amount float64) *SwapIntent {
+	s.amountUI = amount
+	s.amountRaw = 0
+	return s
+}
+
+// Amount sets the raw amount of the input token (or output token for ExactOut).
+func (s *SwapIntent) Amount(amount uint64) *SwapIntent {
+	s.amountRaw = amount
+	s.amountUI = 0
+	return s
+}
+
+// ExactOut interprets the amount as the amount of output token.
+func (s *SwapIntent) ExactOut() *SwapIntent {
+	s.swapMode = SwapModeExactOut
+	return s
+}
+
+// MaxImpact rejects the swap when the route price impact exceeds the given percentage, e.g. 0.5 for 0.5%.
+func (s *SwapIntent) MaxImpact(pct float64) *SwapIntent {
+	s.maxImpactPct = pct
+	return s
+}
+
+// Slippage sets the slippage tolerance in basis points.
+func (s *SwapIntent) Slippage(bps uint64) *SwapIntent {
+	s.slippageBps = bps
+	return s
+}
+
+// OnlyDirect only uses direct routes.
+func (s *SwapIntent) OnlyDirect() *SwapIntent {
+	s.onlyDirect = true
+	return s
+}
+
+// Wallet sets the base58 public key of the user signing the swap.
+func (s *SwapIntent) Wallet(publicKey string) *SwapIntent {
+	s.wallet = publicKey
+	return s
+}
+
+// Destination sets the wallet receiving the output of the swap.
+func (s *SwapIntent) Destination(publicKey string) *SwapIntent {
+	s.destination = publicKey
+	return s
+}
+
+// PlatformFee charges a fee in basis points, collected in the given fee token account.
+func (s *SwapIntent) PlatformFee(bps uint64, feeAccount string) *SwapIntent {
+	s.feeBps = bps
+	s.feeAccount = feeAccount
+	return s
+}
+
+// ComputeUnitPrice sets the compute unit price in micro lamports.
+func (s *SwapIntent) ComputeUnitPrice(microLamports int64) *SwapIntent {
+	s.computeUnitPrice = FixedFee(microLamports)
+	return s
+}
+
+// Build resolves the tokens, quotes, applies the guards and builds the swap transaction.
+func (s *SwapIntent) Build(ctx context.Context) (*PreparedSwap, error) {
+	if s.wallet == "" {
+		return nil, errors.New("wallet is required")
+	}
+
+	inputMint, err := s.client.ResolveMint(ctx, s.from)
+	if err != nil {
+		return nil, err
+	}
+	outputMint, err := s.client.ResolveMint(ctx, s.to)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := s.amountRaw
+	if amount == 0 {
+		amountMint := inputMint
+		if s.swapMode == SwapModeExactOut {
+			amountMint = outputMint
+		}
+		decimals, err := s.client.Decimals(ctx, amountMint)
+		if err != nil {
+			return nil, err
+		}
+		if amount, err = FromUIAmount(s.amountUI, decimals); err != nil {
+			return nil, err
+		}
+	}
+	if amount == 0 {
+		return nil, errors.New("amount is required")
+	}
+
+	slippageBps, maxImpactPct := s.slippageBps, s.maxImpactPct
+	for _, limits := range s.limits(inputMint, outputMint) {
+		if slippageBps == 0 {
+			slippageBps = limits.SlippageBps
+		}
+		if maxImpactPct == 0 {
+			maxImpactPct = limits.MaxPriceImpactPct
+		}
+	}
+
+	routes, err := s.client.quote(ctx, QuoteParams{
+		InputMint:        inputMint,
+		OutputMint:       outputMint,
+		Amount:           amount,
+		SwapMode:         s.swapMode,
+		SlippageBps:      slippageBps,
+		FeeBps:           s.feeBps,
+		OnlyDirectRoutes: s.onlyDirect,
+	})
+	if err != nil {
+		return nil, err
+	}
+	route, err := routes.GetBestRoute()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkPriceImpact(route, maxImpactPct); err != nil {
+		return nil, err
+	}
+
+	swap, err := s.client.buildSwap(ctx, BestSwapParams{
+		UserPublicKey:        s.wallet,
+		DestinationPublicKey: s.destination,
+		FeeAccount:           s.feeAccount,
+	}, route, s.computeUnitPrice)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PreparedSwap{
+		InputMint:    inputMint,
+		OutputMint:   outputMint,
+		Route:        route,
+		SwapResponse: swap,
+	}, nil
+}
+
+// limits returns the limits applied in order when the slippage or the price impact limit isn't set:
+// the ones of the slippage registry, then the defaults of the intent.
+func (s *SwapIntent) limits(inputMint, outputMint string) []SlippageLimits {
+	if limits, ok := s.client.slippageLimits(inputMint, outputMint); ok {
+		return []SlippageLimits{limits, s.defaults}
+	}
+	return []SlippageLimits{s.defaults}
+}
+
+// Defaults of SimpleSwap.
+const (
+	simpleSwapSlippageBps  = 50
+	simpleSwapMaxImpactPct = 5
+)
+
+// SimpleSwap builds a ready-to-sign transaction swapping an UI amount of a token for another, given as symbols or mints.
+// It uses the limits of the slippage registry when configured, otherwise 0.5% slippage (or the slippage engine
+// when configured) and rejects routes with more than 5% price impact.
+func (c *JupagImpl) SimpleSwap(ctx context.Context, from, to string, uiAmount float64, wallet string) (*PreparedSwap, error) {
+	intent := c.NewSwap().
+		From(from).
+		To(to).
+		AmountUI(uiAmount).
+		Wallet(wallet)
+	intent.defaults.MaxPriceImpactPct = simpleSwapMaxImpactPct
+	if c.slippage == nil {
+		intent.defaults.SlippageBps = simpleSwapSlippageBps
+	}
+	return intent.Build(ctx)
+}
+
+// PriceImpactError is returned when the price impact of a route exceeds the limit, it matches ErrPriceImpactTooHigh.
+type PriceImpactError struct {
+	ImpactPct float64 // price impact of the route in percent
+	MaxPct    float64 // limit in percent
+}
+
+func (e *PriceImpactError) Error() string {
+	return fmt.Sprintf("price impact too high: %.4f%% > %.4f%%", e.ImpactPct, e.MaxPct)
+}
+
+func (e *PriceImpactError) Unwrap() error {
+	return ErrPriceImpactTooHigh
+}
+
+// checkPriceImpact returns a PriceImpactError when the price impact of the route exceeds maxPct, 0 disables it.
+func checkPriceImpact(route Route, maxPct float64) error {
+	if impact := route.PriceImpactPct * 100; maxPct > 0 && impact > maxPct {
+		return &PriceImpactError{ImpactPct: impact, MaxPct: maxPct}
+	}
+	return nil
+}
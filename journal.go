@@ -0,0 +1,85 @@
+package jupag
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// JournalEntry is a record of an executed swap.
+type JournalEntry struct {
+	Time           time.Time `json:"time"`
+	Signature      string    `json:"signature"`
+	InputMint      string    `json:"inputMint"`
+	OutputMint     string    `json:"outputMint"`
+	InputDecimals  uint8     `json:"inputDecimals"`
+	OutputDecimals uint8     `json:"outputDecimals"`
+	InAmount       uint64    `json:"inAmount"`  // raw amount of input token spent
+	OutAmount      uint64    `json:"outAmount"` // raw amount of output token received
+	MidPrice       float64   `json:"midPrice"`  // mid price at execution time, in output tokens per input token
+
+	QuotedOutAmount uint64 `json:"quotedOutAmount,omitempty"` // raw output amount of the quote the swap was built from
+}
+
+// ExecutionPrice returns the achieved price in output tokens per input token (UI units).
+func (e JournalEntry) ExecutionPrice() float64 {
+	if e.InAmount == 0 {
+		return 0
+	}
+	in := ToUIAmount(e.InAmount, e.InputDecimals)
+	out := ToUIAmount(e.OutAmount, e.OutputDecimals)
+	return out / in
+}
+
+// RealizedSlippageBps returns the slippage between the quoted and received output amount, in basis points.
+// It returns false when the quoted amount is unknown.
+func (e JournalEntry) RealizedSlippageBps() (float64, bool) {
+	if e.QuotedOutAmount == 0 {
+		return 0, false
+	}
+	return RealizedSlippageBps(NewAmount(e.QuotedOutAmount), NewAmount(e.OutAmount)), true
+}
+
+// Journal stores executed swaps.
+type Journal interface {
+	Record(entry JournalEntry) error
+	// Entries returns the entries recorded in [from, to), ordered by time.
+	Entries(from, to time.Time) ([]JournalEntry, error)
+}
+
+// MemoryJournal is an in-memory Journal.
+type MemoryJournal struct {
+	mu      sync.RWMutex
+	entries []JournalEntry
+}
+
+// NewMemoryJournal returns an empty in-memory journal.
+func NewMemoryJournal() *MemoryJournal {
+	return &MemoryJournal{}
+}
+
+// Record appends an entry to the journal.
+func (j *MemoryJournal) Record(entry JournalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	i := sort.Search(len(j.entries), func(i int) bool { return j.entries[i].Time.After(entry.Time) })
+	j.entries = append(j.entries, JournalEntry{})
+	copy(j.entries[i+1:], j.entries[i:])
+	j.entries[i] = entry
+	return nil
+}
+
+// Entries returns the entries recorded in [from, to), ordered by time.
+func (j *MemoryJournal) Entries(from, to time.Time) ([]JournalEntry, error) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	result := make([]JournalEntry, 0)
+	for _, e := range j.entries {
+		if !e.Time.Before(from) && e.Time.Before(to) {
+			result = append(result, e)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,59 @@
+package jupag
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// GetJSON sends a GET request with the query params to path and decodes the data of the response into T,
+// enveloped or not, e.g. to call an endpoint the client has no method for. A path relative to the API URL is
+// joined to it. The request goes through the rate limit, retries and failover of the client.
+//
+//	pools, err := jupag.GetJSON[[]Pool](ctx, client, "/pools/v1/list", url.Values{"limit": {"10"}})
+func GetJSON[T any](ctx context.Context, c Jupag, path string, params any) (T, error) {
+	return doJSON[T](ctx, c, http.MethodGet, path, params, nil)
+}
+
+// PostJSON sends payload as JSON to path and decodes the data of the response into T, as GetJSON.
+func PostJSON[T any](ctx context.Context, c Jupag, path string, payload any) (T, error) {
+	return doJSON[T](ctx, c, http.MethodPost, path, nil, payload)
+}
+
+// jsonRequester sends the requests of GetJSON and PostJSON, implemented by *JupagImpl.
+type jsonRequester interface {
+	request(ctx context.Context, method, endpoint string, params, body any) (*http.Response, error)
+	parseResponse(resp *http.Response) (json.RawMessage, error)
+	decodeJSON(data []byte, v any) error
+	resolveURL(path string) string
+}
+
+func doJSON[T any](ctx context.Context, client Jupag, method, path string, params, payload any) (T, error) {
+	var v T
+	c, ok := client.(jsonRequester)
+	if !ok {
+		return v, fmt.Errorf("failed to make request: %T is not a client created by NewJupag", client)
+	}
+	resp, err := c.request(ctx, method, c.resolveURL(path), params, payload)
+	if err != nil {
+		return v, fmt.Errorf("failed to make request: %w", err)
+	}
+	data, err := c.parseResponse(resp)
+	if err != nil {
+		return v, err
+	}
+	if err := c.decodeJSON(data, &v); err != nil {
+		return v, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return v, nil
+}
+
+// resolveURL returns the URL of a path, joined to the API URL unless already absolute.
+func (c *JupagImpl) resolveURL(path string) string {
+	if strings.Contains(path, "://") {
+		return path
+	}
+	return strings.TrimRight(c.apiUrl, "/") + "/" + strings.TrimLeft(path, "/")
+}
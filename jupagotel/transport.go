@@ -0,0 +1,187 @@
+// Package jupagotel instruments the Jupag client with OpenTelemetry tracing.
+//
+//	client := jupag.NewJupag(jupagotel.WithTracing())
+package jupagotel
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+
+	jupag "github.com/ipanardian/go-jup-ag"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/ipanardian/go-jup-ag/jupagotel"
+
+// Query parameters recorded as span attributes.
+var queryAttributes = map[string]string{
+	"inputMint":  "jupiter.input_mint",
+	"outputMint": "jupiter.output_mint",
+	"amount":     "jupiter.amount",
+	"swapMode":   "jupiter.swap_mode",
+	"ids":        "jupiter.ids",
+}
+
+// Config configures the tracing transport.
+type Config struct {
+	TracerProvider trace.TracerProvider          // default: otel.GetTracerProvider()
+	Propagator     propagation.TextMapPropagator // default: otel.GetTextMapPropagator()
+}
+
+// Transport is an http.RoundTripper creating a client span per API call and injecting the trace headers.
+// Spans are annotated with the endpoint, the mints and amount of the request and the context slot of the response.
+type Transport struct {
+	base       http.RoundTripper
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+}
+
+// NewTransport wraps the given transport, base defaults to http.DefaultTransport.
+func NewTransport(base http.RoundTripper, cfg Config) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if cfg.TracerProvider == nil {
+		cfg.TracerProvider = otel.GetTracerProvider()
+	}
+	if cfg.Propagator == nil {
+		cfg.Propagator = otel.GetTextMapPropagator()
+	}
+	return &Transport{
+		base:       base,
+		tracer:     cfg.TracerProvider.Tracer(instrumentationName),
+		propagator: cfg.Propagator,
+	}
+}
+
+// WithTracing returns a client option sending the API calls through a tracing Transport with the global provider.
+func WithTracing() jupag.Option {
+	return WithTracingConfig(Config{})
+}
+
+// WithTracingConfig returns a client option sending the API calls through a tracing Transport, wrapping the
+// transport of the client so the endpoint timeouts, proxy and dialer still apply.
+func WithTracingConfig(cfg Config) jupag.Option {
+	return jupag.WithTransportWrapper(func(base http.RoundTripper) http.RoundTripper {
+		return NewTransport(base, cfg)
+	})
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	attrs := []attribute.KeyValue{
+		attribute.String("http.request.method", req.Method),
+		attribute.String("server.address", req.URL.Host),
+		attribute.String("jupiter.endpoint", req.URL.Path),
+	}
+	query := req.URL.Query()
+	for param, key := range queryAttributes {
+		if v := query.Get(param); v != "" {
+			attrs = append(attrs, attribute.String(key, v))
+		}
+	}
+
+	ctx, span := t.tracer.Start(req.Context(), fmt.Sprintf("jupiter %s %s", req.Method, req.URL.Path),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+
+	req = req.Clone(ctx)
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, resp.Status)
+		span.End()
+		return resp, nil
+	}
+
+	// the span ends with the body, once the caller read the context slot through it
+	resp.Body = &slotBody{body: resp.Body, span: span}
+	return resp, nil
+}
+
+var contextSlotKey = []byte(`"contextSlot"`)
+
+// slotBody passes the response body through to the caller as it is read, recording the first context slot of
+// the JSON on the span, and ends the span on EOF or Close.
+type slotBody struct {
+	body   io.ReadCloser
+	span   trace.Span
+	window []byte // unscanned tail of the body, up to a context slot
+	found  bool
+	once   sync.Once
+}
+
+func (b *slotBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	if !b.found && (n > 0 || err == io.EOF) {
+		b.scan(p[:n], err == io.EOF)
+	}
+	if err == io.EOF {
+		b.end()
+	}
+	return n, err
+}
+
+func (b *slotBody) Close() error {
+	err := b.body.Close()
+	b.end()
+	return err
+}
+
+func (b *slotBody) end() {
+	b.once.Do(func() { b.span.End() })
+}
+
+// scan looks for the context slot in the window and the chunk, keeping the tail that may hold a partial one
+// until eof.
+func (b *slotBody) scan(chunk []byte, eof bool) {
+	b.window = append(b.window, chunk...)
+	i := bytes.Index(b.window, contextSlotKey)
+	if i < 0 {
+		keep := max(len(b.window)-len(contextSlotKey), 0)
+		b.window = append(b.window[:0], b.window[keep:]...)
+		return
+	}
+
+	rest := bytes.TrimLeft(b.window[i+len(contextSlotKey):], " \t\r\n")
+	if len(rest) == 0 {
+		b.window = b.window[i:]
+		return
+	}
+	if rest[0] != ':' {
+		b.window = append(b.window[:0], b.window[i+len(contextSlotKey):]...)
+		return
+	}
+	rest = bytes.TrimLeft(rest[1:], " \t\r\n")
+	end := 0
+	for end < len(rest) && '0' <= rest[end] && rest[end] <= '9' {
+		end++
+	}
+	if end == len(rest) && end < 20 && !eof {
+		b.window = b.window[i:] // the number may continue in the next chunk
+		return
+	}
+	b.found = true
+	b.window = nil
+	if slot, err := strconv.ParseInt(string(rest[:end]), 10, 64); err == nil && slot > 0 {
+		b.span.SetAttributes(attribute.Int64("jupiter.context_slot", slot))
+	}
+}
@@ -0,0 +1,69 @@
+package jupagotel
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+type recordingSpan struct {
+	noop.Span
+	attrs []attribute.KeyValue
+	ended int
+}
+
+func (s *recordingSpan) SetAttributes(kv ...attribute.KeyValue) { s.attrs = append(s.attrs, kv...) }
+func (s *recordingSpan) End(...trace.SpanEndOption)             { s.ended++ }
+
+func TestSlotBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int64 // 0 when no slot is recorded
+	}{
+		{"v6 quote", `{"inAmount":"1","routePlan":[{"percent":100}],"contextSlot":312345678,"timeTaken":0.01}`, 312345678},
+		{"spaces", `{"data": [], "contextSlot" : 42 }`, 42},
+		{"at the end", `{"contextSlot":7`, 7},
+		{"absent", `{"data":[{"slot":5}]}`, 0},
+		{"not a number", `{"contextSlot":"x","other":1}`, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, oneByte := range []bool{false, true} {
+				var r io.Reader = strings.NewReader(tt.body)
+				if oneByte {
+					r = iotest.OneByteReader(r)
+				}
+				span := &recordingSpan{}
+				body := &slotBody{body: io.NopCloser(r), span: span}
+
+				got, err := io.ReadAll(body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				body.Close()
+				if string(got) != tt.body {
+					t.Errorf("body = %q, want %q", got, tt.body)
+				}
+				if span.ended != 1 {
+					t.Errorf("span ended %d times, want once", span.ended)
+				}
+
+				var slot int64
+				for _, kv := range span.attrs {
+					if kv.Key == "jupiter.context_slot" {
+						slot = kv.Value.AsInt64()
+					}
+				}
+				if slot != tt.want {
+					t.Errorf("one byte reads %v: context slot = %d, want %d", oneByte, slot, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+// Package jupagtest provides a configurable in-memory client to unit test code using go-jup-ag without HTTP.
+package jupagtest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	jupag "github.com/ipanardian/go-jup-ag"
+)
+
+var (
+	_ jupag.Quoter      = (*MockClient)(nil)
+	_ jupag.Swapper     = (*MockClient)(nil)
+	_ jupag.Pricer      = (*MockClient)(nil)
+	_ jupag.RouteMapper = (*MockClient)(nil)
+	_ jupag.Jupag       = (*MockClient)(nil)
+)
+
+// ErrNoQuote is returned by MockClient.Quote when no quote is configured for the pair.
+var ErrNoQuote = errors.New("jupagtest: no quote configured")
+
+// Call is a call recorded by MockClient.
+type Call struct {
+	Method string
+	Args   []any
+	Time   time.Time
+}
+
+// MockClient implements Jupag, that is Quoter, Swapper, Pricer and RouteMapper, with canned responses.
+type MockClient struct {
+	mu sync.Mutex
+
+	quotes          map[jupag.Pair]jupag.QuoteResponse
+	defaultQuote    jupag.QuoteResponse
+	swapTransaction string
+	prices          jupag.PriceMap
+	routesMap       jupag.IndexedRoutesMap
+	errors          map[string]error
+	calls           []Call
+}
+
+// NewMockClient returns an empty MockClient.
+func NewMockClient() *MockClient {
+	return &MockClient{
+		quotes: make(map[jupag.Pair]jupag.QuoteResponse),
+		prices: make(jupag.PriceMap),
+		errors: make(map[string]error),
+	}
+}
+
+// SetQuote sets the quote returned for the pair.
+func (m *MockClient) SetQuote(inputMint, outputMint string, quotes jupag.QuoteResponse) *MockClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.quotes[jupag.Pair{InputMint: inputMint, OutputMint: outputMint}] = quotes
+	return m
+}
+
+// SetDefaultQuote sets the quote returned for pairs without a specific quote.
+func (m *MockClient) SetDefaultQuote(quotes jupag.QuoteResponse) *MockClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.defaultQuote = quotes
+	return m
+}
+
+// SetSwapTransaction sets the base64 transaction returned by Swap and BestSwap.
+func (m *MockClient) SetSwapTransaction(tx string) *MockClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.swapTransaction = tx
+	return m
+}
+
+// SetPrice sets the price returned for the id.
+func (m *MockClient) SetPrice(id string, price jupag.Price) *MockClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if price.ID == "" {
+		price.ID = id
+	}
+	m.prices[id] = price
+	return m
+}
+
+// SetRoutesMap sets the routes map returned by RoutesMap.
+func (m *MockClient) SetRoutesMap(routesMap jupag.IndexedRoutesMap) *MockClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.routesMap = routesMap
+	return m
+}
+
+// SetError makes the given method ("Quote", "Swap", ...) return err, nil clears it.
+func (m *MockClient) SetError(method string, err error) *MockClient {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err == nil {
+		delete(m.errors, method)
+	} else {
+		m.errors[method] = err
+	}
+	return m
+}
+
+// Calls returns the recorded calls of the given method, or all the calls when method is empty.
+func (m *MockClient) Calls(method string) []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make([]Call, 0)
+	for _, c := range m.calls {
+		if method == "" || c.Method == method {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// Reset clears the recorded calls.
+func (m *MockClient) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = nil
+}
+
+// record records the call and returns the injected error of the method.
+func (m *MockClient) record(method string, args ...any) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, Call{Method: method, Args: args, Time: time.Now()})
+	return m.errors[method]
+}
+
+func (m *MockClient) quote(inputMint, outputMint string) (jupag.QuoteResponse, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if q, ok := m.quotes[jupag.Pair{InputMint: inputMint, OutputMint: outputMint}]; ok {
+		return q, nil
+	}
+	if m.defaultQuote != nil {
+		return m.defaultQuote, nil
+	}
+	return nil, ErrNoQuote
+}
+
+// Quote returns the quote configured for the pair.
+func (m *MockClient) Quote(params jupag.QuoteParams) (jupag.QuoteResponse, error) {
+	if err := m.record("Quote", params); err != nil {
+		return nil, err
+	}
+	return m.quote(params.InputMint, params.OutputMint)
+}
+
+// ExchangeRate returns the rate of the best route of the quote configured for the pair.
+func (m *MockClient) ExchangeRate(params jupag.ExchangeRateParams) (jupag.Rate, error) {
+	rate := jupag.Rate{InputMint: params.InputMint, OutputMint: params.OutputMint}
+	if err := m.record("ExchangeRate", params); err != nil {
+		return rate, err
+	}
+
+	quotes, err := m.quote(params.InputMint, params.OutputMint)
+	if err != nil {
+		return rate, err
+	}
+	route, err := quotes.GetBestRoute()
+	if err != nil {
+		return rate, err
+	}
+	rate.InAmount = route.InAmount.MustUint64()
+	rate.OutAmount = route.OutAmount.MustUint64()
+	return rate, nil
+}
+
+// Swap returns the configured swap transaction.
+func (m *MockClient) Swap(params jupag.SwapParams) (string, error) {
+	if err := m.record("Swap", params); err != nil {
+		return "", err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.swapTransaction, nil
+}
+
+// BestSwap requires a quote configured for the pair and returns the configured swap transaction.
+func (m *MockClient) BestSwap(params jupag.BestSwapParams) (string, error) {
+	if err := m.record("BestSwap", params); err != nil {
+		return "", err
+	}
+	if _, err := m.quote(params.InputMint, params.OutputMint); err != nil {
+		return "", err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.swapTransaction, nil
+}
+
+// Price returns the configured prices of the requested ids.
+func (m *MockClient) Price(params jupag.PriceParams) (jupag.PriceMap, error) {
+	if err := m.record("Price", params); err != nil {
+		return nil, err
+	}
+	return m.lookupPrices(strings.Split(params.IDs, ",")), nil
+}
+
+// Prices returns the configured prices of the requested mints.
+func (m *MockClient) Prices(ctx context.Context, mints []string) (jupag.PriceMap, error) {
+	if err := m.record("Prices", mints); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.lookupPrices(mints), nil
+}
+
+func (m *MockClient) lookupPrices(ids []string) jupag.PriceMap {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make(jupag.PriceMap, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if p, ok := m.prices[id]; ok {
+			result[id] = p
+		}
+	}
+	return result
+}
+
+// RoutesMap returns the configured routes map.
+func (m *MockClient) RoutesMap(onlyDirectRoutes bool) (jupag.IndexedRoutesMap, error) {
+	if err := m.record("RoutesMap", onlyDirectRoutes); err != nil {
+		return jupag.IndexedRoutesMap{}, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.routesMap, nil
+}
@@ -0,0 +1,169 @@
+package jupagtest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Mode is the mode of a Recorder.
+type Mode int
+
+const (
+	// ModeReplay serves responses from fixtures only, unknown requests fail with ErrFixtureNotFound.
+	ModeReplay Mode = iota
+	// ModeRecord always calls the real transport and overwrites the fixtures.
+	ModeRecord
+	// ModeReplayOrRecord serves existing fixtures and records the missing ones.
+	ModeReplayOrRecord
+)
+
+var ErrFixtureNotFound = errors.New("jupagtest: fixture not found")
+
+// Fixture is a recorded request/response pair, stored as JSON.
+type Fixture struct {
+	Request struct {
+		Method string `json:"method"`
+		URL    string `json:"url"`
+		Body   string `json:"body,omitempty"`
+	} `json:"request"`
+	Response struct {
+		StatusCode int         `json:"statusCode"`
+		Header     http.Header `json:"header"`
+		Body       []byte      `json:"body"` // base64 in the JSON, the body may be binary, e.g. gzip encoded
+	} `json:"response"`
+}
+
+// Recorder is a VCR-style http.RoundTripper recording real responses to fixture files and replaying them.
+// Fixtures are keyed by method, URL and body, so replays are deterministic.
+//
+//	rec := jupagtest.NewRecorder("testdata/fixtures", jupagtest.ModeReplay, nil)
+//	client := jupag.NewJupag(jupag.WithHTTPClient(&http.Client{Transport: rec}))
+type Recorder struct {
+	dir       string
+	mode      Mode
+	transport http.RoundTripper
+
+	mu sync.Mutex
+}
+
+// NewRecorder returns a Recorder storing fixtures in dir, transport defaults to http.DefaultTransport.
+func NewRecorder(dir string, mode Mode, transport http.RoundTripper) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &Recorder{dir: dir, mode: mode, transport: transport}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	path := filepath.Join(r.dir, fixtureName(req.Method, req.URL.String(), body))
+
+	if r.mode != ModeRecord {
+		fixture, err := r.load(path)
+		if err == nil {
+			return fixture.response(req), nil
+		}
+		if r.mode == ModeReplay || !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s %s: %v", ErrFixtureNotFound, req.Method, req.URL, err)
+		}
+	}
+
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixture Fixture
+	fixture.Request.Method = req.Method
+	fixture.Request.URL = req.URL.String()
+	fixture.Request.Body = string(body)
+	fixture.Response.StatusCode = resp.StatusCode
+	fixture.Response.Header = resp.Header
+	fixture.Response.Body = respBody
+	if err := r.save(path, fixture); err != nil {
+		return nil, err
+	}
+
+	return fixture.response(req), nil
+}
+
+func (r *Recorder) load(path string) (Fixture, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var fixture Fixture
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fixture, err
+	}
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		return fixture, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+	return fixture, nil
+}
+
+func (r *Recorder) save(path string, fixture Fixture) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write fixture %s: %w", path, err)
+	}
+	return nil
+}
+
+func (f Fixture) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", f.Response.StatusCode, http.StatusText(f.Response.StatusCode)),
+		StatusCode:    f.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        f.Response.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(f.Response.Body)),
+		ContentLength: int64(len(f.Response.Body)),
+		Request:       req,
+	}
+}
+
+func fixtureName(method, url string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(url))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))[:16] + ".json"
+}
@@ -0,0 +1,62 @@
+package jupagtest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecorderGzipResponse(t *testing.T) {
+	const content = `{"data":{"SOL":{"price":"150.1"}}}`
+
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	zw.Write([]byte(content))
+	zw.Close()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(compressed.Bytes())
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	get := func(rec *Recorder) []byte {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/price?ids=SOL", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// set by hand, the transport leaves the body compressed
+		req.Header.Set("Accept-Encoding", "gzip")
+		resp, err := (&http.Client{Transport: rec}).Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if got := resp.Header.Get("Content-Encoding"); got != "gzip" {
+			t.Fatalf("Content-Encoding = %q, want gzip", got)
+		}
+		zr, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return body
+	}
+
+	if got := get(NewRecorder(dir, ModeRecord, nil)); string(got) != content {
+		t.Errorf("recorded body = %q, want %q", got, content)
+	}
+	server.Close()
+	if got := get(NewRecorder(dir, ModeReplay, nil)); string(got) != content {
+		t.Errorf("replayed body = %q, want %q", got, content)
+	}
+}
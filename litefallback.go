@@ -0,0 +1,117 @@
+package jupag
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// APIKeyHeader is the header carrying the API key of the paid Jupiter plans, see WithAPIKey.
+const APIKeyHeader = "X-Api-Key"
+
+// LiteFallbackConfig configures the fallback to the free lite API, see WithLiteFallback.
+type LiteFallbackConfig struct {
+	BaseURL  string        // default: https://lite-api.jup.ag
+	Cooldown time.Duration // time the requests go to the lite API before the base URL is tried again, default: 1m
+	Limiter  RateLimiter   // rate of the lite API requests, default: 1 request per second with a burst of 5
+	// OnChange is called when the fallback starts, active true, and when it ends, e.g. to export a metric.
+	OnChange func(active bool)
+}
+
+type liteFallback struct {
+	cfg LiteFallbackConfig
+
+	mu     sync.Mutex
+	active bool
+	until  time.Time
+}
+
+func newLiteFallback(cfg LiteFallbackConfig) *liteFallback {
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = "https://lite-api.jup.ag"
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = time.Minute
+	}
+	if cfg.Limiter == nil {
+		cfg.Limiter = NewRateLimiter(1, 5)
+	}
+	return &liteFallback{cfg: cfg}
+}
+
+// liteFallbackActive reports whether the requests go to the lite API, ending the fallback once the cooldown is over.
+func (c *JupagImpl) liteFallbackActive(ctx context.Context) bool {
+	lf := c.liteFallback
+	lf.mu.Lock()
+	ended := lf.active && !time.Now().Before(lf.until)
+	if ended {
+		lf.active = false
+	}
+	active := lf.active
+	lf.mu.Unlock()
+
+	if ended {
+		if c.logger != nil {
+			c.logger.LogAttrs(ctx, slog.LevelInfo, "jupiter api fallback to lite api ended")
+		}
+		if lf.cfg.OnChange != nil {
+			lf.cfg.OnChange(false)
+		}
+	}
+	return active
+}
+
+// startLiteFallback sends the requests to the lite API for the cooldown.
+func (c *JupagImpl) startLiteFallback(ctx context.Context, path string) {
+	lf := c.liteFallback
+	lf.mu.Lock()
+	started := !lf.active
+	lf.active = true
+	lf.until = time.Now().Add(lf.cfg.Cooldown)
+	lf.mu.Unlock()
+
+	if !started {
+		return
+	}
+	if c.logger != nil {
+		c.logger.LogAttrs(ctx, slog.LevelWarn, "jupiter api quota exhausted, falling back to lite api",
+			slog.String("path", path), slog.Duration("cooldown", lf.cfg.Cooldown))
+	}
+	if lf.cfg.OnChange != nil {
+		lf.cfg.OnChange(true)
+	}
+}
+
+type liteRequestKey struct{}
+
+// sendOrFallback sends a request to the base URL, or to the lite API while its quota is exhausted.
+// Requests on other base URLs are sent as is.
+func (c *JupagImpl) sendOrFallback(ctx context.Context, e Endpoint, method, completeUrl string, data []byte) (*http.Response, error) {
+	if c.liteFallback == nil || !strings.HasPrefix(completeUrl, c.apiUrl) {
+		return c.send(ctx, e, method, completeUrl, data)
+	}
+	path := strings.TrimPrefix(completeUrl, c.apiUrl)
+
+	if !c.liteFallbackActive(ctx) {
+		resp, err := c.send(ctx, e, method, completeUrl, data)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+			return resp, err
+		}
+		resp.Body.Close()
+		c.startLiteFallback(ctx, path)
+	}
+
+	if err := c.liteFallback.cfg.Limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.send(context.WithValue(ctx, liteRequestKey{}, true), e, method, c.liteFallback.cfg.BaseURL+path, data)
+}
+
+// LiteFallbackActive reports whether the requests currently fall back to the lite API, see WithLiteFallback.
+func (c *JupagImpl) LiteFallbackActive() bool {
+	return c.liteFallback != nil && c.liteFallbackActive(context.Background())
+}
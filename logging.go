@@ -0,0 +1,102 @@
+package jupag
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// logBodyLimit is the number of bytes of request and response bodies logged at debug level.
+const logBodyLimit = 1024
+
+type attemptsKey struct{}
+
+// requestLogger is a heimdall plugin counting the attempts of a request and logging the failed ones.
+type requestLogger struct {
+	logger *slog.Logger
+}
+
+func (l requestLogger) OnRequestStart(req *http.Request) {
+	if attempts, ok := req.Context().Value(attemptsKey{}).(*atomic.Int32); ok {
+		attempts.Add(1)
+	}
+}
+
+func (l requestLogger) OnRequestEnd(req *http.Request, resp *http.Response) {
+	if resp.StatusCode >= http.StatusInternalServerError {
+		l.logger.DebugContext(req.Context(), "jupiter api attempt failed",
+			slog.String("method", req.Method),
+			slog.String("path", req.URL.Path),
+			slog.Int("status", resp.StatusCode),
+		)
+	}
+}
+
+func (l requestLogger) OnError(req *http.Request, err error) {
+	l.logger.DebugContext(req.Context(), "jupiter api attempt failed",
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.String("error", err.Error()),
+	)
+}
+
+// withAttempts returns a context counting the attempts made by the http client.
+func withAttempts(ctx context.Context) (context.Context, *atomic.Int32) {
+	attempts := new(atomic.Int32)
+	return context.WithValue(ctx, attemptsKey{}, attempts), attempts
+}
+
+// logRequest logs a completed request, at debug level on success and warn level on failure.
+// The bodies are only read when debug is enabled, the response body is restored for the caller.
+func (c *JupagImpl) logRequest(ctx context.Context, req *http.Request, reqBody []byte, resp *http.Response, err error, latency time.Duration, attempts int32) {
+	level := slog.LevelDebug
+	if err != nil || resp == nil || resp.StatusCode >= http.StatusBadRequest {
+		level = slog.LevelWarn
+	}
+	if !c.logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.Path),
+		slog.Duration("latency", latency),
+		slog.Int("retries", max(int(attempts)-1, 0)),
+	}
+	if id := req.Header.Get(CorrelationIDHeader); id != "" {
+		attrs = append(attrs, slog.String("requestId", id))
+	}
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	if c.logger.Enabled(ctx, slog.LevelDebug) {
+		if len(reqBody) > 0 {
+			attrs = append(attrs, slog.String("requestBody", truncateBody(reqBody)))
+		}
+		if resp != nil && resp.Body != nil {
+			data, readErr := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(data))
+			if readErr == nil {
+				attrs = append(attrs, slog.String("responseBody", truncateBody(data)))
+			}
+		}
+	}
+
+	c.logger.LogAttrs(ctx, level, "jupiter api request", attrs...)
+}
+
+func truncateBody(data []byte) string {
+	if len(data) <= logBodyLimit {
+		return string(data)
+	}
+	return string(data[:logBodyLimit]) + "...(truncated)"
+}
@@ -0,0 +1,122 @@
+package jupag
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+var ErrTransactionTooLarge = errors.New("transaction is too large")
+
+const (
+	// MaxTransactionSize is the maximum size of a serialized transaction, the IPv6 MTU minus the headers.
+	MaxTransactionSize = 1232
+	// lookupTableMetaSize is the size of the metadata preceding the addresses of an address lookup table account.
+	lookupTableMetaSize = 56
+)
+
+// TransactionTooLargeError is returned when a transaction exceeds MaxTransactionSize, it matches ErrTransactionTooLarge.
+type TransactionTooLargeError struct {
+	Size      int
+	Versioned bool
+}
+
+func (e *TransactionTooLargeError) Error() string {
+	advice := "reduce QuoteParams.MaxAccounts or use OnlyDirectRoutes"
+	if !e.Versioned {
+		advice += ", or use a versioned transaction instead of a legacy one"
+	}
+	return fmt.Sprintf("transaction is too large: %d bytes, max %d: %s", e.Size, MaxTransactionSize, advice)
+}
+
+func (e *TransactionTooLargeError) Unwrap() error {
+	return ErrTransactionTooLarge
+}
+
+// Size returns the serialized size of the transaction, with a slot for each required signature.
+func (tx *Transaction) Size() int {
+	n := int(tx.Message.Header.NumRequiredSignatures)
+	return len(appendCompactU16(nil, n)) + n*64 + len(tx.Message.Marshal())
+}
+
+// CheckSize returns a TransactionTooLargeError when the transaction can't be submitted.
+func (tx *Transaction) CheckSize() error {
+	if size := tx.Size(); size > MaxTransactionSize {
+		return &TransactionTooLargeError{Size: size, Versioned: tx.Message.Versioned}
+	}
+	return nil
+}
+
+// AddressLookupTables maps address lookup table accounts to their addresses.
+type AddressLookupTables map[PublicKey][]PublicKey
+
+// ResolveAddressLookupTables fetches the address lookup tables referenced by the message from the RPC node.
+func ResolveAddressLookupTables(ctx context.Context, rpc RPCClient, m *Message) (AddressLookupTables, error) {
+	tables := make(AddressLookupTables, len(m.AddressTableLookups))
+	if len(m.AddressTableLookups) == 0 {
+		return tables, nil
+	}
+
+	keys := make([]string, len(m.AddressTableLookups))
+	for i, lookup := range m.AddressTableLookups {
+		keys[i] = lookup.AccountKey.String()
+	}
+	var result struct {
+		Value []*struct {
+			Data []string `json:"data"` // base64 data and its encoding
+		} `json:"value"`
+	}
+	if err := rpc.Call(ctx, "getMultipleAccounts", []any{keys, map[string]any{"encoding": "base64"}}, &result); err != nil {
+		return nil, fmt.Errorf("failed to get address lookup tables: %w", err)
+	}
+	if len(result.Value) != len(keys) {
+		return nil, fmt.Errorf("failed to get address lookup tables: expected %d accounts, got %d", len(keys), len(result.Value))
+	}
+
+	for i, account := range result.Value {
+		if account == nil || len(account.Data) == 0 {
+			return nil, fmt.Errorf("address lookup table %s not found", keys[i])
+		}
+		data, err := base64.StdEncoding.DecodeString(account.Data[0])
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode address lookup table %s: %w", keys[i], err)
+		}
+		if len(data) < lookupTableMetaSize || (len(data)-lookupTableMetaSize)%32 != 0 {
+			return nil, fmt.Errorf("invalid address lookup table %s", keys[i])
+		}
+
+		addresses := make([]PublicKey, (len(data)-lookupTableMetaSize)/32)
+		for j := range addresses {
+			copy(addresses[j][:], data[lookupTableMetaSize+j*32:])
+		}
+		tables[m.AddressTableLookups[i].AccountKey] = addresses
+	}
+	return tables, nil
+}
+
+// ResolvedAccountKeys returns all the accounts of the message: the static keys, the writable then the read-only
+// keys loaded from the address lookup tables, as indexed by the compiled instructions.
+func (m *Message) ResolvedAccountKeys(tables AddressLookupTables) ([]PublicKey, error) {
+	keys := append([]PublicKey(nil), m.AccountKeys...)
+	var readonly []PublicKey
+	for _, lookup := range m.AddressTableLookups {
+		addresses, ok := tables[lookup.AccountKey]
+		if !ok {
+			return nil, fmt.Errorf("address lookup table %s not resolved", lookup.AccountKey)
+		}
+		for _, i := range lookup.WritableIndexes {
+			if int(i) >= len(addresses) {
+				return nil, fmt.Errorf("index %d out of range of address lookup table %s", i, lookup.AccountKey)
+			}
+			keys = append(keys, addresses[i])
+		}
+		for _, i := range lookup.ReadonlyIndexes {
+			if int(i) >= len(addresses) {
+				return nil, fmt.Errorf("index %d out of range of address lookup table %s", i, lookup.AccountKey)
+			}
+			readonly = append(readonly, addresses[i])
+		}
+	}
+	return append(keys, readonly...), nil
+}
@@ -0,0 +1,91 @@
+package jupag
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// defaultQuoteMaxAge is the age after which a managed quote expires when no max age is given.
+const defaultQuoteMaxAge = 10 * time.Second
+
+// ManagedQuote is a quote that tracks its age and refreshes itself when it is used after expiring,
+// e.g. a quote shown in a UI and executed seconds later. It is safe for concurrent use.
+type ManagedQuote struct {
+	client *JupagImpl
+	params QuoteParams
+	maxAge time.Duration
+
+	mu        sync.Mutex
+	quote     QuoteResponse
+	fetchedAt time.Time
+}
+
+// NewManagedQuote quotes the params and returns a managed quote expiring after maxAge, default: 10s.
+func (c *JupagImpl) NewManagedQuote(ctx context.Context, params QuoteParams, maxAge time.Duration) (*ManagedQuote, error) {
+	if maxAge <= 0 {
+		maxAge = defaultQuoteMaxAge
+	}
+	q := &ManagedQuote{client: c, params: params, maxAge: maxAge}
+	if err := q.Refresh(ctx); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// Quote returns the current quote, which may be expired.
+func (q *ManagedQuote) Quote() QuoteResponse {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.quote
+}
+
+// FetchedAt returns the time of the current quote.
+func (q *ManagedQuote) FetchedAt() time.Time {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.fetchedAt
+}
+
+// Age returns the age of the current quote.
+func (q *ManagedQuote) Age() time.Duration {
+	return time.Since(q.FetchedAt())
+}
+
+// Expired reports whether the current quote is older than the max age.
+func (q *ManagedQuote) Expired() bool {
+	return q.Age() > q.maxAge
+}
+
+// Refresh fetches a new quote, the current one is kept when it fails.
+func (q *ManagedQuote) Refresh(ctx context.Context) error {
+	quote, err := q.client.quote(ctx, q.params)
+	if err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.quote, q.fetchedAt = quote, time.Now()
+	return nil
+}
+
+// Route returns the best route of the quote, refreshing it first if it expired.
+func (q *ManagedQuote) Route(ctx context.Context) (Route, error) {
+	if q.Expired() {
+		if err := q.Refresh(ctx); err != nil {
+			return Route{}, err
+		}
+	}
+	return q.Quote().GetBestRoute()
+}
+
+// Swap builds the swap of the best route, refreshing the quote first if it expired. params.Route is ignored.
+func (q *ManagedQuote) Swap(ctx context.Context, params SwapParams) (SwapResponse, error) {
+	route, err := q.Route(ctx)
+	if err != nil {
+		return SwapResponse{}, err
+	}
+	params.Route = route
+	return q.client.swap(ctx, params)
+}
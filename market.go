@@ -0,0 +1,166 @@
+package jupag
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// TokenMarket is the market data of a single token.
+type TokenMarket struct {
+	Mint         string  `json:"mint"`
+	PriceUSD     float64 `json:"priceUsd"`
+	LiquidityUSD float64 `json:"liquidityUsd"` // approximate notional tradable with 1% price impact, 0 when unknown
+}
+
+// MarketPair is a direct pair of the routes map annotated with prices and liquidity.
+type MarketPair struct {
+	InputMint      string  `json:"inputMint"`
+	OutputMint     string  `json:"outputMint"`
+	InputPriceUSD  float64 `json:"inputPriceUsd"`
+	OutputPriceUSD float64 `json:"outputPriceUsd"`
+	LiquidityUSD   float64 `json:"liquidityUsd"` // approximate, the lowest liquidity of both legs
+}
+
+// MarketSnapshot joins the routes map with the price API.
+type MarketSnapshot struct {
+	TakenAt time.Time              `json:"takenAt"`
+	Tokens  map[string]TokenMarket `json:"tokens"`
+	Pairs   []MarketPair           `json:"pairs"` // sorted by liquidity, highest first
+
+	index map[Pair]int
+}
+
+// MarketSnapshot fetches the direct routes map and enriches it with prices.
+func (c *JupagImpl) MarketSnapshot(ctx context.Context) (*MarketSnapshot, error) {
+	routesMap, err := c.routesMap(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	return c.EnrichRoutesMap(ctx, routesMap)
+}
+
+// EnrichRoutesMap annotates each pair of the routes map with both legs' USD prices and approximate liquidity.
+// Pairs with a token that has no price are skipped.
+func (c *JupagImpl) EnrichRoutesMap(ctx context.Context, routesMap IndexedRoutesMap) (*MarketSnapshot, error) {
+	snapshot := &MarketSnapshot{
+		TakenAt: time.Now(),
+		Tokens:  make(map[string]TokenMarket, len(routesMap.MintKeys)),
+	}
+
+	prices, err := c.batchPrices(ctx, routesMap.MintKeys, PriceParams{ShowExtraInfo: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to enrich routes map: %w", err)
+	}
+	for id, p := range prices {
+		usd, err := strconv.ParseFloat(p.Price, 64)
+		if err != nil {
+			continue
+		}
+		snapshot.Tokens[id] = TokenMarket{
+			Mint:         id,
+			PriceUSD:     usd,
+			LiquidityUSD: p.approximateLiquidityUSD(),
+		}
+	}
+
+	routesMap.ForEachPair(func(p Pair) bool {
+		in, ok := snapshot.Tokens[p.InputMint]
+		if !ok {
+			return true
+		}
+		out, ok := snapshot.Tokens[p.OutputMint]
+		if !ok {
+			return true
+		}
+		snapshot.Pairs = append(snapshot.Pairs, MarketPair{
+			InputMint:      in.Mint,
+			OutputMint:     out.Mint,
+			InputPriceUSD:  in.PriceUSD,
+			OutputPriceUSD: out.PriceUSD,
+			LiquidityUSD:   min(in.LiquidityUSD, out.LiquidityUSD),
+		})
+		return true
+	})
+
+	snapshot.buildIndex()
+	return snapshot, nil
+}
+
+func (s *MarketSnapshot) buildIndex() {
+	sort.Slice(s.Pairs, func(i, j int) bool {
+		if s.Pairs[i].LiquidityUSD != s.Pairs[j].LiquidityUSD {
+			return s.Pairs[i].LiquidityUSD > s.Pairs[j].LiquidityUSD
+		}
+		return s.Pairs[i].InputMint+s.Pairs[i].OutputMint < s.Pairs[j].InputMint+s.Pairs[j].OutputMint
+	})
+	s.index = make(map[Pair]int, len(s.Pairs))
+	for i, p := range s.Pairs {
+		s.index[Pair{InputMint: p.InputMint, OutputMint: p.OutputMint}] = i
+	}
+}
+
+// Pair returns the market pair for the given mints.
+func (s *MarketSnapshot) Pair(inputMint, outputMint string) (MarketPair, bool) {
+	if s.index == nil {
+		s.buildIndex()
+	}
+	i, ok := s.index[Pair{InputMint: inputMint, OutputMint: outputMint}]
+	if !ok {
+		return MarketPair{}, false
+	}
+	return s.Pairs[i], true
+}
+
+// PairsFor returns the pairs having the given mint as input, sorted by liquidity.
+func (s *MarketSnapshot) PairsFor(inputMint string) []MarketPair {
+	result := make([]MarketPair, 0)
+	for _, p := range s.Pairs {
+		if p.InputMint == inputMint {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// Filter returns the pairs with at least the given approximate liquidity.
+func (s *MarketSnapshot) Filter(minLiquidityUSD float64) []MarketPair {
+	result := make([]MarketPair, 0)
+	for _, p := range s.Pairs {
+		if p.LiquidityUSD >= minLiquidityUSD {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// approximateLiquidityUSD extrapolates the notional tradable with 1% price impact from the price depth,
+// using the largest sampled size with a non-zero impact ratio. It returns 0 when the depth is unknown.
+func (p Price) approximateLiquidityUSD() float64 {
+	if p.ExtraInfo == nil || p.ExtraInfo.Depth == nil {
+		return 0
+	}
+
+	liquidity := math.Inf(1)
+	for _, depth := range []PriceDepth{p.ExtraInfo.Depth.BuyPriceImpactRatio, p.ExtraInfo.Depth.SellPriceImpactRatio} {
+		var size, impact float64
+		for k, v := range depth.Depth {
+			s, err := strconv.ParseFloat(k, 64)
+			if err != nil || v <= 0 || s <= size {
+				continue
+			}
+			size, impact = s, v
+		}
+		if size > 0 {
+			liquidity = math.Min(liquidity, size*0.01/impact)
+		}
+	}
+
+	if math.IsInf(liquidity, 1) {
+		return 0
+	}
+	return liquidity
+}
@@ -0,0 +1,104 @@
+package jupag
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"math"
+)
+
+var errTooManyAccounts = errors.New("message references more than 256 accounts")
+
+// NewTransaction compiles instructions into an unsigned legacy transaction paid by payer.
+// The signature slots are zeroed until the transaction is signed.
+func NewTransaction(payer, recentBlockhash PublicKey, instructions ...Instruction) (*Transaction, error) {
+	msg, err := NewMessage(payer, recentBlockhash, instructions...)
+	if err != nil {
+		return nil, err
+	}
+
+	sigs := make([][]byte, msg.Header.NumRequiredSignatures)
+	for i := range sigs {
+		sigs[i] = make([]byte, ed25519.SignatureSize)
+	}
+	return &Transaction{Signatures: sigs, Message: *msg}, nil
+}
+
+// NewMessage compiles instructions into a legacy message paid by payer. The accounts are ordered as the runtime
+// expects: writable signers starting with the payer, read-only signers, writable and read-only non-signers.
+func NewMessage(payer, recentBlockhash PublicKey, instructions ...Instruction) (*Message, error) {
+	type account struct {
+		key      PublicKey
+		signer   bool
+		writable bool
+	}
+	accounts := []*account{{key: payer, signer: true, writable: true}}
+	index := map[PublicKey]*account{payer: accounts[0]}
+	add := func(key PublicKey, signer, writable bool) {
+		if a, ok := index[key]; ok {
+			a.signer = a.signer || signer
+			a.writable = a.writable || writable
+			return
+		}
+		a := &account{key: key, signer: signer, writable: writable}
+		index[key] = a
+		accounts = append(accounts, a)
+	}
+	for _, ix := range instructions {
+		for _, meta := range ix.Accounts {
+			add(meta.PublicKey, meta.IsSigner, meta.IsWritable)
+		}
+		add(ix.ProgramID, false, false)
+	}
+
+	msg := &Message{RecentBlockhash: recentBlockhash}
+	for _, group := range []struct{ signer, writable bool }{{true, true}, {true, false}, {false, true}, {false, false}} {
+		for _, a := range accounts {
+			if a.signer != group.signer || a.writable != group.writable {
+				continue
+			}
+			msg.AccountKeys = append(msg.AccountKeys, a.key)
+			switch {
+			case a.signer && a.writable:
+				msg.Header.NumRequiredSignatures++
+			case a.signer:
+				msg.Header.NumRequiredSignatures++
+				msg.Header.NumReadonlySignedAccounts++
+			case !a.writable:
+				msg.Header.NumReadonlyUnsignedAccounts++
+			}
+		}
+	}
+	if len(msg.AccountKeys) > math.MaxUint8+1 {
+		return nil, errTooManyAccounts
+	}
+
+	positions := make(map[PublicKey]uint8, len(msg.AccountKeys))
+	for i, key := range msg.AccountKeys {
+		positions[key] = uint8(i)
+	}
+	for _, ix := range instructions {
+		compiled := CompiledInstruction{ProgramIDIndex: positions[ix.ProgramID], Data: ix.Data}
+		for _, meta := range ix.Accounts {
+			compiled.Accounts = append(compiled.Accounts, positions[meta.PublicKey])
+		}
+		msg.Instructions = append(msg.Instructions, compiled)
+	}
+
+	return msg, nil
+}
+
+// LatestBlockhash returns the latest blockhash and the last block height at which it is valid, from the RPC node.
+func LatestBlockhash(ctx context.Context, rpc RPCClient, commitment Commitment) (PublicKey, uint64, error) {
+	var result struct {
+		Value struct {
+			Blockhash            PublicKey `json:"blockhash"`
+			LastValidBlockHeight uint64    `json:"lastValidBlockHeight"`
+		} `json:"value"`
+	}
+	if err := rpc.Call(ctx, "getLatestBlockhash", []any{map[string]any{"commitment": commitment}}, &result); err != nil {
+		return PublicKey{}, 0, fmt.Errorf("failed to get latest blockhash: %w", err)
+	}
+	return result.Value.Blockhash, result.Value.LastValidBlockHeight, nil
+}
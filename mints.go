@@ -0,0 +1,144 @@
+package jupag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Mints of well-known tokens.
+const (
+	MintSOL     = "So11111111111111111111111111111111111111112" // wrapped SOL, used by the API for native SOL too
+	MintWSOL    = MintSOL
+	MintUSDC    = "EPjFWdd5AufqSSqeM2qN1xzybapC8G4wEGGkZwyTDt1v"
+	MintUSDT    = "Es9vMFrzaCERmJfrF4H2FYD4KCoNkY11McCe8BenwNYB"
+	MintJUP     = "JUPyiwrYJFskUPiHa7hkeR8VUtAeFoSYbKedZNsDvCN"
+	MintJitoSOL = "J1toso1uCk3RLmjorhTtrVwY9HJ7X8V9yYac6Y7kGCPn"
+	MintMSOL    = "mSoLzYCxHdYgdzU16g5QSh3i5K3z3KZK7ytfqcJm7So"
+	MintBSOL    = "bSo13r4TkiE4KumL71LsHTPpL2euBYLFx6h9HP3piy1"
+	MintBONK    = "DezXAZ8z7PnrnRJjz3wXBoRgixCa6xjnB7YaB1pPB263"
+	MintWIF     = "EKpQGSJtjMFqKZ9KQanSqYXRcF8fBopzLHYxdM65zcjm"
+	MintPYTH    = "HZ1JovNiVvGrGNiiYvEozEVgZ58xaU3RKwX8eACQBCt3"
+	MintRAY     = "4k3Dyjzvzp8eMZWUXbBCjEvwSkkk59S5iCNLY3QrkX6R"
+)
+
+var (
+	ErrUnknownToken    = errors.New("unknown token")
+	ErrAmbiguousSymbol = errors.New("ambiguous token symbol")
+)
+
+// tokenListTTL is how long the verified token list backing ResolveMint is cached.
+const tokenListTTL = time.Hour
+
+type wellKnownToken struct {
+	symbol   string
+	mint     string
+	decimals uint8
+}
+
+// Tokens that can be referenced by symbol without calling the token API.
+var wellKnownTokens = []wellKnownToken{
+	{"SOL", MintSOL, 9},
+	{"WSOL", MintWSOL, 9},
+	{"USDC", MintUSDC, 6},
+	{"USDT", MintUSDT, 6},
+	{"JUP", MintJUP, 6},
+	{"JITOSOL", MintJitoSOL, 9},
+	{"MSOL", MintMSOL, 9},
+	{"BSOL", MintBSOL, 9},
+	{"BONK", MintBONK, 5},
+	{"WIF", MintWIF, 6},
+	{"PYTH", MintPYTH, 6},
+	{"RAY", MintRAY, 6},
+}
+
+func wellKnownMint(symbol string) (string, bool) {
+	symbol = strings.ToUpper(symbol)
+	for _, t := range wellKnownTokens {
+		if t.symbol == symbol {
+			return t.mint, true
+		}
+	}
+	return "", false
+}
+
+// tokenList caches the verified tokens indexed by upper case symbol.
+type tokenList struct {
+	mu        sync.Mutex
+	bySymbol  map[string][]TokenInfo
+	fetchedAt time.Time
+}
+
+// ResolveMint returns the mint of a token given as a symbol or a mint address.
+// Well-known symbols are resolved locally, other symbols are looked up in the verified token list.
+// Symbols shared by several verified tokens fail with ErrAmbiguousSymbol, pass the mint address instead.
+func (c *JupagImpl) ResolveMint(ctx context.Context, symbolOrMint string) (string, error) {
+	if mint, ok := wellKnownMint(symbolOrMint); ok {
+		return mint, nil
+	}
+	if _, err := ParsePublicKey(symbolOrMint); err == nil {
+		return symbolOrMint, nil
+	}
+
+	tokens, err := c.tokensBySymbol(ctx, symbolOrMint)
+	if err != nil {
+		return "", err
+	}
+	switch len(tokens) {
+	case 0:
+		return "", fmt.Errorf("%w %q", ErrUnknownToken, symbolOrMint)
+	case 1:
+		return tokens[0].Address, nil
+	default:
+		return "", fmt.Errorf("%w %q: %d verified tokens", ErrAmbiguousSymbol, symbolOrMint, len(tokens))
+	}
+}
+
+func (c *JupagImpl) tokensBySymbol(ctx context.Context, symbol string) ([]TokenInfo, error) {
+	c.tokenList.mu.Lock()
+	defer c.tokenList.mu.Unlock()
+
+	if c.tokenList.bySymbol == nil || time.Since(c.tokenList.fetchedAt) > tokenListTTL {
+		tokens, fetchedAt, err := c.verifiedTokens(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve token %q: %w", symbol, err)
+		}
+		c.tokenList.bySymbol = make(map[string][]TokenInfo, len(tokens))
+		for _, t := range tokens {
+			key := strings.ToUpper(t.Symbol)
+			c.tokenList.bySymbol[key] = append(c.tokenList.bySymbol[key], t)
+		}
+		c.tokenList.fetchedAt = fetchedAt
+	}
+
+	return c.tokenList.bySymbol[strings.ToUpper(symbol)], nil
+}
+
+// sharedTokenList is the verified token list stored in the shared cache.
+type sharedTokenList struct {
+	Tokens    []TokenInfo `json:"tokens"`
+	FetchedAt time.Time   `json:"fetchedAt"`
+}
+
+// verifiedTokens returns the verified token list and the time it was fetched, from the shared cache when fresh.
+func (c *JupagImpl) verifiedTokens(ctx context.Context) ([]TokenInfo, time.Time, error) {
+	const key = "tokens:verified"
+	if c.payloadCache != nil {
+		if shared, ok := cacheGet[sharedTokenList](ctx, c.payloadCache, key); ok && time.Since(shared.FetchedAt) <= tokenListTTL {
+			return shared.Tokens, shared.FetchedAt, nil
+		}
+	}
+
+	tokens, err := c.TaggedTokens(ctx, "verified")
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	now := time.Now()
+	if c.payloadCache != nil {
+		cacheSet(ctx, c.payloadCache, key, sharedTokenList{Tokens: tokens, FetchedAt: now}, tokenListTTL)
+	}
+	return tokens, now, nil
+}
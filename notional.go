@@ -0,0 +1,82 @@
+package jupag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var (
+	ErrNoPrice          = errors.New("no price for token")
+	ErrNotionalTooSmall = errors.New("swap notional below minimum")
+	ErrNotionalTooLarge = errors.New("swap notional above maximum")
+)
+
+// NotionalError is returned when the USD value of the input of a swap is outside the limits of the notional guard,
+// it matches ErrNotionalTooSmall or ErrNotionalTooLarge.
+type NotionalError struct {
+	Mint        string
+	NotionalUSD float64
+	MinUSD      float64
+	MaxUSD      float64
+}
+
+func (e *NotionalError) Error() string {
+	if e.NotionalUSD < e.MinUSD {
+		return fmt.Sprintf("swap notional below minimum: $%.2f of %s, min $%.2f", e.NotionalUSD, e.Mint, e.MinUSD)
+	}
+	return fmt.Sprintf("swap notional above maximum: $%.2f of %s, max $%.2f", e.NotionalUSD, e.Mint, e.MaxUSD)
+}
+
+func (e *NotionalError) Unwrap() error {
+	if e.NotionalUSD < e.MinUSD {
+		return ErrNotionalTooSmall
+	}
+	return ErrNotionalTooLarge
+}
+
+// USDValue returns the value in USD of a raw amount of a mint, from the price API.
+func (c *JupagImpl) USDValue(ctx context.Context, mint string, amount Amount) (float64, error) {
+	prices, err := c.price(ctx, PriceParams{IDs: mint})
+	if err != nil {
+		return 0, err
+	}
+	price, ok := prices[mint]
+	if !ok || price.decimal().IsZero() {
+		return 0, fmt.Errorf("%w: %s", ErrNoPrice, mint)
+	}
+	decimals, err := c.Decimals(ctx, mint)
+	if err != nil {
+		return 0, err
+	}
+
+	value := new(big.Rat).SetFrac(amount.BigInt(), pow10(decimals))
+	f, _ := value.Mul(value, price.decimal().Rat()).Float64()
+	return f, nil
+}
+
+// notionalGuard rejects swaps whose input is worth less than minUSD or more than maxUSD, 0 disables a bound.
+type notionalGuard struct {
+	minUSD float64
+	maxUSD float64
+}
+
+// checkNotional returns a NotionalError when the input of the route is outside the limits of the notional guard.
+// Swaps are rejected when the input can't be valued.
+func (c *JupagImpl) checkNotional(ctx context.Context, route Route) error {
+	if c.notionalGuard == nil || len(route.MarketInfos) == 0 {
+		return nil
+	}
+	mint := route.MarketInfos[0].InputMint
+
+	notional, err := c.USDValue(ctx, mint, route.InAmount)
+	if err != nil {
+		return fmt.Errorf("failed to value swap input: %w", err)
+	}
+	g := c.notionalGuard
+	if (g.minUSD > 0 && notional < g.minUSD) || (g.maxUSD > 0 && notional > g.maxUSD) {
+		return &NotionalError{Mint: mint, NotionalUSD: notional, MinUSD: g.minUSD, MaxUSD: g.maxUSD}
+	}
+	return nil
+}
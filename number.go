@@ -0,0 +1,132 @@
+package jupag
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Int64 is an integer the API returns either as a JSON string or number, e.g. prioritizationFeeLamports.
+// It marshals to a JSON number.
+type Int64 int64
+
+// UnmarshalJSON decodes the integer from a JSON string or number, null and "" leave it 0.
+func (i *Int64) UnmarshalJSON(data []byte) error {
+	s, ok, err := jsonNumberText(data)
+	if err != nil || !ok {
+		return err
+	}
+
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		// some responses encode integers as floats, e.g. 1000.0
+		f, ferr := strconv.ParseFloat(s, 64)
+		if ferr != nil || f != float64(int64(f)) {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		v = int64(f)
+	}
+	*i = Int64(v)
+	return nil
+}
+
+// jsonNumberText returns the text of a JSON number or of a JSON string holding a number.
+// It reports false for null and "".
+func jsonNumberText(data []byte) (string, bool, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return "", false, nil
+	}
+
+	s := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return "", false, err
+		}
+		s = strings.TrimSpace(s)
+	}
+	return s, s != "", nil
+}
+
+// FeeSetting is a fee field the API accepts either as a number or as "auto" to let it pick the fee,
+// e.g. computeUnitPriceMicroLamports. The zero value is unset and omitted from the requests.
+type FeeSetting string
+
+// FeeAuto lets the API pick the fee.
+const FeeAuto FeeSetting = "auto"
+
+// FixedFee returns the setting of a fixed fee, in the unit of the field.
+func FixedFee(v int64) FeeSetting {
+	return FeeSetting(strconv.FormatInt(v, 10))
+}
+
+// IsSet reports whether the fee is set, fixed or auto.
+func (f FeeSetting) IsSet() bool {
+	return f != ""
+}
+
+// IsAuto reports whether the API picks the fee.
+func (f FeeSetting) IsAuto() bool {
+	return f == FeeAuto
+}
+
+// Value returns the fixed fee, false when the fee is unset or auto.
+func (f FeeSetting) Value() (int64, bool) {
+	if !f.IsSet() || f.IsAuto() {
+		return 0, false
+	}
+	v, err := strconv.ParseInt(string(f), 10, 64)
+	return v, err == nil
+}
+
+// validate checks the fee is unset, auto or a non-negative integer.
+func (f FeeSetting) validate() error {
+	if !f.IsSet() || f.IsAuto() {
+		return nil
+	}
+	v, err := strconv.ParseInt(string(f), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid fee %q, expected an integer or %q", string(f), FeeAuto)
+	}
+	if v < 0 {
+		return fmt.Errorf("negative fee %d", v)
+	}
+	return nil
+}
+
+// MarshalJSON encodes a fixed fee as a JSON number, auto as "auto" and unset as null.
+func (f FeeSetting) MarshalJSON() ([]byte, error) {
+	switch {
+	case !f.IsSet():
+		return []byte("null"), nil
+	case f.IsAuto():
+		return json.Marshal(string(f))
+	}
+	v, err := strconv.ParseInt(string(f), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fee %q, expected an integer or %q", string(f), FeeAuto)
+	}
+	return strconv.AppendInt(nil, v, 10), nil
+}
+
+// UnmarshalJSON decodes the fee from a JSON number, a JSON string holding a number or "auto",
+// null and "" leave it unset.
+func (f *FeeSetting) UnmarshalJSON(data []byte) error {
+	s, ok, err := jsonNumberText(data)
+	if err != nil || !ok {
+		return err
+	}
+	if s == string(FeeAuto) {
+		*f = FeeAuto
+		return nil
+	}
+
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid fee %q, expected an integer or %q", s, FeeAuto)
+	}
+	*f = FixedFee(v)
+	return nil
+}
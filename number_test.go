@@ -0,0 +1,106 @@
+package jupag
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJSONNumberText(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		ok      bool
+		wantErr bool
+	}{
+		{in: `123`, want: "123", ok: true},
+		{in: ` -1.5e3 `, want: "-1.5e3", ok: true},
+		{in: `"123"`, want: "123", ok: true},
+		{in: `" 42 "`, want: "42", ok: true},
+		{in: `"auto"`, want: "auto", ok: true},
+		{in: `null`},
+		{in: `""`},
+		{in: `"  "`},
+		{in: `"unterminated`, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, ok, err := jsonNumberText([]byte(tt.in))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("jsonNumberText(%s) error = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("jsonNumberText(%s) = %q, %v, want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestInt64UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Int64
+		wantErr bool
+	}{
+		{in: `5000`, want: 5000},
+		{in: `"5000"`, want: 5000},
+		{in: `-12`, want: -12},
+		{in: `1000.0`, want: 1000},
+		{in: `"1e3"`, want: 1000},
+		{in: `9223372036854775807`, want: 9223372036854775807},
+		{in: `null`, want: 0},
+		{in: `""`, want: 0},
+		{in: `1.5`, wantErr: true},
+		{in: `"abc"`, wantErr: true},
+		{in: `true`, wantErr: true},
+	}
+	for _, tt := range tests {
+		var v struct {
+			N Int64 `json:"n"`
+		}
+		err := json.Unmarshal([]byte(`{"n":`+tt.in+`}`), &v)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Int64 %s: error = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err == nil && v.N != tt.want {
+			t.Errorf("Int64 %s = %d, want %d", tt.in, v.N, tt.want)
+		}
+	}
+
+	b, err := json.Marshal(Int64(42))
+	if err != nil || string(b) != "42" {
+		t.Errorf("Marshal(Int64(42)) = %s, %v, want 42", b, err)
+	}
+}
+
+func TestFeeSettingJSON(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    FeeSetting
+		wantErr bool
+	}{
+		{in: `1000`, want: FixedFee(1000)},
+		{in: `"1000"`, want: FixedFee(1000)},
+		{in: `"auto"`, want: FeeAuto},
+		{in: `null`, want: ""},
+		{in: `""`, want: ""},
+		{in: `1.5`, wantErr: true},
+		{in: `"fast"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		var f FeeSetting
+		err := json.Unmarshal([]byte(tt.in), &f)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("FeeSetting %s: error = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err == nil && f != tt.want {
+			t.Errorf("FeeSetting %s = %q, want %q", tt.in, f, tt.want)
+		}
+	}
+
+	for f, want := range map[FeeSetting]string{"": "null", FeeAuto: `"auto"`, FixedFee(7): "7"} {
+		if b, err := json.Marshal(f); err != nil || string(b) != want {
+			t.Errorf("Marshal(%q) = %s, %v, want %s", f, b, err, want)
+		}
+	}
+}
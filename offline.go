@@ -0,0 +1,98 @@
+package jupag
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// The functions of this file, like the Route and Amount methods, FromUIAmount or DecodeTransaction,
+// don't need a client, e.g. to reuse the quote math in backtests and offline analysis.
+
+// RoutePrice returns the UI price of the route, in output tokens per input token, e.g. 150 USDC per SOL.
+func RoutePrice(route Route, inputDecimals, outputDecimals uint8) (Decimal, error) {
+	if route.InAmount.IsZero() {
+		return Decimal{}, ErrNoPrice
+	}
+	// (out / 10^outputDecimals) / (in / 10^inputDecimals)
+	num := new(big.Int).Mul(route.OutAmount.BigInt(), pow10(inputDecimals))
+	den := new(big.Int).Mul(route.InAmount.BigInt(), pow10(outputDecimals))
+	return ratDecimal(new(big.Rat).SetFrac(num, den)), nil
+}
+
+// SlippageThreshold returns the worst amount accepted for the slippage: the minimum output of an ExactIn
+// swap of the given out amount, or the maximum input of an ExactOut swap of the given in amount.
+// It is the other amount threshold of the routes the API quotes.
+func SlippageThreshold(amount Amount, slippageBps uint64, swapMode string) Amount {
+	if swapMode == SwapModeExactOut {
+		return amount.MulDiv(10000+slippageBps, 10000)
+	}
+	return amount.ApplyBps(slippageBps)
+}
+
+// RealizedSlippageBps returns the slippage between the quoted and received output amounts in basis points,
+// negative when more than quoted was received. It returns 0 when the quoted amount is 0.
+func RealizedSlippageBps(quoted, received Amount) float64 {
+	if quoted.IsZero() {
+		return 0
+	}
+	q := quoted.Float64()
+	return (q - received.Float64()) / q * 10000
+}
+
+// TransactionBalanceChange returns the raw balance change of a mint for a wallet in a transaction, given as
+// the JSON result of getTransaction with the json encoding. The token balances of the wallet accounts are
+// summed. For MintSOL the native balance change is added, excluding the transaction fee when the wallet paid it.
+func TransactionBalanceChange(tx json.RawMessage, owner, mint string) (Amount, error) {
+	type tokenBalance struct {
+		Mint          string `json:"mint"`
+		Owner         string `json:"owner"`
+		UITokenAmount struct {
+			Amount Amount `json:"amount"`
+		} `json:"uiTokenAmount"`
+	}
+	var result struct {
+		Meta struct {
+			Fee               uint64         `json:"fee"`
+			PreBalances       []uint64       `json:"preBalances"`
+			PostBalances      []uint64       `json:"postBalances"`
+			PreTokenBalances  []tokenBalance `json:"preTokenBalances"`
+			PostTokenBalances []tokenBalance `json:"postTokenBalances"`
+		} `json:"meta"`
+		Transaction struct {
+			Message struct {
+				AccountKeys []string `json:"accountKeys"`
+			} `json:"message"`
+		} `json:"transaction"`
+	}
+	if err := json.Unmarshal(tx, &result); err != nil {
+		return Amount{}, fmt.Errorf("failed to decode transaction: %w", err)
+	}
+
+	var change Amount
+	for _, b := range result.Meta.PostTokenBalances {
+		if b.Owner == owner && b.Mint == mint {
+			change = change.Add(b.UITokenAmount.Amount)
+		}
+	}
+	for _, b := range result.Meta.PreTokenBalances {
+		if b.Owner == owner && b.Mint == mint {
+			change = change.Sub(b.UITokenAmount.Amount)
+		}
+	}
+
+	if mint == MintSOL {
+		meta := result.Meta
+		for i, key := range result.Transaction.Message.AccountKeys {
+			if key != owner || i >= len(meta.PreBalances) || i >= len(meta.PostBalances) {
+				continue
+			}
+			change = change.Add(NewAmount(meta.PostBalances[i])).Sub(NewAmount(meta.PreBalances[i]))
+			if i == 0 {
+				change = change.Add(NewAmount(meta.Fee))
+			}
+			break
+		}
+	}
+	return change, nil
+}
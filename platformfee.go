@@ -0,0 +1,54 @@
+package jupag
+
+import (
+	"context"
+	"fmt"
+)
+
+// FeeAccountResolver returns the token account collecting the platform fee of a mint,
+// e.g. a referral token account, see the referral package.
+type FeeAccountResolver interface {
+	FeeAccount(ctx context.Context, mint string) (string, error)
+}
+
+// platformFee charges a platform fee on the quotes and collects it in the accounts of a resolver.
+type platformFee struct {
+	bps      uint64
+	accounts FeeAccountResolver
+}
+
+// applyPlatformFee sets the platform fee of the quote params when it is not specified.
+func (c *JupagImpl) applyPlatformFee(params *QuoteParams) {
+	if c.platformFee != nil && params.FeeBps == 0 {
+		params.FeeBps = c.platformFee.bps
+	}
+}
+
+// applyFeeAccount sets the fee account of the swap params for the platform fee mint of the route,
+// when the route charges a platform fee and the fee account is not specified.
+func (c *JupagImpl) applyFeeAccount(ctx context.Context, params *SwapParams) error {
+	if c.platformFee == nil || c.platformFee.accounts == nil || params.FeeAccount != "" {
+		return nil
+	}
+
+	mint := platformFeeMint(params.Route)
+	if mint == "" {
+		return nil
+	}
+	account, err := c.platformFee.accounts.FeeAccount(ctx, mint)
+	if err != nil {
+		return fmt.Errorf("failed to resolve fee account of %s: %w", mint, err)
+	}
+	params.FeeAccount = account
+	return nil
+}
+
+// platformFeeMint returns the mint the platform fee of the route is charged in, or "" without platform fee.
+func platformFeeMint(route Route) string {
+	for _, m := range route.MarketInfos {
+		if m.PlatformFee != nil && m.PlatformFee.Mint != "" {
+			return m.PlatformFee.Mint
+		}
+	}
+	return ""
+}
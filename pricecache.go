@@ -0,0 +1,152 @@
+package jupag
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type priceCache struct {
+	ttl    time.Duration
+	flight flightGroup[PriceMap]
+	shared Cache // optional cache shared with other instances, see WithCache
+
+	mu      sync.RWMutex
+	entries map[string]Price // keyed by priceCacheKey
+}
+
+func newPriceCache(ttl time.Duration) *priceCache {
+	return &priceCache{ttl: ttl, entries: make(map[string]Price)}
+}
+
+// get returns the cached prices of the ids, the missing or expired ones are fetched in a single deduplicated call.
+func (pc *priceCache) get(ctx context.Context, params PriceParams, fetch func(context.Context, PriceParams) (PriceMap, error)) (PriceMap, error) {
+	ids := splitIDs(params.IDs)
+	variant := pc.variant(params)
+	result := make(PriceMap, len(ids))
+	missing := make([]string, 0, len(ids))
+
+	pc.mu.RLock()
+	for _, id := range ids {
+		if p, ok := pc.entries[priceCacheKey(id, variant)]; ok && time.Since(p.FetchedAt) < pc.ttl {
+			result[id] = p
+			continue
+		}
+		missing = append(missing, id)
+	}
+	pc.mu.RUnlock()
+
+	if pc.shared != nil && len(missing) > 0 {
+		missing = pc.getShared(ctx, missing, variant, result)
+	}
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	sort.Strings(missing)
+	params.IDs = strings.Join(missing, ",")
+	fetched, err := pc.flight.do(ctx, fmt.Sprintf("%s|%s", params.IDs, variant), func(ctx context.Context) (PriceMap, error) {
+		prices, err := fetch(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+
+		now := time.Now()
+		pc.mu.Lock()
+		for id, p := range prices {
+			if p.FetchedAt.IsZero() {
+				p.FetchedAt = now
+			}
+			if !p.Stale {
+				pc.entries[priceCacheKey(id, variant)] = p
+				if pc.shared != nil {
+					cacheSet(ctx, pc.shared, "price:"+priceCacheKey(id, variant), sharedPrice{Price: p, FetchedAt: p.FetchedAt}, pc.ttl)
+				}
+			}
+			prices[id] = p
+		}
+		pc.mu.Unlock()
+
+		return prices, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for id, p := range fetched {
+		result[id] = p
+	}
+	return result, nil
+}
+
+// sharedPrice is a price stored in the shared cache, FetchedAt isn't part of the Price JSON.
+type sharedPrice struct {
+	Price     Price     `json:"price"`
+	FetchedAt time.Time `json:"fetchedAt"`
+}
+
+// getShared adds the prices of the ids found in the shared cache to result and returns the ids still missing.
+func (pc *priceCache) getShared(ctx context.Context, ids []string, variant string, result PriceMap) []string {
+	missing := ids[:0]
+	for _, id := range ids {
+		key := priceCacheKey(id, variant)
+		sp, ok := cacheGet[sharedPrice](ctx, pc.shared, "price:"+key)
+		if !ok || time.Since(sp.FetchedAt) >= pc.ttl {
+			missing = append(missing, id)
+			continue
+		}
+		sp.Price.FetchedAt = sp.FetchedAt
+		pc.mu.Lock()
+		pc.entries[key] = sp.Price
+		pc.mu.Unlock()
+		result[id] = sp.Price
+	}
+	return missing
+}
+
+// invalidate removes the given ids from the cache, or everything when no id is given.
+// Prices of the shared cache are only removed for the variants cached by this instance.
+func (pc *priceCache) invalidate(ids ...string) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+
+	remove := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		remove[id] = true
+	}
+	for key := range pc.entries {
+		id, _, _ := strings.Cut(key, "|")
+		if len(ids) > 0 && !remove[id] {
+			continue
+		}
+		delete(pc.entries, key)
+		if pc.shared != nil {
+			_ = pc.shared.Delete(context.Background(), cacheKeyPrefix+"price:"+key)
+		}
+	}
+}
+
+// variant identifies the non-id parameters changing the returned prices.
+func (pc *priceCache) variant(params PriceParams) string {
+	return fmt.Sprintf("%s:%g:%t", params.VsToken, params.VsAmount, params.ShowExtraInfo)
+}
+
+func splitIDs(ids string) []string {
+	result := make([]string, 0)
+	for _, id := range strings.Split(ids, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// InvalidatePriceCache removes the given ids from the price cache, or all the cached prices when no id is given.
+func (c *JupagImpl) InvalidatePriceCache(ids ...string) {
+	if c.priceCache != nil {
+		c.priceCache.invalidate(ids...)
+	}
+}
@@ -0,0 +1,89 @@
+package jupag
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// priceIDsPerRequest is the maximum number of ids accepted by the price endpoint in a single call.
+const priceIDsPerRequest = 100
+
+// priceBatchWorkers is the maximum number of concurrent price requests of a batch.
+const priceBatchWorkers = 4
+
+// Prices returns the prices of any number of mints, splitting them into chunks accepted by the price endpoint
+// and requesting the chunks concurrently. The first error cancels the remaining requests.
+func (c *JupagImpl) Prices(ctx context.Context, mints []string) (PriceMap, error) {
+	return c.batchPrices(ctx, mints, PriceParams{})
+}
+
+// batchPrices is Prices using params for everything but the ids.
+func (c *JupagImpl) batchPrices(ctx context.Context, mints []string, params PriceParams) (PriceMap, error) {
+	ids := dedupe(mints)
+	chunks := make([][]string, 0, len(ids)/priceIDsPerRequest+1)
+	for start := 0; start < len(ids); start += priceIDsPerRequest {
+		chunks = append(chunks, ids[start:min(start+priceIDsPerRequest, len(ids))])
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		result   = make(PriceMap, len(ids))
+		jobs     = make(chan []string)
+	)
+	for i := 0; i < min(priceBatchWorkers, len(chunks)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range jobs {
+				p := params
+				p.IDs = strings.Join(chunk, ",")
+				prices, err := c.price(ctx, p)
+
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				for id, price := range prices {
+					result[id] = price
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, chunk := range chunks {
+		select {
+		case jobs <- chunk:
+		case <-ctx.Done():
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return result, ctx.Err()
+}
+
+// dedupe returns the non-empty values in order without duplicates.
+func dedupe(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		result = append(result, v)
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+package jupag
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// maxPriorityFeeAccounts is the maximum number of accounts accepted by getRecentPrioritizationFees.
+const maxPriorityFeeAccounts = 128
+
+// PriorityFeeEstimator estimates the compute unit price, in micro lamports, for transactions locking the given accounts.
+type PriorityFeeEstimator interface {
+	EstimatePriorityFee(ctx context.Context, accounts []string) (int64, error)
+}
+
+// RPCPriorityFeeEstimator estimates priority fees from getRecentPrioritizationFees.
+type RPCPriorityFeeEstimator struct {
+	rpc        RPCClient
+	Percentile float64 // percentile of recent fees, default: 0.75
+	Min        int64   // lower bound of estimations (optional)
+	Max        int64   // upper bound of estimations (optional)
+}
+
+// NewPriorityFeeEstimator returns an estimator selecting the given percentile of recent prioritization fees.
+func NewPriorityFeeEstimator(rpc RPCClient, percentile float64) *RPCPriorityFeeEstimator {
+	if percentile <= 0 || percentile > 1 {
+		percentile = 0.75
+	}
+	return &RPCPriorityFeeEstimator{rpc: rpc, Percentile: percentile}
+}
+
+// EstimatePriorityFee returns the percentile of the fees paid in recent slots by transactions locking the accounts.
+func (e *RPCPriorityFeeEstimator) EstimatePriorityFee(ctx context.Context, accounts []string) (int64, error) {
+	if len(accounts) > maxPriorityFeeAccounts {
+		accounts = accounts[:maxPriorityFeeAccounts]
+	}
+
+	var fees []struct {
+		Slot              uint64 `json:"slot"`
+		PrioritizationFee Int64  `json:"prioritizationFee"`
+	}
+	params := []any{}
+	if len(accounts) > 0 {
+		params = append(params, accounts)
+	}
+	if err := e.rpc.Call(ctx, "getRecentPrioritizationFees", params, &fees); err != nil {
+		return 0, fmt.Errorf("failed to get recent prioritization fees: %w", err)
+	}
+
+	fee := int64(0)
+	if len(fees) > 0 {
+		samples := make([]float64, len(fees))
+		for i, f := range fees {
+			samples[i] = float64(f.PrioritizationFee)
+		}
+		fee = int64(math.Ceil(percentile(samples, e.Percentile)))
+	}
+
+	if fee < e.Min {
+		fee = e.Min
+	}
+	if e.Max > 0 && fee > e.Max {
+		fee = e.Max
+	}
+	return fee, nil
+}
+
+// EstimatePriorityFee estimates the compute unit price for transactions locking the given accounts,
+// using the estimator set by WithPriorityFeeEstimator or the RPC client.
+func (c *JupagImpl) EstimatePriorityFee(ctx context.Context, accounts []string) (int64, error) {
+	estimator := c.feeEstimator
+	if estimator == nil {
+		if c.rpc == nil {
+			return 0, ErrNoRPC
+		}
+		estimator = NewPriorityFeeEstimator(c.rpc, 0)
+	}
+	return estimator.EstimatePriorityFee(ctx, accounts)
+}
+
+// applyPriorityFee sets the compute unit price of the swap params from their estimator when it is not specified.
+func applyPriorityFee(ctx context.Context, params *SwapParams) error {
+	if params.PriorityFeeEstimator == nil || params.ComputeUnitPriceMicroLamports.IsSet() || params.PrioritizationFeeLamports.IsSet() {
+		return nil
+	}
+
+	accounts := make([]string, 0, len(params.Route.MarketInfos)+1)
+	if params.UserPublicKey != "" {
+		accounts = append(accounts, params.UserPublicKey)
+	}
+	for _, m := range params.Route.MarketInfos {
+		accounts = append(accounts, m.ID)
+	}
+
+	fee, err := params.PriorityFeeEstimator.EstimatePriorityFee(ctx, accounts)
+	if err != nil {
+		return fmt.Errorf("failed to estimate priority fee: %w", err)
+	}
+	params.ComputeUnitPriceMicroLamports = FixedFee(fee)
+	return nil
+}
@@ -0,0 +1,86 @@
+package jupag
+
+import (
+	"fmt"
+
+	"github.com/ipanardian/go-jup-ag/utils"
+)
+
+// PublicKey is a 32 bytes Solana account address.
+type PublicKey [32]byte
+
+// Well-known program ids.
+var (
+	SystemProgramID          = MustPublicKey("11111111111111111111111111111111")
+	ComputeBudgetProgramID   = MustPublicKey("ComputeBudget111111111111111111111111111111")
+	TokenProgramID           = MustPublicKey("TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA")
+	Token2022ProgramID       = MustPublicKey("TokenzQdBNbLqP5VEhdkAS6EPFLC1PHnBqCXEpPxuEb")
+	AssociatedTokenProgramID = MustPublicKey("ATokenGPvbdGVxr1b2hvZbsiqW5xWH25efTNsLJA8knL")
+	JupiterV6ProgramID       = MustPublicKey("JUP6LkbZbjS1jKKwapdHNy74zcZ3tLUZoi5QNyVTaV4")
+)
+
+var programLabels = map[PublicKey]string{
+	SystemProgramID:          "System",
+	ComputeBudgetProgramID:   "ComputeBudget",
+	TokenProgramID:           "Token",
+	Token2022ProgramID:       "Token2022",
+	AssociatedTokenProgramID: "AssociatedToken",
+	JupiterV6ProgramID:       "JupiterV6",
+}
+
+// ParsePublicKey parses a base58 encoded public key.
+func ParsePublicKey(s string) (PublicKey, error) {
+	b, err := utils.Base58Decode(s)
+	if err != nil {
+		return PublicKey{}, fmt.Errorf("invalid public key %q: %w", s, err)
+	}
+	if len(b) != 32 {
+		return PublicKey{}, fmt.Errorf("invalid public key %q: expected 32 bytes, got %d", s, len(b))
+	}
+
+	var pk PublicKey
+	copy(pk[:], b)
+	return pk, nil
+}
+
+// MustPublicKey is like ParsePublicKey but panics on invalid input.
+func MustPublicKey(s string) PublicKey {
+	pk, err := ParsePublicKey(s)
+	if err != nil {
+		panic(err)
+	}
+	return pk
+}
+
+// String returns the base58 encoded public key.
+func (pk PublicKey) String() string {
+	return utils.Base58Encode(pk[:])
+}
+
+// Label returns a human readable name for well-known programs, or the base58 key otherwise.
+func (pk PublicKey) Label() string {
+	if label, ok := programLabels[pk]; ok {
+		return label
+	}
+	return pk.String()
+}
+
+// IsZero reports whether the public key is all zeros.
+func (pk PublicKey) IsZero() bool {
+	return pk == PublicKey{}
+}
+
+// MarshalText encodes the public key in base58.
+func (pk PublicKey) MarshalText() ([]byte, error) {
+	return []byte(pk.String()), nil
+}
+
+// UnmarshalText decodes a base58 encoded public key.
+func (pk *PublicKey) UnmarshalText(text []byte) error {
+	v, err := ParsePublicKey(string(text))
+	if err != nil {
+		return err
+	}
+	*pk = v
+	return nil
+}
@@ -0,0 +1,130 @@
+package jupag
+
+import (
+	"context"
+	"errors"
+	"math/big"
+)
+
+// EffectivePrice returns the out amount received per unit of in amount, in raw units.
+// Multiply by 10^(input decimals - output decimals) to get the UI price. It returns 0 when the in amount is 0.
+func (r Route) EffectivePrice() float64 {
+	if r.InAmount.IsZero() {
+		return 0
+	}
+	price, _ := new(big.Rat).SetFrac(r.OutAmount.BigInt(), r.InAmount.BigInt()).Float64()
+	return price
+}
+
+// TotalFees returns the LP and platform fees of all the route legs, aggregated by mint.
+func (r Route) TotalFees() map[string]Amount {
+	fees := make(map[string]Amount)
+	for _, m := range r.MarketInfos {
+		for _, fee := range []*Fee{m.LpFee, m.PlatformFee} {
+			if fee == nil || fee.Amount.IsZero() {
+				continue
+			}
+			fees[fee.Mint] = fees[fee.Mint].Add(fee.Amount)
+		}
+	}
+	return fees
+}
+
+// MinimumReceived returns the minimum out amount for the given slippage, ExactOut routes always receive the out amount.
+func (r Route) MinimumReceived(slippageBps uint64) Amount {
+	if r.SwapMode == SwapModeExactOut {
+		return r.OutAmount
+	}
+	return r.OutAmount.ApplyBps(slippageBps)
+}
+
+// EffectivePrice returns the effective price of the best route, see Route.EffectivePrice.
+func (q QuoteResponse) EffectivePrice() float64 {
+	route, err := q.GetBestRoute()
+	if err != nil {
+		return 0
+	}
+	return route.EffectivePrice()
+}
+
+// TotalFees returns the fees of the best route aggregated by mint, see Route.TotalFees.
+func (q QuoteResponse) TotalFees() map[string]Amount {
+	route, err := q.GetBestRoute()
+	if err != nil {
+		return map[string]Amount{}
+	}
+	return route.TotalFees()
+}
+
+// MinimumReceived returns the minimum out amount of the best route for the given slippage, see Route.MinimumReceived.
+func (q QuoteResponse) MinimumReceived(slippageBps uint64) Amount {
+	route, err := q.GetBestRoute()
+	if err != nil {
+		return Amount{}
+	}
+	return route.MinimumReceived(slippageBps)
+}
+
+// USDValuer values raw token amounts in USD, e.g. the client with the price API.
+type USDValuer interface {
+	USDValue(ctx context.Context, mint string, amount Amount) (float64, error)
+}
+
+// QuoteValuation is the USD value of the amounts and costs of a route.
+type QuoteValuation struct {
+	InputUSD       float64
+	OutputUSD      float64
+	RouteFeesUSD   float64 // LP and platform fees of all the legs
+	NetworkFeeUSD  float64 // signature fees
+	PriorityFeeUSD float64
+	TotalFeesUSD   float64 // route, network and priority fees
+}
+
+// ValueUSD values the amounts and fees of the route in USD. priorityFeeLamports is the expected priority fee,
+// e.g. SwapResponse.PrioritizationFeeLamports, 0 if none. The network fee is one signature without fee estimate.
+func (r Route) ValueUSD(ctx context.Context, valuer USDValuer, priorityFeeLamports uint64) (QuoteValuation, error) {
+	var v QuoteValuation
+	if len(r.MarketInfos) == 0 {
+		return v, errors.New("route has no market")
+	}
+
+	var err error
+	if v.InputUSD, err = valuer.USDValue(ctx, r.MarketInfos[0].InputMint, r.InAmount); err != nil {
+		return v, err
+	}
+	if v.OutputUSD, err = valuer.USDValue(ctx, r.MarketInfos[len(r.MarketInfos)-1].OutputMint, r.OutAmount); err != nil {
+		return v, err
+	}
+	for mint, amount := range r.TotalFees() {
+		usd, err := valuer.USDValue(ctx, mint, amount)
+		if err != nil {
+			return v, err
+		}
+		v.RouteFeesUSD += usd
+	}
+
+	signatureFee := uint64(signatureFeeLamports)
+	if r.Fees != nil && r.Fees.SignatureFee > 0 {
+		signatureFee = uint64(r.Fees.SignatureFee)
+	}
+	if v.NetworkFeeUSD, err = valuer.USDValue(ctx, MintSOL, NewAmount(signatureFee)); err != nil {
+		return v, err
+	}
+	if priorityFeeLamports > 0 {
+		if v.PriorityFeeUSD, err = valuer.USDValue(ctx, MintSOL, NewAmount(priorityFeeLamports)); err != nil {
+			return v, err
+		}
+	}
+
+	v.TotalFeesUSD = v.RouteFeesUSD + v.NetworkFeeUSD + v.PriorityFeeUSD
+	return v, nil
+}
+
+// ValueUSD values the amounts and fees of the best route in USD, see Route.ValueUSD.
+func (q QuoteResponse) ValueUSD(ctx context.Context, valuer USDValuer, priorityFeeLamports uint64) (QuoteValuation, error) {
+	route, err := q.GetBestRoute()
+	if err != nil {
+		return QuoteValuation{}, err
+	}
+	return route.ValueUSD(ctx, valuer, priorityFeeLamports)
+}
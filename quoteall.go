@@ -0,0 +1,62 @@
+package jupag
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// QuoteAllOptions configures QuoteAll.
+type QuoteAllOptions struct {
+	Concurrency int           // maximum number of concurrent quote requests, default: 8
+	Budget      time.Duration // latency budget of the whole batch, requests still running are cancelled (optional)
+}
+
+// QuoteResult is the result of a single quote of QuoteAll.
+type QuoteResult struct {
+	Params  QuoteParams
+	Quotes  QuoteResponse
+	Err     error
+	Latency time.Duration
+}
+
+// QuoteAll fetches many quotes concurrently with bounded parallelism.
+// Results are returned in the order of params, each with its own error.
+func (c *JupagImpl) QuoteAll(ctx context.Context, params []QuoteParams, opts QuoteAllOptions) []QuoteResult {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 8
+	}
+	if opts.Budget > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Budget)
+		defer cancel()
+	}
+
+	results := make([]QuoteResult, len(params))
+	sem := make(chan struct{}, opts.Concurrency)
+	var wg sync.WaitGroup
+
+	for i, p := range params {
+		results[i].Params = p
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, p QuoteParams) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			start := time.Now()
+			results[i].Quotes, results[i].Err = c.quote(ctx, p)
+			results[i].Latency = time.Since(start)
+		}(i, p)
+	}
+	wg.Wait()
+
+	return results
+}
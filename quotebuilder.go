@@ -0,0 +1,173 @@
+package jupag
+
+import (
+	"errors"
+	"fmt"
+)
+
+// QuoteBuilder is a fluent builder of validated QuoteParams.
+//
+//	params, err := jupag.NewQuote().Input("USDC").Output("SOL").AmountUI(100).Slippage(50).OnlyDirect().Build()
+type QuoteBuilder struct {
+	params   QuoteParams
+	input    string
+	output   string
+	amountUI *float64
+	decimals *uint8
+	errs     []error
+}
+
+// NewQuote returns a quote builder.
+func NewQuote() *QuoteBuilder {
+	return &QuoteBuilder{params: QuoteParams{SwapMode: SwapModeExactIn}}
+}
+
+// Input sets the input token, as a well-known symbol or a mint address.
+func (b *QuoteBuilder) Input(symbolOrMint string) *QuoteBuilder {
+	b.input = symbolOrMint
+	return b
+}
+
+// Output sets the output token, as a well-known symbol or a mint address.
+func (b *QuoteBuilder) Output(symbolOrMint string) *QuoteBuilder {
+	b.output = symbolOrMint
+	return b
+}
+
+// Amount sets the raw amount of the input token (or output token for ExactOut).
+func (b *QuoteBuilder) Amount(amount uint64) *QuoteBuilder {
+	b.params.Amount = amount
+	b.amountUI = nil
+	return b
+}
+
+// AmountUI sets the amount in UI units, converted with the decimals of the well-known tokens or the ones set with Decimals.
+func (b *QuoteBuilder) AmountUI(amount float64) *QuoteBuilder {
+	b.amountUI = &amount
+	b.params.Amount = 0
+	return b
+}
+
+// Decimals sets the decimals used to convert the UI amount, required for tokens that aren't well-known.
+func (b *QuoteBuilder) Decimals(decimals uint8) *QuoteBuilder {
+	b.decimals = &decimals
+	return b
+}
+
+// ExactOut interprets the amount as the amount of output token.
+func (b *QuoteBuilder) ExactOut() *QuoteBuilder {
+	b.params.SwapMode = SwapModeExactOut
+	return b
+}
+
+// Slippage sets the slippage tolerance in basis points.
+func (b *QuoteBuilder) Slippage(bps uint64) *QuoteBuilder {
+	if bps > 10000 {
+		b.errs = append(b.errs, fmt.Errorf("slippage %d bps exceeds 10000", bps))
+	}
+	b.params.SlippageBps = bps
+	return b
+}
+
+// Fee charges a platform fee in basis points.
+func (b *QuoteBuilder) Fee(bps uint64) *QuoteBuilder {
+	if bps > 10000 {
+		b.errs = append(b.errs, fmt.Errorf("fee %d bps exceeds 10000", bps))
+	}
+	b.params.FeeBps = bps
+	return b
+}
+
+// OnlyDirect only returns direct routes.
+func (b *QuoteBuilder) OnlyDirect() *QuoteBuilder {
+	b.params.OnlyDirectRoutes = true
+	return b
+}
+
+// AsLegacyTransaction only returns routes that fit in a legacy transaction.
+func (b *QuoteBuilder) AsLegacyTransaction() *QuoteBuilder {
+	b.params.AsLegacyTransaction = true
+	return b
+}
+
+// MaxAccounts limits the accounts of the route, to keep the transaction small enough.
+func (b *QuoteBuilder) MaxAccounts(n uint64) *QuoteBuilder {
+	b.params.MaxAccounts = n
+	return b
+}
+
+// User sets the public key of the user, to get the deposit and fees returned.
+func (b *QuoteBuilder) User(publicKey string) *QuoteBuilder {
+	if _, err := ParsePublicKey(publicKey); err != nil {
+		b.errs = append(b.errs, fmt.Errorf("invalid user public key: %w", err))
+	}
+	b.params.UserPublicKey = publicKey
+	return b
+}
+
+// Build validates and returns the quote params, all the validation errors are joined.
+func (b *QuoteBuilder) Build() (QuoteParams, error) {
+	errs := append([]error(nil), b.errs...)
+	params := b.params
+
+	var err error
+	if params.InputMint, err = staticMint("input", b.input); err != nil {
+		errs = append(errs, err)
+	}
+	if params.OutputMint, err = staticMint("output", b.output); err != nil {
+		errs = append(errs, err)
+	}
+	if params.InputMint != "" && params.InputMint == params.OutputMint {
+		errs = append(errs, errors.New("input and output tokens are the same"))
+	}
+
+	amountMint := params.InputMint
+	if params.SwapMode == SwapModeExactOut {
+		amountMint = params.OutputMint
+	}
+	switch {
+	case b.amountUI == nil:
+		if params.Amount == 0 {
+			errs = append(errs, errors.New("amount is required"))
+		}
+	case amountMint == "":
+		// the UI amount can't be converted without a valid token
+	default:
+		if params.Amount, err = b.rawAmount(amountMint); err != nil {
+			errs = append(errs, err)
+		} else if params.Amount == 0 {
+			errs = append(errs, errors.New("amount is required"))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return QuoteParams{}, fmt.Errorf("invalid quote: %w", err)
+	}
+	return params, nil
+}
+
+func (b *QuoteBuilder) rawAmount(mint string) (uint64, error) {
+	if b.decimals != nil {
+		return FromUIAmount(*b.amountUI, *b.decimals)
+	}
+	for _, t := range wellKnownTokens {
+		if t.mint == mint {
+			return FromUIAmount(*b.amountUI, t.decimals)
+		}
+	}
+	return 0, fmt.Errorf("decimals of %s are unknown, set them with Decimals", mint)
+}
+
+// staticMint resolves a well-known symbol or mint address without calling the token API.
+func staticMint(side, symbolOrMint string) (string, error) {
+	if symbolOrMint == "" {
+		return "", fmt.Errorf("%s token is required", side)
+	}
+	if mint, ok := wellKnownMint(symbolOrMint); ok {
+		return mint, nil
+	}
+	if _, err := ParsePublicKey(symbolOrMint); err != nil {
+		return "", fmt.Errorf("%w %q, use a well-known symbol or a mint address", ErrUnknownToken, symbolOrMint)
+	}
+	return symbolOrMint, nil
+}
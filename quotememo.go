@@ -0,0 +1,90 @@
+package jupag
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ipanardian/go-jup-ag/utils"
+)
+
+// quoteMemoSweepSize is the number of memoized quotes above which the expired ones are removed on insert.
+const quoteMemoSweepSize = 1024
+
+type quoteMemoEntry struct {
+	quotes    QuoteResponse
+	slot      uint64 // context slot of the quotes, 0 when unknown
+	fetchedAt time.Time
+}
+
+// quoteMemo reuses the quotes of the same parameters within a bucket of slots, see WithQuoteMemo.
+// The current slot is extrapolated from the latest context slot returned by the API, without RPC call.
+type quoteMemo struct {
+	bucketSlots uint64
+
+	mu      sync.Mutex
+	entries map[string]quoteMemoEntry
+	slot    uint64 // latest context slot seen
+	slotAt  time.Time
+}
+
+func newQuoteMemo(bucketSlots uint64) *quoteMemo {
+	if bucketSlots == 0 {
+		bucketSlots = 1
+	}
+	return &quoteMemo{bucketSlots: bucketSlots, entries: make(map[string]quoteMemoEntry)}
+}
+
+// currentSlot returns the estimated current slot, 0 when no context slot was seen yet.
+func (m *quoteMemo) currentSlot() uint64 {
+	if m.slotAt.IsZero() {
+		return 0
+	}
+	return m.slot + uint64(time.Since(m.slotAt)/slotDuration)
+}
+
+// valid reports whether the entry is in the current slot bucket, or younger than a bucket when its slot is unknown.
+func (m *quoteMemo) valid(entry quoteMemoEntry) bool {
+	if current := m.currentSlot(); entry.slot > 0 && current > 0 {
+		return current/m.bucketSlots == entry.slot/m.bucketSlots
+	}
+	return time.Since(entry.fetchedAt) < time.Duration(m.bucketSlots)*slotDuration
+}
+
+// get returns the memoized quotes of the parameters, fetching them when missing or expired.
+// Failed quotes aren't memoized.
+func (m *quoteMemo) get(params QuoteParams, fetch func() (QuoteResponse, error)) (QuoteResponse, error) {
+	uv, err := utils.StructToUrlValues(params)
+	if err != nil {
+		return fetch()
+	}
+	key := uv.Encode()
+
+	m.mu.Lock()
+	entry, ok := m.entries[key]
+	if ok && m.valid(entry) {
+		m.mu.Unlock()
+		return entry.quotes.clone(), nil
+	}
+	m.mu.Unlock()
+
+	quotes, err := fetch()
+	if err != nil {
+		return quotes, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry = quoteMemoEntry{quotes: quotes.clone(), slot: quotes.ContextSlot(), fetchedAt: time.Now()}
+	if entry.slot > m.currentSlot() {
+		m.slot, m.slotAt = entry.slot, entry.fetchedAt
+	}
+	if len(m.entries) >= quoteMemoSweepSize {
+		for k, e := range m.entries {
+			if !m.valid(e) {
+				delete(m.entries, k)
+			}
+		}
+	}
+	m.entries[key] = entry
+	return quotes, nil
+}
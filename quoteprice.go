@@ -0,0 +1,90 @@
+package jupag
+
+import (
+	"context"
+	"strings"
+)
+
+// PriceTypeQuote is the Price.Type of the prices derived from a quote by QuoteAsPrice.
+const PriceTypeQuote = "quote"
+
+// QuoteAsPrice returns the prices of the price API, completed for the tokens it fails on or lacks by a reference
+// quote selling params.VsAmount (default 1) of the token for the vs token (default USDC), so long-tail tokens
+// still get a price. Tokens without route are omitted. It only fails when neither the price API nor any quote succeeded.
+func (c *JupagImpl) QuoteAsPrice(ctx context.Context, params PriceParams) (PriceMap, error) {
+	ids := dedupe(strings.Split(params.IDs, ","))
+	prices, priceErr := c.price(ctx, params)
+	if prices == nil {
+		prices = make(PriceMap, len(ids))
+	}
+
+	for _, id := range ids {
+		if p, ok := prices[id]; ok && !p.decimal().IsZero() {
+			continue
+		}
+		price, err := c.quotePrice(ctx, id, params)
+		if err != nil {
+			continue
+		}
+		prices[id] = price
+	}
+
+	if priceErr != nil && len(prices) == 0 {
+		return nil, priceErr
+	}
+	return prices, nil
+}
+
+// quotePrice derives the price of a token from a quote of its reference amount into the vs token.
+func (c *JupagImpl) quotePrice(ctx context.Context, id string, params PriceParams) (Price, error) {
+	mint, err := c.ResolveMint(ctx, id)
+	if err != nil {
+		return Price{}, err
+	}
+	vsToken := params.VsToken
+	if vsToken == "" {
+		vsToken = MintUSDC
+	}
+	vsMint, err := c.ResolveMint(ctx, vsToken)
+	if err != nil {
+		return Price{}, err
+	}
+	decimals, err := c.Decimals(ctx, mint)
+	if err != nil {
+		return Price{}, err
+	}
+	vsDecimals, err := c.Decimals(ctx, vsMint)
+	if err != nil {
+		return Price{}, err
+	}
+
+	vsAmount := params.VsAmount
+	if vsAmount <= 0 {
+		vsAmount = 1
+	}
+	amount, err := FromUIAmount(vsAmount, decimals)
+	if err != nil {
+		return Price{}, err
+	}
+	quotes, err := c.quote(ctx, QuoteParams{InputMint: mint, OutputMint: vsMint, Amount: amount, SwapMode: SwapModeExactIn})
+	if err != nil {
+		return Price{}, err
+	}
+	route, err := quotes.GetBestRoute()
+	if err != nil {
+		return Price{}, err
+	}
+	price, err := RoutePrice(route, decimals, vsDecimals)
+	if err != nil {
+		return Price{}, err
+	}
+
+	return Price{
+		ID:      mint,
+		VsToken: vsMint,
+		Price:   price.String(),
+		Type:    PriceTypeQuote,
+
+		PriceDecimal: price,
+	}, nil
+}
@@ -0,0 +1,36 @@
+package jupag
+
+import (
+	"context"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimiter limits the rate of API requests, e.g. a *rate.Limiter.
+type RateLimiter interface {
+	Wait(ctx context.Context) error // blocks until a request may be sent
+	Allow() bool                    // reports whether a request may be sent now, consuming a token if so
+}
+
+// NewRateLimiter returns a token bucket limiter of rps requests per second with the given burst.
+func NewRateLimiter(rps float64, burst int) RateLimiter {
+	return rate.NewLimiter(rate.Limit(rps), burst)
+}
+
+type rateReservedKey struct{}
+
+// withRateReserved returns a context of a request whose token was already taken from the limiter.
+func withRateReserved(ctx context.Context) context.Context {
+	return context.WithValue(ctx, rateReservedKey{}, true)
+}
+
+// waitRate waits for the rate limiter unless the request already took its token.
+func (c *JupagImpl) waitRate(ctx context.Context) error {
+	if c.limiter == nil {
+		return nil
+	}
+	if reserved, _ := ctx.Value(rateReservedKey{}).(bool); reserved {
+		return nil
+	}
+	return c.limiter.Wait(ctx)
+}
@@ -0,0 +1,106 @@
+package jupag
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+)
+
+type rawCaptureKey struct{}
+
+// rawCapture holds the body of the first response fully read with a capturing context.
+type rawCapture struct {
+	mu   sync.Mutex
+	body json.RawMessage
+}
+
+// withRawCapture returns a context capturing the body of the successful response of the requests made with it.
+func withRawCapture(ctx context.Context) (context.Context, *rawCapture) {
+	capture := &rawCapture{}
+	return context.WithValue(ctx, rawCaptureKey{}, capture), capture
+}
+
+func (r *rawCapture) raw() json.RawMessage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.body
+}
+
+// captureBody makes the body of a successful response recorded once it's read to the end,
+// so the raw payload of hedged requests is the one that was decoded.
+func captureBody(ctx context.Context, resp *http.Response) {
+	capture, _ := ctx.Value(rawCaptureKey{}).(*rawCapture)
+	if capture == nil || resp == nil || resp.StatusCode != http.StatusOK {
+		return
+	}
+	resp.Body = &capturingBody{ReadCloser: resp.Body, capture: capture}
+}
+
+type capturingBody struct {
+	io.ReadCloser
+	capture *rawCapture
+	buf     bytes.Buffer
+}
+
+func (b *capturingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+	if err == io.EOF {
+		b.record()
+	}
+	return n, err
+}
+
+// Close records the body when it was decoded without reading to EOF, e.g. a trailing newline left by a json.Decoder.
+func (b *capturingBody) Close() error {
+	if b.buf.Len() > 0 {
+		if _, err := b.buf.ReadFrom(b.ReadCloser); err == nil {
+			b.record()
+		}
+	}
+	return b.ReadCloser.Close()
+}
+
+func (b *capturingBody) record() {
+	b.capture.mu.Lock()
+	defer b.capture.mu.Unlock()
+	if b.capture.body == nil {
+		b.capture.body = json.RawMessage(bytes.Clone(bytes.TrimSpace(b.buf.Bytes())))
+	}
+}
+
+// QuoteRaw returns a quote along with the response body it was decoded from, e.g. to persist it for audits.
+func (c *JupagImpl) QuoteRaw(ctx context.Context, params QuoteParams) (QuoteResponse, json.RawMessage, error) {
+	ctx, capture := withRawCapture(ctx)
+	quote, err := c.quote(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	return quote, capture.raw(), nil
+}
+
+// PriceRaw returns prices along with the response body they were decoded from, it bypasses the price cache.
+func (c *JupagImpl) PriceRaw(ctx context.Context, params PriceParams) (PriceMap, json.RawMessage, error) {
+	if err := params.Validate(); err != nil {
+		return nil, nil, err
+	}
+	ctx, capture := withRawCapture(ctx)
+	price, err := c.fetchPrice(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	return price, capture.raw(), nil
+}
+
+// SwapRaw returns a swap transaction along with the response body it was decoded from.
+func (c *JupagImpl) SwapRaw(ctx context.Context, params SwapParams) (SwapResponse, json.RawMessage, error) {
+	ctx, capture := withRawCapture(ctx)
+	swap, err := c.swap(ctx, params)
+	if err != nil {
+		return SwapResponse{}, nil, err
+	}
+	return swap, capture.raw(), nil
+}
@@ -0,0 +1,80 @@
+package jupag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrReceivedBelowMinimum = errors.New("received amount below minimum")
+
+// transactionFetchAttempts is the number of getTransaction calls until a just confirmed transaction is available.
+const transactionFetchAttempts = 10
+
+// ReceivedBelowMinimumError is returned when a swap would or did receive less than the minimum output,
+// it matches ErrReceivedBelowMinimum.
+type ReceivedBelowMinimumError struct {
+	Mint     string
+	MinOut   Amount // raw amount
+	Received Amount // raw amount: the quote worst case before submission, the realized output after confirmation
+	Realized bool   // whether Received is the realized output of a landed transaction
+}
+
+func (e *ReceivedBelowMinimumError) Error() string {
+	what := "quoted minimum"
+	if e.Realized {
+		what = "received"
+	}
+	return fmt.Sprintf("received amount below minimum: %s %s of %s, minimum %s", what, e.Received, e.Mint, e.MinOut)
+}
+
+func (e *ReceivedBelowMinimumError) Unwrap() error {
+	return ErrReceivedBelowMinimum
+}
+
+// checkMinOut returns a ReceivedBelowMinimumError when the worst case output of the route is below minOut:
+// the other amount threshold for ExactIn, the output amount for ExactOut.
+func checkMinOut(route Route, outputMint string, minOut uint64) error {
+	if minOut == 0 {
+		return nil
+	}
+	worst := route.OtherAmountThreshold
+	if route.SwapMode == SwapModeExactOut {
+		worst = route.OutAmount
+	}
+	if worst.Cmp(NewAmount(minOut)) < 0 {
+		return &ReceivedBelowMinimumError{Mint: outputMint, MinOut: NewAmount(minOut), Received: worst}
+	}
+	return nil
+}
+
+// ReceivedAmount returns the raw amount of a mint received by a wallet in a confirmed transaction, from the balance
+// changes of its token accounts. For MintSOL the native balance change is added, excluding the transaction fee
+// when the wallet paid it. It waits for the transaction to be available to the RPC node.
+func ReceivedAmount(ctx context.Context, rpc RPCClient, signature, owner, mint string) (Amount, error) {
+	params := []any{signature, map[string]any{
+		"encoding":                       "json",
+		"commitment":                     CommitmentConfirmed,
+		"maxSupportedTransactionVersion": 0,
+	}}
+	var tx json.RawMessage
+	for i := 0; ; i++ {
+		if err := rpc.Call(ctx, "getTransaction", params, &tx); err != nil {
+			return Amount{}, fmt.Errorf("failed to get transaction: %w", err)
+		}
+		if len(tx) > 0 && string(tx) != "null" {
+			break
+		}
+		if i+1 >= transactionFetchAttempts {
+			return Amount{}, fmt.Errorf("transaction %s not found", signature)
+		}
+		select {
+		case <-ctx.Done():
+			return Amount{}, ctx.Err()
+		case <-time.After(confirmationPollInterval):
+		}
+	}
+	return TransactionBalanceChange(tx, owner, mint)
+}
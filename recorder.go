@@ -0,0 +1,162 @@
+package jupag
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RecordKind is the kind of response of a Record.
+type RecordKind string
+
+const (
+	RecordQuote RecordKind = "quote"
+	RecordPrice RecordKind = "price"
+)
+
+// Record is a quote or price response of the API, as recorded by a Recorder.
+type Record struct {
+	Time        time.Time     `json:"time"` // time the response was received
+	Kind        RecordKind    `json:"kind"`
+	ContextSlot uint64        `json:"contextSlot,omitempty"` // slot of the data of the response, 0 when unknown
+	QuoteParams *QuoteParams  `json:"quoteParams,omitempty"`
+	Quote       QuoteResponse `json:"quote,omitempty"`
+	PriceParams *PriceParams  `json:"priceParams,omitempty"`
+	Prices      PriceMap      `json:"prices,omitempty"`
+}
+
+// RecordSink writes the records of a Recorder, e.g. to a file or a database. Writes are serialized by the recorder.
+type RecordSink interface {
+	WriteRecord(r Record) error
+}
+
+// Recorder records every quote and price response of the API to a sink, e.g. to analyse slippage or backtest
+// strategies on production traffic. Responses served from the caches and memos are not recorded again.
+// Pass it to WithRecorder.
+type Recorder struct {
+	mu   sync.Mutex
+	sink RecordSink
+}
+
+// NewRecorder returns a recorder writing to sink.
+func NewRecorder(sink RecordSink) *Recorder {
+	return &Recorder{sink: sink}
+}
+
+// Record writes a record to the sink.
+func (r *Recorder) Record(record Record) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.sink.WriteRecord(record)
+}
+
+// JSONLinesSink writes each record as a JSON object on its own line.
+type JSONLinesSink struct {
+	enc *json.Encoder
+}
+
+// NewJSONLinesSink returns a sink writing JSON lines to w.
+func NewJSONLinesSink(w io.Writer) *JSONLinesSink {
+	return &JSONLinesSink{enc: json.NewEncoder(w)}
+}
+
+func (s *JSONLinesSink) WriteRecord(r Record) error {
+	return s.enc.Encode(r)
+}
+
+// csvHeader are the columns of a CSVSink. Quote rows fill the quote columns, price rows the price columns.
+var csvHeader = []string{
+	"time", "kind", "contextSlot",
+	"inputMint", "outputMint", "amount", "swapMode", "slippageBps", "route",
+	"inAmount", "outAmount", "otherAmountThreshold", "priceImpactPct",
+	"mint", "vsToken", "price",
+}
+
+// CSVSink writes a row per route of the quotes and per token of the prices, under a header row.
+type CSVSink struct {
+	w      *csv.Writer
+	header bool
+}
+
+// NewCSVSink returns a sink writing CSV to w. The header is written with the first record, so w should be empty.
+func NewCSVSink(w io.Writer) *CSVSink {
+	return &CSVSink{w: csv.NewWriter(w)}
+}
+
+func (s *CSVSink) WriteRecord(r Record) error {
+	if !s.header {
+		if err := s.w.Write(csvHeader); err != nil {
+			return err
+		}
+		s.header = true
+	}
+
+	slot := ""
+	if r.ContextSlot > 0 {
+		slot = strconv.FormatUint(r.ContextSlot, 10)
+	}
+	prefix := []string{r.Time.UTC().Format(time.RFC3339Nano), string(r.Kind), slot}
+
+	switch r.Kind {
+	case RecordQuote:
+		var p QuoteParams
+		if r.QuoteParams != nil {
+			p = *r.QuoteParams
+		}
+		for i, route := range r.Quote {
+			swapMode := route.SwapMode
+			if swapMode == "" {
+				swapMode = p.SwapMode
+			}
+			row := append(append([]string(nil), prefix...),
+				p.InputMint, p.OutputMint, strconv.FormatUint(p.Amount, 10), swapMode,
+				strconv.FormatInt(route.SlippageBps, 10), strconv.Itoa(i),
+				route.InAmount.String(), route.OutAmount.String(), route.OtherAmountThreshold.String(),
+				exactDecimal(route.PriceImpactPctDecimal, route.PriceImpactPct).String(),
+				"", "", "",
+			)
+			if err := s.w.Write(row); err != nil {
+				return err
+			}
+		}
+	case RecordPrice:
+		mints := make([]string, 0, len(r.Prices))
+		for mint := range r.Prices {
+			mints = append(mints, mint)
+		}
+		sort.Strings(mints)
+		for _, mint := range mints {
+			price := r.Prices[mint]
+			row := append(append([]string(nil), prefix...),
+				"", "", "", "", "", "", "", "", "", "",
+				mint, price.VsToken, price.Price,
+			)
+			if err := s.w.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	s.w.Flush()
+	return s.w.Error()
+}
+
+// record writes a response to the recorder if any, failures are logged and don't affect the response.
+func (c *JupagImpl) record(ctx context.Context, r Record) {
+	if c.recorder == nil {
+		return
+	}
+	r.Time = time.Now()
+	if err := c.recorder.Record(r); err != nil && c.logger != nil {
+		c.logger.LogAttrs(ctx, slog.LevelWarn, "jupiter api response not recorded",
+			slog.String("kind", string(r.Kind)),
+			slog.String("error", err.Error()),
+		)
+	}
+}
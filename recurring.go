@@ -0,0 +1,308 @@
+package jupag
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RecurringOrdersParams are the parameters of a recurring orders request.
+type RecurringOrdersParams struct {
+	User            string     `url:"user"`                      // required; wallet of the orders
+	OrderStatus     OrderState `url:"orderStatus"`               // required; OrdersActive or OrdersHistory
+	RecurringType   string     `url:"recurringType"`             // optional; Default to "time", the time based orders.
+	Page            int        `url:"page,omitempty"`            // optional; Default to 1.
+	IncludeFailedTx bool       `url:"includeFailedTx,omitempty"` // optional; Include the failed executions in the trades.
+}
+
+// RecurringOrdersPage is a page of recurring orders.
+type RecurringOrdersPage struct {
+	Orders     []RecurringOrder `json:"time"`
+	Page       int              `json:"page"`
+	TotalPages int              `json:"totalPages"`
+}
+
+// RecurringOrder is a recurring (DCA) position, swapping InAmountPerCycle of InputMint every CycleFrequency seconds
+// until the deposit is used.
+type RecurringOrder struct {
+	OrderKey         string           `json:"orderKey"`
+	UserPubkey       string           `json:"userPubkey"`
+	InputMint        string           `json:"inputMint"`
+	OutputMint       string           `json:"outputMint"`
+	InDeposited      Amount           `json:"rawInDeposited"`
+	InWithdrawn      Amount           `json:"rawInWithdrawn"`
+	InUsed           Amount           `json:"rawInUsed"`
+	OutReceived      Amount           `json:"rawOutReceived"`
+	OutWithdrawn     Amount           `json:"rawOutWithdrawn"`
+	InAmountPerCycle Amount           `json:"rawInAmountPerCycle"`
+	CycleFrequency   Int64            `json:"cycleFrequency"` // seconds between the cycles
+	UserClosed       bool             `json:"userClosed"`     // closed by the user rather than completed
+	OpenTx           string           `json:"openTx"`
+	CloseTx          string           `json:"closeTx"`
+	CreatedAt        time.Time        `json:"createdAt"`
+	UpdatedAt        time.Time        `json:"updatedAt"`
+	Trades           []RecurringTrade `json:"trades"`
+}
+
+// RecurringTrade is an executed cycle of a recurring order.
+type RecurringTrade struct {
+	TxID         string    `json:"txId"`
+	Action       string    `json:"action"` // e.g. Fill
+	InputAmount  Amount    `json:"rawInputAmount"`
+	OutputAmount Amount    `json:"rawOutputAmount"`
+	FeeMint      string    `json:"feeMint"`
+	FeeAmount    Amount    `json:"rawFeeAmount"`
+	ConfirmedAt  time.Time `json:"confirmedAt"`
+}
+
+// fill reports whether the trade is the execution of a cycle.
+func (t RecurringTrade) fill() bool {
+	return t.Action == "" || strings.EqualFold(t.Action, "Fill")
+}
+
+// RecurringOrders returns a page of the recurring orders of a wallet.
+func (c *JupagImpl) RecurringOrders(ctx context.Context, params RecurringOrdersParams) (RecurringOrdersPage, error) {
+	if _, err := ParsePublicKey(params.User); err != nil {
+		return RecurringOrdersPage{}, fmt.Errorf("%w: invalid user: %w", ErrInvalidParams, err)
+	}
+	if params.RecurringType == "" {
+		params.RecurringType = "time"
+	}
+	page, err := GetJSON[RecurringOrdersPage](ctx, c, c.endpoint(EndpointRecurringOrders), params)
+	if err != nil {
+		return RecurringOrdersPage{}, fmt.Errorf("failed to get recurring orders: %w", err)
+	}
+	return page, nil
+}
+
+// openRecurringOrders returns the open recurring orders of a wallet, of all the pages.
+func (c *JupagImpl) openRecurringOrders(ctx context.Context, wallet string) ([]RecurringOrder, error) {
+	var orders []RecurringOrder
+	for page := 1; ; page++ {
+		p, err := c.RecurringOrders(ctx, RecurringOrdersParams{User: wallet, OrderStatus: OrdersActive, Page: page})
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, p.Orders...)
+		if page >= p.TotalPages || len(p.Orders) == 0 {
+			return orders, nil
+		}
+	}
+}
+
+// RecurringEventType is the type of a recurring order event.
+type RecurringEventType string
+
+const (
+	RecurringEventCycle   RecurringEventType = "cycle"   // a cycle was executed
+	RecurringEventSkipped RecurringEventType = "skipped" // cycles weren't executed when due
+	RecurringEventClosed  RecurringEventType = "closed"  // the position was closed by the user or completed
+)
+
+// RecurringEvent is an event of WatchRecurringOrders. Either Err is set, or Type and Order.
+type RecurringEvent struct {
+	Time  time.Time
+	Type  RecurringEventType
+	Order RecurringOrder
+	Trade *RecurringTrade // executed cycle, for the cycle events
+
+	// Price is the realized price of the cycle, in output tokens per input token, 0 when the decimals of
+	// the tokens are unknown.
+	Price   float64
+	Skipped int // cycles due but not executed, for the skipped events
+	Err     error
+}
+
+// WatchRecurringOrders polls the recurring orders of a wallet every interval and sends their executed cycles on
+// the returned channel, alerting when cycles are skipped or a position is closed. The cycles executed before the
+// first poll are the baseline and aren't reported. A cycle is skipped when not executed a tenth of the cycle
+// frequency, at least a minute, after it was due. The channel is closed when ctx is done. The interval defaults
+// to a minute when not positive.
+func (c *JupagImpl) WatchRecurringOrders(ctx context.Context, wallet string, interval time.Duration) <-chan RecurringEvent {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	events := make(chan RecurringEvent, 16)
+
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		w := recurringWatcher{c: c, wallet: wallet, positions: make(map[string]*recurringPosition)}
+		for {
+			changes, err := w.poll(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				changes = append(changes, RecurringEvent{Time: time.Now(), Err: err})
+			}
+			for _, event := range changes {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return events
+}
+
+type recurringWatcher struct {
+	c         *JupagImpl
+	wallet    string
+	positions map[string]*recurringPosition // open orders, by order key
+	seeded    bool
+}
+
+type recurringPosition struct {
+	order   RecurringOrder
+	trades  map[string]bool // reported trades, by transaction id
+	skipped int             // skipped cycles already reported
+}
+
+// poll reports the new cycles and the skipped cycles of the open orders, closed orders stay tracked until found
+// in the history.
+func (w *recurringWatcher) poll(ctx context.Context) ([]RecurringEvent, error) {
+	open, err := w.c.openRecurringOrders(ctx, w.wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var events []RecurringEvent
+	seen := make(map[string]bool, len(open))
+	for _, order := range open {
+		seen[order.OrderKey] = true
+		pos, ok := w.positions[order.OrderKey]
+		if !ok {
+			pos = &recurringPosition{trades: make(map[string]bool)}
+			w.positions[order.OrderKey] = pos
+			if !w.seeded {
+				for _, t := range order.Trades {
+					pos.trades[t.TxID] = true
+				}
+			}
+		}
+		events = append(events, w.cycles(ctx, now, pos, order)...)
+
+		skipped := skippedCycles(order, now)
+		if skipped > pos.skipped {
+			events = append(events, RecurringEvent{Time: now, Type: RecurringEventSkipped, Order: order, Skipped: skipped})
+		}
+		pos.skipped = skipped
+		pos.order = order
+	}
+	w.seeded = true
+
+	var closed []*recurringPosition
+	for key, pos := range w.positions {
+		if !seen[key] {
+			closed = append(closed, pos)
+		}
+	}
+	if len(closed) == 0 {
+		return events, nil
+	}
+	sort.Slice(closed, func(i, j int) bool { return closed[i].order.OrderKey < closed[j].order.OrderKey })
+
+	history, err := w.c.RecurringOrders(ctx, RecurringOrdersParams{User: w.wallet, OrderStatus: OrdersHistory})
+	if err != nil {
+		return events, err
+	}
+	final := make(map[string]RecurringOrder, len(history.Orders))
+	for _, order := range history.Orders {
+		final[order.OrderKey] = order
+	}
+	for _, pos := range closed {
+		order, ok := final[pos.order.OrderKey]
+		if !ok {
+			continue // not in the history yet
+		}
+		events = append(events, w.cycles(ctx, now, pos, order)...)
+		events = append(events, RecurringEvent{Time: now, Type: RecurringEventClosed, Order: order})
+		delete(w.positions, order.OrderKey)
+	}
+	return events, nil
+}
+
+// cycles returns the events of the cycles of the order not reported yet, oldest first.
+func (w *recurringWatcher) cycles(ctx context.Context, now time.Time, pos *recurringPosition, order RecurringOrder) []RecurringEvent {
+	trades := make([]RecurringTrade, 0, len(order.Trades))
+	for _, t := range order.Trades {
+		if !pos.trades[t.TxID] && t.fill() {
+			trades = append(trades, t)
+		}
+		pos.trades[t.TxID] = true
+	}
+	sort.Slice(trades, func(i, j int) bool { return trades[i].ConfirmedAt.Before(trades[j].ConfirmedAt) })
+
+	events := make([]RecurringEvent, 0, len(trades))
+	for i := range trades {
+		t := trades[i]
+		events = append(events, RecurringEvent{
+			Time:  now,
+			Type:  RecurringEventCycle,
+			Order: order,
+			Trade: &t,
+			Price: w.price(ctx, order, t),
+		})
+	}
+	return events
+}
+
+// price returns the realized price of a trade in output tokens per input token, 0 when unknown.
+func (w *recurringWatcher) price(ctx context.Context, order RecurringOrder, t RecurringTrade) float64 {
+	if t.InputAmount.IsZero() {
+		return 0
+	}
+	inDecimals, err := w.c.Decimals(ctx, order.InputMint)
+	if err != nil {
+		return 0
+	}
+	outDecimals, err := w.c.Decimals(ctx, order.OutputMint)
+	if err != nil {
+		return 0
+	}
+	in := t.InputAmount.Float64() / math.Pow10(int(inDecimals))
+	out := t.OutputAmount.Float64() / math.Pow10(int(outDecimals))
+	return out / in
+}
+
+// skippedCycles returns the cycles of the order due but not executed since its last execution. Orders without
+// enough deposit left for a cycle have no cycle due.
+func skippedCycles(order RecurringOrder, now time.Time) int {
+	freq := time.Duration(order.CycleFrequency) * time.Second
+	if freq <= 0 || order.InAmountPerCycle.IsZero() {
+		return 0
+	}
+	used := order.InUsed.Add(order.InWithdrawn)
+	if used.Cmp(order.InDeposited) >= 0 || order.InDeposited.Sub(used).Cmp(order.InAmountPerCycle) < 0 {
+		return 0
+	}
+
+	last := order.CreatedAt
+	for _, t := range order.Trades {
+		if t.fill() && t.ConfirmedAt.After(last) {
+			last = t.ConfirmedAt
+		}
+	}
+	late := now.Sub(last) - max(freq/10, time.Minute)
+	if late < freq {
+		return 0
+	}
+	return int(late / freq)
+}
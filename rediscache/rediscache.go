@@ -0,0 +1,270 @@
+// Package rediscache implements jupag.Cache on a Redis server so several instances share cached prices,
+// token lists and routes maps. It speaks the Redis protocol directly and has no dependency.
+//
+//	cache := rediscache.New("localhost:6379", rediscache.Options{})
+//	defer cache.Close()
+//	client := jupag.NewJupag(jupag.WithCache(cache), jupag.WithPriceCache(10*time.Second))
+package rediscache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	jupag "github.com/ipanardian/go-jup-ag"
+)
+
+var _ jupag.Cache = (*Cache)(nil)
+
+// ErrClosed is returned by the commands of a closed Cache.
+var ErrClosed = errors.New("redis cache closed")
+
+// Options configures a Cache.
+type Options struct {
+	Username    string        // ACL user, requires Password
+	Password    string        // sent with AUTH when set
+	DB          int           // database selected with SELECT when not 0
+	Prefix      string        // prefix of all the keys, e.g. to share a database between applications
+	DialTimeout time.Duration // default: 5s
+	PoolSize    int           // maximum number of idle connections kept, default: 4
+}
+
+// Cache is a jupag.Cache backed by a Redis server. It is safe for concurrent use.
+type Cache struct {
+	addr string
+	opts Options
+
+	mu     sync.Mutex
+	idle   []*conn
+	closed bool
+}
+
+// New returns a cache on the Redis server at addr, connections are opened on demand.
+func New(addr string, opts Options) *Cache {
+	if opts.DialTimeout <= 0 {
+		opts.DialTimeout = 5 * time.Second
+	}
+	if opts.PoolSize <= 0 {
+		opts.PoolSize = 4
+	}
+	return &Cache{addr: addr, opts: opts}
+}
+
+// Get returns the value of the key, false when it is missing.
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	reply, err := c.do(ctx, "GET", c.opts.Prefix+key)
+	if err != nil {
+		return nil, false, err
+	}
+	if reply == nil {
+		return nil, false, nil
+	}
+	value, ok := reply.([]byte)
+	if !ok {
+		return nil, false, fmt.Errorf("unexpected redis GET reply %T", reply)
+	}
+	return value, true, nil
+}
+
+// Set stores the value of the key, expiring after ttl with millisecond precision. A ttl <= 0 never expires.
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	args := []any{"SET", c.opts.Prefix + key, value}
+	if ms := ttl.Milliseconds(); ms > 0 {
+		args = append(args, "PX", strconv.FormatInt(ms, 10))
+	}
+	_, err := c.do(ctx, args...)
+	return err
+}
+
+// Delete removes the key.
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	_, err := c.do(ctx, "DEL", c.opts.Prefix+key)
+	return err
+}
+
+// Close closes the idle connections, the commands in flight close theirs when done.
+func (c *Cache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	var err error
+	for _, cn := range c.idle {
+		err = errors.Join(err, cn.Close())
+	}
+	c.idle = nil
+	return err
+}
+
+// do sends a command on a pooled connection and returns its reply: nil, []byte, int64 or string.
+// A connection failing on a network error is discarded.
+func (c *Cache) do(ctx context.Context, args ...any) (any, error) {
+	cn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reply, err := cn.do(ctx, args...)
+	var redisErr Error
+	if err != nil && !errors.As(err, &redisErr) {
+		cn.Close()
+		return nil, err
+	}
+	c.put(cn)
+	return reply, err
+}
+
+func (c *Cache) get(ctx context.Context) (*conn, error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, ErrClosed
+	}
+	if n := len(c.idle); n > 0 {
+		cn := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.mu.Unlock()
+		return cn, nil
+	}
+	c.mu.Unlock()
+
+	return c.dial(ctx)
+}
+
+func (c *Cache) put(cn *conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed || len(c.idle) >= c.opts.PoolSize {
+		cn.Close()
+		return
+	}
+	c.idle = append(c.idle, cn)
+}
+
+func (c *Cache) dial(ctx context.Context) (*conn, error) {
+	d := net.Dialer{Timeout: c.opts.DialTimeout}
+	nc, err := d.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	cn := &conn{Conn: nc, r: bufio.NewReader(nc), w: bufio.NewWriter(nc)}
+
+	if c.opts.Password != "" {
+		args := []any{"AUTH", c.opts.Password}
+		if c.opts.Username != "" {
+			args = []any{"AUTH", c.opts.Username, c.opts.Password}
+		}
+		if _, err := cn.do(ctx, args...); err != nil {
+			cn.Close()
+			return nil, fmt.Errorf("failed to authenticate to redis: %w", err)
+		}
+	}
+	if c.opts.DB != 0 {
+		if _, err := cn.do(ctx, "SELECT", strconv.Itoa(c.opts.DB)); err != nil {
+			cn.Close()
+			return nil, fmt.Errorf("failed to select redis database: %w", err)
+		}
+	}
+	return cn, nil
+}
+
+// Error is an error reply of the Redis server.
+type Error string
+
+func (e Error) Error() string {
+	return "redis: " + string(e)
+}
+
+type conn struct {
+	net.Conn
+	r *bufio.Reader
+	w *bufio.Writer
+}
+
+// do writes a command as an array of bulk strings and reads its reply, within the deadline of ctx if any.
+// The connection is closed when ctx is done before the reply is read.
+func (cn *conn) do(ctx context.Context, args ...any) (any, error) {
+	deadline, _ := ctx.Deadline()
+	if err := cn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	stop := context.AfterFunc(ctx, func() { cn.Close() })
+	reply, err := cn.roundTrip(args)
+	if !stop() {
+		return nil, ctx.Err()
+	}
+	return reply, err
+}
+
+func (cn *conn) roundTrip(args []any) (any, error) {
+	fmt.Fprintf(cn.w, "*%d\r\n", len(args))
+	for _, arg := range args {
+		var b []byte
+		switch v := arg.(type) {
+		case string:
+			b = []byte(v)
+		case []byte:
+			b = v
+		default:
+			return nil, fmt.Errorf("unsupported redis argument %T", arg)
+		}
+		fmt.Fprintf(cn.w, "$%d\r\n", len(b))
+		cn.w.Write(b)
+		cn.w.WriteString("\r\n")
+	}
+	if err := cn.w.Flush(); err != nil {
+		return nil, err
+	}
+	return cn.readReply()
+}
+
+func (cn *conn) readReply() (any, error) {
+	line, err := cn.readLine()
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, errors.New("empty redis reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, Error(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis bulk length %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(cn.r, buf); err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	default:
+		return nil, fmt.Errorf("unsupported redis reply %q", line)
+	}
+}
+
+func (cn *conn) readLine() (string, error) {
+	line, err := cn.r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", fmt.Errorf("invalid redis reply line %q", line)
+	}
+	return line[:len(line)-2], nil
+}
@@ -0,0 +1,128 @@
+package rediscache
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadReply(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    any
+		wantErr string
+	}{
+		{in: "+OK\r\n", want: "OK"},
+		{in: "-ERR wrong type\r\n", wantErr: "redis: ERR wrong type"},
+		{in: ":42\r\n", want: int64(42)},
+		{in: ":-1\r\n", want: int64(-1)},
+		{in: "$3\r\nabc\r\n", want: []byte("abc")},
+		{in: "$4\r\na\r\nb\r\n", want: []byte("a\r\nb")},
+		{in: "$0\r\n\r\n", want: []byte{}},
+		{in: "$-1\r\n", want: nil},
+		{in: "", wantErr: "EOF"},
+		{in: "\r\n", wantErr: "empty redis reply"},
+		{in: "+OK\n", wantErr: "invalid redis reply line"},
+		{in: ":x\r\n", wantErr: "invalid syntax"},
+		{in: "$x\r\n", wantErr: "invalid redis bulk length"},
+		{in: "$5\r\nab", wantErr: "EOF"},
+		{in: "*1\r\n$1\r\na\r\n", wantErr: "unsupported redis reply"},
+	}
+	for _, tt := range tests {
+		cn := &conn{r: bufio.NewReader(strings.NewReader(tt.in))}
+		got, err := cn.readReply()
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("readReply(%q) = %v, %v, want error %q", tt.in, got, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("readReply(%q) = %#v, %v, want %#v", tt.in, got, err, tt.want)
+		}
+	}
+
+	var redisErr Error
+	cn := &conn{r: bufio.NewReader(strings.NewReader("-ERR x\r\n"))}
+	if _, err := cn.readReply(); !errors.As(err, &redisErr) {
+		t.Errorf("error reply = %v, want an Error", err)
+	}
+}
+
+// pipeConn returns a conn to a server answering each command with reply, after sending the command to commands.
+func pipeConn(t *testing.T, reply string, commands chan<- []byte) *conn {
+	client, server := net.Pipe()
+	t.Cleanup(func() { client.Close(); server.Close() })
+	go func() {
+		r := bufio.NewReader(server)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			// a command is an array of bulk strings, each a length line and a data line
+			cmd := []byte(line)
+			for n := int(line[1] - '0'); n > 0; n-- {
+				for i := 0; i < 2; i++ {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					cmd = append(cmd, line...)
+				}
+			}
+			commands <- cmd
+			if reply != "" {
+				io.WriteString(server, reply)
+			}
+		}
+	}()
+	return &conn{Conn: client, r: bufio.NewReader(client), w: bufio.NewWriter(client)}
+}
+
+func TestConnDo(t *testing.T) {
+	commands := make(chan []byte, 1)
+	cn := pipeConn(t, "$5\r\nvalue\r\n", commands)
+
+	reply, err := cn.do(context.Background(), "SET", "k", []byte("v"), "PX", "1000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "*5\r\n$3\r\nSET\r\n$1\r\nk\r\n$1\r\nv\r\n$2\r\nPX\r\n$4\r\n1000\r\n"; string(<-commands) != want {
+		t.Errorf("command not encoded as %q", want)
+	}
+	if !bytes.Equal(reply.([]byte), []byte("value")) {
+		t.Errorf("reply = %q, want value", reply)
+	}
+}
+
+func TestConnDoCanceled(t *testing.T) {
+	commands := make(chan []byte, 1)
+	cn := pipeConn(t, "", commands)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-commands
+		cancel()
+	}()
+	done := make(chan error, 1)
+	go func() {
+		_, err := cn.do(ctx, "GET", "k")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("error = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("command not interrupted by the cancellation")
+	}
+}
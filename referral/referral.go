@@ -0,0 +1,152 @@
+// Package referral derives and creates the token accounts of the Jupiter referral program, which collect
+// the platform fees of swaps, and resolves them for jupag.WithPlatformFee.
+package referral
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+
+	jupag "github.com/ipanardian/go-jup-ag"
+)
+
+// ErrTokenAccountNotFound is returned when the referral token account of a mint doesn't exist yet.
+var ErrTokenAccountNotFound = errors.New("referral token account not found, create it with CreateTokenAccountTransaction")
+
+var (
+	ProgramID      = jupag.MustPublicKey("REFER4ZgmyYx9c6He5XfaTMiGfdLwRnkV4RPp9t9iF3")  // Jupiter referral program
+	JupiterProject = jupag.MustPublicKey("45ruCyfdRkWpRNGEqWzjCiXRHkZs8WXCLQ67Pnpye7Hp") // project of the Jupiter swap referrals
+)
+
+// initializeTokenAccount is the anchor discriminator of the initialize_referral_token_account instruction.
+var initializeTokenAccount = func() []byte {
+	sum := sha256.Sum256([]byte("global:initialize_referral_token_account"))
+	return sum[:8]
+}()
+
+// TokenAccount returns the referral token account of a referral account for a mint.
+func TokenAccount(referralAccount, mint jupag.PublicKey) (jupag.PublicKey, error) {
+	account, _, err := jupag.FindProgramAddress([][]byte{[]byte("referral_ata"), referralAccount[:], mint[:]}, ProgramID)
+	if err != nil {
+		return jupag.PublicKey{}, fmt.Errorf("failed to derive referral token account: %w", err)
+	}
+	return account, nil
+}
+
+// InitializeTokenAccountInstruction returns the instruction creating the referral token account of a mint,
+// payer pays the rent. tokenProgram is the program of the mint, Token or Token-2022.
+func InitializeTokenAccountInstruction(payer, referralAccount, mint, tokenProgram jupag.PublicKey) (jupag.Instruction, error) {
+	account, err := TokenAccount(referralAccount, mint)
+	if err != nil {
+		return jupag.Instruction{}, err
+	}
+	return jupag.Instruction{
+		ProgramID: ProgramID,
+		Accounts: []jupag.AccountMeta{
+			{PublicKey: payer, IsSigner: true, IsWritable: true},
+			{PublicKey: JupiterProject},
+			{PublicKey: referralAccount},
+			{PublicKey: account, IsWritable: true},
+			{PublicKey: mint},
+			{PublicKey: jupag.SystemProgramID},
+			{PublicKey: tokenProgram},
+		},
+		Data: initializeTokenAccount,
+	}, nil
+}
+
+// Resolver resolves the referral token accounts of a referral account, it implements jupag.FeeAccountResolver.
+// Existing accounts are cached.
+type Resolver struct {
+	rpc     jupag.RPCClient
+	account jupag.PublicKey
+
+	mu       sync.RWMutex
+	existing map[jupag.PublicKey]bool
+}
+
+// NewResolver returns a resolver of the token accounts of referralAccount, checking that they exist over rpc.
+func NewResolver(rpc jupag.RPCClient, referralAccount jupag.PublicKey) *Resolver {
+	return &Resolver{rpc: rpc, account: referralAccount, existing: make(map[jupag.PublicKey]bool)}
+}
+
+// FeeAccount implements jupag.FeeAccountResolver, it returns ErrTokenAccountNotFound when the account doesn't exist.
+func (r *Resolver) FeeAccount(ctx context.Context, mint string) (string, error) {
+	mintKey, err := jupag.ParsePublicKey(mint)
+	if err != nil {
+		return "", err
+	}
+	account, err := TokenAccount(r.account, mintKey)
+	if err != nil {
+		return "", err
+	}
+
+	r.mu.RLock()
+	ok := r.existing[account]
+	r.mu.RUnlock()
+	if ok {
+		return account.String(), nil
+	}
+
+	_, exists, err := r.owner(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return "", fmt.Errorf("%w: %s for mint %s", ErrTokenAccountNotFound, account, mint)
+	}
+	r.mu.Lock()
+	r.existing[account] = true
+	r.mu.Unlock()
+
+	return account.String(), nil
+}
+
+// CreateTokenAccountTransaction returns the unsigned transaction creating the referral token account of a mint,
+// paid and to be signed by payer. It returns nil when the account already exists.
+func (r *Resolver) CreateTokenAccountTransaction(ctx context.Context, payer, mint jupag.PublicKey) (*jupag.Transaction, error) {
+	account, err := TokenAccount(r.account, mint)
+	if err != nil {
+		return nil, err
+	}
+	if _, exists, err := r.owner(ctx, account); err != nil || exists {
+		return nil, err
+	}
+
+	tokenProgram, exists, err := r.owner(ctx, mint)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("mint account %s not found", mint)
+	}
+	ix, err := InitializeTokenAccountInstruction(payer, r.account, mint, tokenProgram)
+	if err != nil {
+		return nil, err
+	}
+
+	blockhash, _, err := jupag.LatestBlockhash(ctx, r.rpc, jupag.CommitmentConfirmed)
+	if err != nil {
+		return nil, err
+	}
+	return jupag.NewTransaction(payer, blockhash, ix)
+}
+
+// owner returns the program owning an account, reporting false when it doesn't exist.
+func (r *Resolver) owner(ctx context.Context, account jupag.PublicKey) (jupag.PublicKey, bool, error) {
+	var info struct {
+		Value *struct {
+			Owner jupag.PublicKey `json:"owner"`
+		} `json:"value"`
+	}
+	params := []any{account.String(), map[string]any{"encoding": "base64", "dataSlice": map[string]int{"offset": 0, "length": 0}}}
+	if err := r.rpc.Call(ctx, "getAccountInfo", params, &info); err != nil {
+		return jupag.PublicKey{}, false, fmt.Errorf("failed to get account %s: %w", account, err)
+	}
+	if info.Value == nil {
+		return jupag.PublicKey{}, false, nil
+	}
+	return info.Value.Owner, true, nil
+}
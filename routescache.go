@@ -0,0 +1,90 @@
+package jupag
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type routesMapEntry struct {
+	routesMap IndexedRoutesMap
+	etag      string
+	fetchedAt time.Time
+}
+
+type routesMapCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[bool]*routesMapEntry // keyed by onlyDirectRoutes
+}
+
+func newRoutesMapCache(ttl time.Duration) *routesMapCache {
+	return &routesMapCache{ttl: ttl, entries: make(map[bool]*routesMapEntry)}
+}
+
+// get returns the cached routes map, revalidating it when expired or when force is set.
+func (rc *routesMapCache) get(ctx context.Context, c *JupagImpl, onlyDirectRoutes, force bool) (IndexedRoutesMap, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	entry := rc.entries[onlyDirectRoutes]
+	if entry != nil && !force && time.Since(entry.fetchedAt) < rc.ttl {
+		return entry.routesMap, nil
+	}
+	key := fmt.Sprintf("routesmap:%t", onlyDirectRoutes)
+	if c.payloadCache != nil && (entry == nil || !force) {
+		// an expired shared routes map is still worth its ETag to revalidate it
+		if shared, ok := cacheGet[sharedRoutesMap](ctx, c.payloadCache, key); ok && (entry == nil || shared.FetchedAt.After(entry.fetchedAt)) {
+			shared.RoutesMap.BuildIndex()
+			entry = &routesMapEntry{routesMap: shared.RoutesMap, etag: shared.ETag, fetchedAt: shared.FetchedAt}
+			rc.entries[onlyDirectRoutes] = entry
+			if !force && time.Since(entry.fetchedAt) < rc.ttl {
+				return entry.routesMap, nil
+			}
+		}
+	}
+
+	etag := ""
+	if entry != nil {
+		etag = entry.etag
+	}
+	routesMap, etag, err := c.fetchRoutesMap(ctx, onlyDirectRoutes, etag)
+	if err != nil {
+		return IndexedRoutesMap{}, err
+	}
+
+	if routesMap == nil {
+		// Not modified.
+		entry.fetchedAt = time.Now()
+	} else {
+		entry = &routesMapEntry{
+			routesMap: *routesMap,
+			etag:      etag,
+			fetchedAt: time.Now(),
+		}
+		rc.entries[onlyDirectRoutes] = entry
+	}
+	if c.payloadCache != nil {
+		// kept past the TTL for its ETag
+		cacheSet(ctx, c.payloadCache, key, sharedRoutesMap{RoutesMap: entry.routesMap, ETag: entry.etag, FetchedAt: entry.fetchedAt}, 0)
+	}
+	return entry.routesMap, nil
+}
+
+// sharedRoutesMap is a routes map stored in the shared cache, with its ETag to revalidate it.
+type sharedRoutesMap struct {
+	RoutesMap IndexedRoutesMap `json:"routesMap"`
+	ETag      string           `json:"etag"`
+	FetchedAt time.Time        `json:"fetchedAt"`
+}
+
+// RefreshRoutesMap revalidates the cached routes map regardless of its age.
+// Without WithRoutesMapCache it is equivalent to RoutesMap.
+func (c *JupagImpl) RefreshRoutesMap(ctx context.Context, onlyDirectRoutes bool) (IndexedRoutesMap, error) {
+	if c.routesCache == nil {
+		return c.routesMap(ctx, onlyDirectRoutes)
+	}
+	return c.routesCache.get(ctx, c, onlyDirectRoutes, true)
+}
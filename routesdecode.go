@@ -0,0 +1,427 @@
+package jupag
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// routesDecoderPool reuses the read buffers of the routes map decoders between downloads.
+var routesDecoderPool = sync.Pool{
+	New: func() any {
+		return &routesDecoder{r: bufio.NewReaderSize(nil, 64<<10)}
+	},
+}
+
+// errRoutesSyntax is wrapped by the syntax errors of DecodeRoutesMap.
+var errRoutesSyntax = errors.New("invalid routes map JSON")
+
+// DecodeRoutesMap decodes a routes map JSON from r as it is read, building its index on the fly. Unlike decoding
+// it with encoding/json, the payload is never held in memory at once and the scratch buffers are reused between
+// calls, which lowers the peak memory and GC pressure of the multi-MB routes map. The map is returned indexed.
+// The result matches encoding/json, enveloped or not, except that the field names are case-sensitive.
+func DecodeRoutesMap(r io.Reader) (IndexedRoutesMap, error) {
+	d := routesDecoderPool.Get().(*routesDecoder)
+	d.r.Reset(r)
+	defer func() {
+		d.r.Reset(nil)
+		routesDecoderPool.Put(d)
+	}()
+
+	routesMap, err := d.decode()
+	if err != nil {
+		return IndexedRoutesMap{}, fmt.Errorf("failed to decode routes map: %w", err)
+	}
+	return routesMap, nil
+}
+
+type routesDecoder struct {
+	r    *bufio.Reader
+	str  []byte // scratch buffer of the string being read
+	ints []int  // scratch buffer of the output array being read
+}
+
+func (d *routesDecoder) decode() (IndexedRoutesMap, error) {
+	routesMap := IndexedRoutesMap{IndexedRouteMap: make(map[string][]int)}
+	idx := &routesIndex{mints: make(map[string]int), outputs: make(map[int]map[int]struct{})}
+
+	var field func(key string) error
+	field = func(key string) error {
+		switch key {
+		case "data":
+			// enveloped routes map
+			if null, err := d.null(); null || err != nil {
+				return err
+			}
+			return d.object(field)
+		case "mintKeys":
+			routesMap.MintKeys = make([]string, 0)
+			idx.mints = make(map[string]int)
+			if null, err := d.null(); null || err != nil {
+				return err
+			}
+			return d.array(func() error {
+				mint, err := d.string()
+				if err != nil {
+					return err
+				}
+				s := string(mint)
+				idx.mints[s] = len(routesMap.MintKeys)
+				routesMap.MintKeys = append(routesMap.MintKeys, s)
+				return nil
+			})
+		case "indexedRouteMap":
+			if null, err := d.null(); null || err != nil {
+				clear(routesMap.IndexedRouteMap)
+				return err
+			}
+			return d.object(func(key string) error {
+				d.ints = d.ints[:0]
+				if null, err := d.null(); null || err != nil {
+					routesMap.IndexedRouteMap[key] = nil
+					return err
+				}
+				err := d.array(func() error {
+					n, err := d.int()
+					if err != nil {
+						return err
+					}
+					d.ints = append(d.ints, n)
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+				outputs := make([]int, len(d.ints))
+				copy(outputs, d.ints)
+				routesMap.IndexedRouteMap[key] = outputs
+				return nil
+			})
+		default:
+			return d.skip()
+		}
+	}
+	null, err := d.null()
+	if err != nil {
+		return IndexedRoutesMap{}, err
+	}
+	if !null {
+		if err := d.object(field); err != nil {
+			return IndexedRoutesMap{}, err
+		}
+	}
+	if b, err := d.next(); err == nil {
+		return IndexedRoutesMap{}, fmt.Errorf("%w: unexpected %q after the routes map", errRoutesSyntax, b)
+	} else if err != io.ErrUnexpectedEOF {
+		return IndexedRoutesMap{}, err
+	}
+
+	// validated once all the mints are known, as the keys may come in any order
+	for key, outputs := range routesMap.IndexedRouteMap {
+		in, err := strconv.Atoi(key)
+		if err != nil || in < 0 || in >= len(routesMap.MintKeys) {
+			continue
+		}
+		set := make(map[int]struct{}, len(outputs))
+		for _, out := range outputs {
+			if out >= 0 && out < len(routesMap.MintKeys) {
+				set[out] = struct{}{}
+			}
+		}
+		idx.outputs[in] = set
+	}
+	routesMap.index = idx
+	return routesMap, nil
+}
+
+// next returns the next non-space byte.
+func (d *routesDecoder) next() (byte, error) {
+	for {
+		b, err := d.r.ReadByte()
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return 0, err
+		}
+		switch b {
+		case ' ', '\t', '\n', '\r':
+			continue
+		}
+		return b, nil
+	}
+}
+
+// null reads a null literal if it is the next value, as encoding/json does for the slices and maps.
+func (d *routesDecoder) null() (bool, error) {
+	b, err := d.next()
+	if err != nil {
+		return false, err
+	}
+	d.r.UnreadByte()
+	if b != 'n' {
+		return false, nil
+	}
+	return true, d.skip()
+}
+
+func (d *routesDecoder) expect(want byte) error {
+	b, err := d.next()
+	if err != nil {
+		return err
+	}
+	if b != want {
+		return fmt.Errorf("%w: expected %q, got %q", errRoutesSyntax, want, b)
+	}
+	return nil
+}
+
+// object reads an object, calling fn to read the value of each key.
+func (d *routesDecoder) object(fn func(key string) error) error {
+	if err := d.expect('{'); err != nil {
+		return err
+	}
+	b, err := d.next()
+	if err != nil || b == '}' {
+		return err
+	}
+	d.r.UnreadByte()
+	for {
+		key, err := d.string()
+		if err != nil {
+			return err
+		}
+		if err := d.expect(':'); err != nil {
+			return err
+		}
+		if err := fn(string(key)); err != nil {
+			return err
+		}
+		b, err := d.next()
+		if err != nil {
+			return err
+		}
+		switch b {
+		case ',':
+		case '}':
+			return nil
+		default:
+			return fmt.Errorf("%w: expected ',' or '}', got %q", errRoutesSyntax, b)
+		}
+	}
+}
+
+// array reads an array, calling fn to read each element.
+func (d *routesDecoder) array(fn func() error) error {
+	if err := d.expect('['); err != nil {
+		return err
+	}
+	b, err := d.next()
+	if err != nil || b == ']' {
+		return err
+	}
+	d.r.UnreadByte()
+	for {
+		if err := fn(); err != nil {
+			return err
+		}
+		b, err := d.next()
+		if err != nil {
+			return err
+		}
+		switch b {
+		case ',':
+		case ']':
+			return nil
+		default:
+			return fmt.Errorf("%w: expected ',' or ']', got %q", errRoutesSyntax, b)
+		}
+	}
+}
+
+// string reads a string into the scratch buffer, valid until the next call.
+func (d *routesDecoder) string() ([]byte, error) {
+	if err := d.expect('"'); err != nil {
+		return nil, err
+	}
+	d.str = d.str[:0]
+	for {
+		b, err := d.r.ReadByte()
+		if err != nil {
+			return nil, io.ErrUnexpectedEOF
+		}
+		switch {
+		case b == '"':
+			if !utf8.Valid(d.str) {
+				d.str = replaceInvalidUTF8(d.str)
+			}
+			return d.str, nil
+		case b == '\\':
+			if err := d.escape(); err != nil {
+				return nil, err
+			}
+		case b < 0x20:
+			return nil, fmt.Errorf("%w: control character %q in string", errRoutesSyntax, b)
+		default:
+			d.str = append(d.str, b)
+		}
+	}
+}
+
+// replaceInvalidUTF8 replaces each invalid byte with utf8.RuneError, as encoding/json does.
+func replaceInvalidUTF8(s []byte) []byte {
+	valid := make([]byte, 0, len(s)+8)
+	for len(s) > 0 {
+		r, size := utf8.DecodeRune(s)
+		if r == utf8.RuneError && size == 1 {
+			valid = utf8.AppendRune(valid, utf8.RuneError)
+		} else {
+			valid = append(valid, s[:size]...)
+		}
+		s = s[size:]
+	}
+	return valid
+}
+
+func (d *routesDecoder) escape() error {
+	b, err := d.r.ReadByte()
+	if err != nil {
+		return io.ErrUnexpectedEOF
+	}
+	switch b {
+	case '"', '\\', '/':
+		d.str = append(d.str, b)
+	case 'b':
+		d.str = append(d.str, '\b')
+	case 'f':
+		d.str = append(d.str, '\f')
+	case 'n':
+		d.str = append(d.str, '\n')
+	case 'r':
+		d.str = append(d.str, '\r')
+	case 't':
+		d.str = append(d.str, '\t')
+	case 'u':
+		r, err := d.hex4()
+		if err != nil {
+			return err
+		}
+		// a surrogate pair is two escapes, a lone surrogate is utf8.RuneError
+		if utf16.IsSurrogate(r) {
+			pair := utf8.RuneError
+			if next, err := d.r.Peek(6); err == nil && next[0] == '\\' && next[1] == 'u' {
+				if r2, err := strconv.ParseUint(string(next[2:]), 16, 16); err == nil {
+					pair = utf16.DecodeRune(r, rune(r2))
+				}
+			}
+			if r = pair; r != utf8.RuneError {
+				d.r.Discard(6)
+			}
+		}
+		d.str = utf8.AppendRune(d.str, r)
+	default:
+		return fmt.Errorf("%w: invalid escape \\%c", errRoutesSyntax, b)
+	}
+	return nil
+}
+
+// hex4 reads the four hex digits of a \\u escape.
+func (d *routesDecoder) hex4() (rune, error) {
+	var hex [4]byte
+	if _, err := io.ReadFull(d.r, hex[:]); err != nil {
+		return 0, io.ErrUnexpectedEOF
+	}
+	r, err := strconv.ParseUint(string(hex[:]), 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid escape \\u%s", errRoutesSyntax, hex[:])
+	}
+	return rune(r), nil
+}
+
+// int reads an integer, as used for the mint positions. A null is 0, as with encoding/json.
+func (d *routesDecoder) int() (int, error) {
+	if null, err := d.null(); null || err != nil {
+		return 0, err
+	}
+	b, err := d.next()
+	if err != nil {
+		return 0, err
+	}
+	sign := 1
+	if b == '-' {
+		sign = -1
+		if b, err = d.r.ReadByte(); err != nil {
+			return 0, io.ErrUnexpectedEOF
+		}
+	}
+	if b < '0' || b > '9' {
+		return 0, fmt.Errorf("%w: expected a mint position, got %q", errRoutesSyntax, b)
+	}
+	n := int(b - '0')
+	for {
+		b, err := d.r.ReadByte()
+		if err != nil {
+			return 0, io.ErrUnexpectedEOF
+		}
+		switch {
+		case b >= '0' && b <= '9' && n == 0:
+			return 0, fmt.Errorf("%w: mint position with a leading zero", errRoutesSyntax)
+		case b >= '0' && b <= '9':
+			if n > (1<<31)/10 {
+				return 0, fmt.Errorf("%w: mint position overflow", errRoutesSyntax)
+			}
+			n = n*10 + int(b-'0')
+		case b == '.' || b == 'e' || b == 'E':
+			return 0, fmt.Errorf("%w: mint position %d%c is not an integer", errRoutesSyntax, n, b)
+		default:
+			d.r.UnreadByte()
+			return sign * n, nil
+		}
+	}
+}
+
+// skip reads and discards a value of any type.
+func (d *routesDecoder) skip() error {
+	b, err := d.next()
+	if err != nil {
+		return err
+	}
+	switch b {
+	case '"':
+		d.r.UnreadByte()
+		_, err := d.string()
+		return err
+	case '{':
+		d.r.UnreadByte()
+		return d.object(func(string) error { return d.skip() })
+	case '[':
+		d.r.UnreadByte()
+		return d.array(d.skip)
+	}
+	// number or literal, up to the next delimiter
+	d.str = append(d.str[:0], b)
+	for {
+		b, err := d.r.ReadByte()
+		if err != nil && err != io.EOF {
+			return err
+		}
+		switch {
+		case err == nil && strings.IndexByte(",}] \t\n\r", b) < 0:
+			d.str = append(d.str, b)
+			continue
+		case err == nil:
+			d.r.UnreadByte()
+		}
+		if !json.Valid(d.str) {
+			return fmt.Errorf("%w: invalid value %q", errRoutesSyntax, d.str)
+		}
+		return nil
+	}
+}
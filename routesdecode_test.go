@@ -0,0 +1,108 @@
+package jupag
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// decodeRoutesMapJSON decodes a routes map as the client does with strict decoding or drift reports, with
+// encoding/json after unwrapping the envelope.
+func decodeRoutesMapJSON(body string) (IndexedRoutesMap, error) {
+	var routesMap IndexedRoutesMap
+	err := (&JupagImpl{}).decodeData(strings.NewReader(body), &routesMap)
+	routesMap.BuildIndex()
+	return routesMap, err
+}
+
+func equalRoutesMaps(a, b IndexedRoutesMap) bool {
+	return slices.Equal(a.MintKeys, b.MintKeys) &&
+		maps.EqualFunc(a.IndexedRouteMap, b.IndexedRouteMap, slices.Equal[[]int]) &&
+		maps.Equal(a.index.mints, b.index.mints) &&
+		maps.EqualFunc(a.index.outputs, b.index.outputs, maps.Equal[map[int]struct{}])
+}
+
+func TestDecodeRoutesMapEquivalence(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"bare", `{"mintKeys":["a","b","c"],"indexedRouteMap":{"0":[1,2],"1":[0],"2":[]}}`},
+		{"enveloped", `{"data":{"mintKeys":["a","b"],"indexedRouteMap":{"0":[1]}},"contextSlot":5,"timeTaken":0.1}`},
+		{"empty", `{}`},
+		{"null", ` null `},
+		{"null data", `{"data":null}`},
+		{"index before the mints", `{"indexedRouteMap":{"1":[0]},"mintKeys":["a","b"]}`},
+		{"whitespace", " \n{ \"mintKeys\" :\t[ \"a\" , \"b\" ] ,\r\n \"indexedRouteMap\" : { \"0\" : [ 1 ] } } \n"},
+		{"unknown fields", `{"x":{"y":[1,"z",true,null,-1.5e3,{}]},"mintKeys":["a"],"n":null,"f":false,"s":"}]"}`},
+		{"escapes", `{"mintKeys":["a\"b","\\","\/","\b\f\n\r\t","\u00e9","\u00E9x","\ud83d\ude00","é","日本"],` +
+			`"indexedRouteMap":{"\u0030":[1]}}`},
+		{"lone surrogates", `{"mintKeys":["\ud83d","\ude00","\ud83dx","\ud83d\u0041","\ud83d\ud83d\ude00","\ude00\ud83d"]}`},
+		{"invalid utf-8", "{\"mintKeys\":[\"a\xffb\",\"\xe6\x97\"]}"},
+		{"nulls", `{"mintKeys":null,"indexedRouteMap":{"0":null}}`},
+		{"null index", `{"mintKeys":["a"],"indexedRouteMap":null}`},
+		{"out of range positions", `{"mintKeys":["a","b"],"indexedRouteMap":{"0":[-1,1,5],"7":[0],"x":[1],"-1":[0]}}`},
+		{"duplicate keys", `{"mintKeys":["a","b"],"indexedRouteMap":{"0":[1]},"mintKeys":["b","a"],"indexedRouteMap":{"1":[0]}}`},
+		{"duplicate mints", `{"mintKeys":["a","b","a"],"indexedRouteMap":{"0":[1],"2":[1]}}`},
+		{"zero", `{"mintKeys":["a"],"indexedRouteMap":{"0":[0]}}`},
+	}
+	for _, tt := range tests {
+		want, err := decodeRoutesMapJSON(tt.body)
+		if err != nil {
+			t.Errorf("%s: encoding/json: %v", tt.name, err)
+			continue
+		}
+		got, err := DecodeRoutesMap(strings.NewReader(tt.body))
+		if err != nil {
+			t.Errorf("%s: DecodeRoutesMap: %v", tt.name, err)
+			continue
+		}
+		if !equalRoutesMaps(got, want) {
+			t.Errorf("%s: DecodeRoutesMap = %q %v, encoding/json = %q %v", tt.name, got.MintKeys, got.IndexedRouteMap, want.MintKeys, want.IndexedRouteMap)
+		}
+	}
+}
+
+func TestDecodeRoutesMapMalformed(t *testing.T) {
+	bodies := []string{
+		``,
+		`{`,
+		`[]`,
+		`{"mintKeys":["a"]`,
+		`{"mintKeys":["a"]}}`,
+		`{"mintKeys":["a"]} x`,
+		`{"mintKeys":["a",]}`,
+		`{"mintKeys":["a"],}`,
+		`{"mintKeys" ["a"]}`,
+		`{mintKeys:["a"]}`,
+		`{"mintKeys":[1]}`,
+		`{"mintKeys":"a"}`,
+		`{"mintKeys":["a` + "\t" + `b"]}`,
+		`{"mintKeys":["a\qb"]}`,
+		`{"mintKeys":["\u12"]}`,
+		`{"mintKeys":["\u12g4"]}`,
+		`{"indexedRouteMap":{"0":["a"]}}`,
+		`{"indexedRouteMap":{"0":[1.5]}}`,
+		`{"indexedRouteMap":{"0":[1e2]}}`,
+		`{"indexedRouteMap":{"0":[01]}}`,
+		`{"indexedRouteMap":{"0":[-]}}`,
+		`{"indexedRouteMap":{"0":[1 2]}}`,
+		`{"indexedRouteMap":[]}`,
+		`{"x":tru}`,
+		`{"x":nul}`,
+		`{"x":1.2.3}`,
+		`{"x":+1}`,
+		`{"x":[1,]}`,
+		`{"x":{"a"}}`,
+		`{"data":{"mintKeys":["a"]`,
+	}
+	for _, body := range bodies {
+		if _, err := decodeRoutesMapJSON(body); err == nil {
+			t.Errorf("%q: no encoding/json error", body)
+		}
+		if _, err := DecodeRoutesMap(strings.NewReader(body)); err == nil {
+			t.Errorf("%q: no DecodeRoutesMap error", body)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package jupag
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// routesMapSubscriberBuffer is the number of changes buffered per subscriber before they are dropped.
+const routesMapSubscriberBuffer = 16
+
+// RoutesMapChange is a change of the direct routes map. Either Err is set, or the pairs that appeared or disappeared.
+type RoutesMapChange struct {
+	Time    time.Time
+	Added   []Pair
+	Removed []Pair
+	Err     error // refresh error, the previous routes map is kept
+}
+
+// RoutesMapSync keeps the direct routes map fresh in the background, see StartRoutesMapSync.
+type RoutesMapSync struct {
+	mu          sync.RWMutex
+	routesMap   IndexedRoutesMap
+	subscribers map[chan RoutesMapChange]struct{}
+	done        chan struct{}
+}
+
+// StartRoutesMapSync refreshes the direct routes map every interval until ctx is done, and notifies the subscribers
+// when pairs appear or disappear, e.g. to watch for new markets. With WithRoutesMapCache, the client cache is kept
+// fresh too. The first refresh is done before it returns. The interval defaults to 5 minutes when not positive.
+func (c *JupagImpl) StartRoutesMapSync(ctx context.Context, interval time.Duration) (*RoutesMapSync, error) {
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	routesMap, err := c.RefreshRoutesMap(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	s := &RoutesMapSync{
+		routesMap:   routesMap,
+		subscribers: make(map[chan RoutesMapChange]struct{}),
+		done:        make(chan struct{}),
+	}
+
+	go func() {
+		defer s.close()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			routesMap, err := c.RefreshRoutesMap(ctx, true)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				s.notify(RoutesMapChange{Time: time.Now(), Err: err})
+				continue
+			}
+
+			s.mu.Lock()
+			previous := s.routesMap
+			s.routesMap = routesMap
+			s.mu.Unlock()
+
+			change := RoutesMapChange{Time: time.Now()}
+			change.Added = missingPairs(&routesMap, &previous)
+			change.Removed = missingPairs(&previous, &routesMap)
+			if len(change.Added) > 0 || len(change.Removed) > 0 {
+				s.notify(change)
+			}
+		}
+	}()
+
+	return s, nil
+}
+
+// RoutesMap returns the latest routes map, its index is built so it can be shared between goroutines.
+func (s *RoutesMapSync) RoutesMap() IndexedRoutesMap {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.routesMap
+}
+
+// Subscribe returns a channel receiving the changes of the routes map and a function to unsubscribe.
+// Changes are dropped when the subscriber lags behind. The channel is closed on unsubscribe or when the sync stops.
+func (s *RoutesMapSync) Subscribe() (<-chan RoutesMapChange, func()) {
+	ch := make(chan RoutesMapChange, routesMapSubscriberBuffer)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.subscribers == nil {
+		close(ch)
+		return ch, func() {}
+	}
+	s.subscribers[ch] = struct{}{}
+
+	return ch, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := s.subscribers[ch]; ok {
+			delete(s.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Done returns a channel closed when the sync stops.
+func (s *RoutesMapSync) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *RoutesMapSync) notify(change RoutesMapChange) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for ch := range s.subscribers {
+		select {
+		case ch <- change:
+		default:
+		}
+	}
+}
+
+func (s *RoutesMapSync) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.subscribers {
+		close(ch)
+	}
+	s.subscribers = nil
+	close(s.done)
+}
+
+// missingPairs returns the pairs of a missing from b, sorted.
+func missingPairs(a, b *IndexedRoutesMap) []Pair {
+	var missing []Pair
+	a.ForEachPair(func(p Pair) bool {
+		if !b.HasRoute(p.InputMint, p.OutputMint) {
+			missing = append(missing, p)
+		}
+		return true
+	})
+	sort.Slice(missing, func(i, j int) bool {
+		if missing[i].InputMint != missing[j].InputMint {
+			return missing[i].InputMint < missing[j].InputMint
+		}
+		return missing[i].OutputMint < missing[j].OutputMint
+	})
+	return missing
+}
@@ -0,0 +1,107 @@
+package jupag
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gojek/heimdall/v7/httpclient"
+)
+
+// RPCClient is a minimal Solana JSON-RPC client.
+type RPCClient interface {
+	// Call invokes the given RPC method and decodes the result into result.
+	Call(ctx context.Context, method string, params []any, result any) error
+}
+
+type RPCClientImpl struct {
+	rpcImpl  *httpclient.Client
+	endpoint string
+	id       atomic.Uint64
+}
+
+// RPCError is an error returned by the RPC node.
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      uint64 `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error,omitempty"`
+}
+
+// NewRPCClient returns a RPC client for the given Solana RPC endpoint.
+func NewRPCClient(endpoint string) RPCClient {
+	timeout := 10000 * time.Millisecond
+	cl := httpclient.NewClient(
+		httpclient.WithHTTPTimeout(timeout),
+	)
+
+	return &RPCClientImpl{
+		rpcImpl:  cl,
+		endpoint: endpoint,
+	}
+}
+
+// Call invokes the given RPC method and decodes the result into result.
+func (c *RPCClientImpl) Call(ctx context.Context, method string, params []any, result any) error {
+	data, err := json.Marshal(rpcRequest{
+		JSONRPC: "2.0",
+		ID:      c.id.Add(1),
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal rpc request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.rpcImpl.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make %s request: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(resp)
+	}
+
+	var response rpcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", method, err)
+	}
+	if response.Error != nil {
+		return response.Error
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("failed to parse %s response: %w", method, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package jupag
+
+import (
+	"context"
+	"sync"
+)
+
+// Priority is the scheduling priority of an API request, see WithScheduler.
+type Priority int
+
+const (
+	PriorityLow    Priority = iota // background jobs, e.g. price polling; default of the price requests
+	PriorityNormal                 // default
+	PriorityHigh                   // execution critical; default of the swap builds
+)
+
+// defaultPriorities are the priorities of the endpoints other than PriorityNormal, when the context sets none.
+var defaultPriorities = map[Endpoint]Priority{
+	EndpointPrice:            PriorityLow,
+	EndpointSwap:             PriorityHigh,
+	EndpointSwapInstructions: PriorityHigh,
+}
+
+type priorityKey struct{}
+
+// ContextWithPriority returns a context scheduling the API requests made with it at the given priority,
+// e.g. PriorityHigh for the quotes of a swap being executed. It is only used with WithScheduler.
+func ContextWithPriority(ctx context.Context, priority Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, priority)
+}
+
+// priorityOf returns the priority of a request to the endpoint made with ctx.
+func priorityOf(ctx context.Context, e Endpoint) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return p
+	}
+	if p, ok := defaultPriorities[e]; ok {
+		return p
+	}
+	return PriorityNormal
+}
+
+// scheduler bounds the number of requests in flight, the queued requests are started by priority then in order.
+type scheduler struct {
+	maxInFlight int
+
+	mu       sync.Mutex
+	inFlight int
+	queues   [PriorityHigh + 1][]chan struct{}
+}
+
+func newScheduler(maxInFlight int) *scheduler {
+	if maxInFlight <= 0 {
+		maxInFlight = 1
+	}
+	return &scheduler{maxInFlight: maxInFlight}
+}
+
+// acquire waits for a slot, release must be called once the request is done.
+func (s *scheduler) acquire(ctx context.Context, priority Priority) error {
+	priority = min(max(priority, PriorityLow), PriorityHigh)
+
+	s.mu.Lock()
+	if s.inFlight < s.maxInFlight {
+		s.inFlight++
+		s.mu.Unlock()
+		return nil
+	}
+	ready := make(chan struct{})
+	s.queues[priority] = append(s.queues[priority], ready)
+	s.mu.Unlock()
+
+	select {
+	case <-ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	queue := s.queues[priority]
+	for i, ch := range queue {
+		if ch == ready {
+			s.queues[priority] = append(queue[:i], queue[i+1:]...)
+			return ctx.Err()
+		}
+	}
+	// the slot was handed over meanwhile, pass it on
+	s.releaseLocked()
+	return ctx.Err()
+}
+
+func (s *scheduler) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.releaseLocked()
+}
+
+// releaseLocked hands the slot over to the first request of the highest priority queue, or frees it.
+func (s *scheduler) releaseLocked() {
+	for p := PriorityHigh; p >= PriorityLow; p-- {
+		if queue := s.queues[p]; len(queue) > 0 {
+			close(queue[0])
+			s.queues[p] = queue[1:]
+			return
+		}
+	}
+	s.inFlight--
+}
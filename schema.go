@@ -0,0 +1,145 @@
+package jupag
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// SchemaDrift lists the fields of an API response that aren't mapped by the response type.
+type SchemaDrift struct {
+	Type   string   // response type, e.g. jupag.QuoteResponse
+	Fields []string // paths of the unknown fields, e.g. [].routePlan, data.*.newField
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// decodeJSON decodes data into v, reporting or rejecting the unknown fields and setting the decimal fields
+// when configured.
+func (c *JupagImpl) decodeJSON(data []byte, v any) error {
+	if c.driftReport != nil {
+		if fields := unknownFields(data, reflect.TypeOf(v)); len(fields) > 0 {
+			c.driftReport(SchemaDrift{Type: reflect.TypeOf(v).Elem().String(), Fields: fields})
+		}
+	}
+
+	var err error
+	if !c.strictDecode {
+		err = c.jsonCodec().Unmarshal(data, v)
+	} else {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(v)
+	}
+	if err == nil && c.decimalNumbers {
+		setDecimals(data, reflect.ValueOf(v))
+	}
+	return err
+}
+
+// jsonCodec returns the codec set with WithCodec, encoding/json otherwise.
+func (c *JupagImpl) jsonCodec() Codec {
+	if c.codec == nil {
+		return stdCodec{}
+	}
+	return c.codec
+}
+
+// unknownFields returns the sorted paths of the object keys in data that don't map to a field of t.
+// Types with their own JSON decoding are not inspected.
+func unknownFields(data []byte, t reflect.Type) []string {
+	seen := make(map[string]bool)
+	walkUnknownFields(data, t, "", seen)
+
+	fields := make([]string, 0, len(seen))
+	for f := range seen {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func walkUnknownFields(data []byte, t reflect.Type, path string, seen map[string]bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(unmarshalerType) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		var obj map[string]json.RawMessage
+		if json.Unmarshal(data, &obj) != nil {
+			return
+		}
+		fields := jsonFields(t)
+		for key, val := range obj {
+			field, ok := fields[strings.ToLower(key)]
+			if !ok {
+				seen[joinPath(path, key)] = true
+				continue
+			}
+			walkUnknownFields(val, field, joinPath(path, key), seen)
+		}
+	case reflect.Slice, reflect.Array:
+		var items []json.RawMessage
+		if json.Unmarshal(data, &items) != nil {
+			return
+		}
+		for _, item := range items {
+			walkUnknownFields(item, t.Elem(), path+"[]", seen)
+		}
+	case reflect.Map:
+		var obj map[string]json.RawMessage
+		if json.Unmarshal(data, &obj) != nil {
+			return
+		}
+		for _, val := range obj {
+			walkUnknownFields(val, t.Elem(), joinPath(path, "*"), seen)
+		}
+	}
+}
+
+// jsonFields returns the types of the JSON fields of a struct by lowercase name, as encoding/json matches them.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if tag == "-" {
+			continue
+		}
+		if f.Anonymous && tag == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				for name, typ := range jsonFields(ft) {
+					if _, ok := fields[name]; !ok {
+						fields[name] = typ
+					}
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if tag == "" {
+			tag = f.Name
+		}
+		fields[strings.ToLower(tag)] = f.Type
+	}
+	return fields
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
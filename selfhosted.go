@@ -0,0 +1,48 @@
+package jupag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var ErrNotSelfHosted = errors.New("only supported by a self-hosted swap api")
+
+// NewSelfHosted returns a client of a self-hosted jupiter-swap-api instance at baseURL.
+// Quotes and swaps use the instance and the APIVersionV6 shapes unless configured with WithAPIVersion,
+// the other API families keep using the hosted API unless configured with WithAPIBaseURL.
+func NewSelfHosted(baseURL string, opts ...Option) *JupagImpl {
+	profile := []Option{
+		WithAPIBaseURL(APISwap, baseURL),
+		WithEndpointPath(EndpointHealth, "/health"),
+		func(c *JupagImpl) { c.selfHosted = true },
+	}
+	return NewJupag(append(profile, opts...)...)
+}
+
+// MarketParams is a market injected into a self-hosted swap api started with --enable-add-market.
+type MarketParams struct {
+	ID     string         `json:"id"`               // market account address
+	Data   []string       `json:"data"`             // account data and its encoding, e.g. ["<base64>", "base64"]
+	Owner  string         `json:"owner"`            // program owning the market account
+	Params map[string]any `json:"params,omitempty"` // extra params of the AMM, e.g. the address lookup table
+}
+
+// MarketsAdd injects a new market into a self-hosted swap api so it is routed before being indexed.
+func (c *JupagImpl) MarketsAdd(ctx context.Context, market MarketParams) error {
+	if !c.selfHosted {
+		return ErrNotSelfHosted
+	}
+
+	resp, err := c.request(ctx, http.MethodPost, c.endpoint(EndpointMarkets), nil, market)
+	if err != nil {
+		return fmt.Errorf("failed to make markets request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(resp)
+	}
+	return nil
+}
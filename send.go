@@ -0,0 +1,242 @@
+package jupag
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+var (
+	ErrAttemptTimeout     = errors.New("send attempt timed out")
+	ErrTransactionPending = errors.New("transaction outcome unknown")
+)
+
+// PendingTransactionError is returned by SendWithPolicy when the submissions may still land, e.g. ctx was done
+// before their blockhash expired. It matches ErrTransactionPending and the cause. Check the status of the
+// signatures before submitting the transaction again.
+type PendingTransactionError struct {
+	Signatures []string // submissions that may still land, most recent first
+	Err        error
+}
+
+func (e *PendingTransactionError) Error() string {
+	return fmt.Sprintf("transaction outcome unknown, %d submissions pending: %v", len(e.Signatures), e.Err)
+}
+
+func (e *PendingTransactionError) Unwrap() []error {
+	return []error{ErrTransactionPending, e.Err}
+}
+
+const (
+	defaultSendAttempts       = 3
+	defaultSendAttemptTimeout = 15 * time.Second
+)
+
+var defaultFeeMultipliers = []float64{1, 1.5, 2}
+
+// SendPolicy controls the resubmission of a transaction by SendWithPolicy.
+type SendPolicy struct {
+	// Attempts is the maximum number of submissions. Default: 3.
+	Attempts int
+	// FeeMultipliers multiply the base compute unit price on each attempt, the last one is repeated.
+	// Default: 1, 1.5, 2.
+	FeeMultipliers []float64
+	// AttemptTimeouts are the time to wait for each attempt to land before resubmitting, the last one is repeated.
+	// Default: 15s.
+	AttemptTimeouts []time.Duration
+	// BaseComputeUnitPrice is the compute unit price, in micro lamports, the multipliers apply to.
+	// Default: the price set by the transaction, if any.
+	BaseComputeUnitPrice uint64
+	Commitment           Commitment // commitment to wait for, default: confirmed
+	SkipPreflight        bool       // disables the RPC node preflight simulation
+}
+
+func (p SendPolicy) attempts() int {
+	if p.Attempts <= 0 {
+		return defaultSendAttempts
+	}
+	return p.Attempts
+}
+
+func (p SendPolicy) multiplier(attempt int) float64 {
+	multipliers := p.FeeMultipliers
+	if len(multipliers) == 0 {
+		multipliers = defaultFeeMultipliers
+	}
+	return multipliers[min(attempt, len(multipliers)-1)]
+}
+
+func (p SendPolicy) timeout(attempt int) time.Duration {
+	if len(p.AttemptTimeouts) == 0 {
+		return defaultSendAttemptTimeout
+	}
+	return p.AttemptTimeouts[min(attempt, len(p.AttemptTimeouts)-1)]
+}
+
+// SendResult is the result of SendWithPolicy.
+type SendResult struct {
+	Signature    string // signature of the landed transaction
+	Confirmation ConfirmationResult
+	Attempts     []SendAttempt
+}
+
+// SendWithPolicy signs and submits a built transaction, then waits for it to land. When an attempt times out
+// or the blockhash expires, the compute unit price is bumped by the next multiplier of the policy and the
+// transaction is signed again and resubmitted, with a fresh blockhash once expired.
+// Earlier submissions are still watched until their blockhash expires and a final status check shows them absent,
+// so a timed out attempt may land instead of a later one: the landed signature is reported in the result.
+// When the last attempt times out, it waits until the last blockhash expires and checks all the submissions
+// again, returning a PendingTransactionError when their outcome is still unknown.
+func (c *JupagImpl) SendWithPolicy(ctx context.Context, tx *Transaction, lastValidBlockHeight uint64, signers []Signer, policy SendPolicy) (SendResult, error) {
+	if c.rpc == nil {
+		return SendResult{}, ErrNoRPC
+	}
+	if len(signers) == 0 {
+		return SendResult{}, ErrNoSigner
+	}
+	base := policy.BaseComputeUnitPrice
+	if base == 0 {
+		base, _ = tx.Message.computeUnitPrice()
+	}
+
+	var (
+		result      SendResult
+		pending     []string              // submissions that may still land, most recent first
+		submitSlots = map[string]uint64{} // slot of each submission, for the statistics
+	)
+	for i := 0; i < policy.attempts(); i++ {
+		price := uint64(float64(base) * policy.multiplier(i))
+		attempt := SendAttempt{ComputeUnitPriceMicroLamports: int64(price)}
+
+		slot := c.submitSlot(ctx)
+		attempt.Signature, attempt.Err = c.submitWithPrice(ctx, tx, signers, price, policy.SkipPreflight)
+		if attempt.Err != nil {
+			c.events.failed(ctx, StageSubmit, attempt.Err)
+			result.Attempts = append(result.Attempts, attempt)
+			return result, attempt.Err
+		}
+		c.stats.submitted()
+		c.events.submitted(ctx, attempt.Signature, Route{})
+		if !slices.Contains(pending, attempt.Signature) {
+			pending = append([]string{attempt.Signature}, pending...)
+			submitSlots[attempt.Signature] = slot
+		}
+
+		attemptCtx, cancel := context.WithTimeout(ctx, policy.timeout(i))
+		attempt.Confirmation, attempt.Err = waitForAnyConfirmation(attemptCtx, c.rpc, pending, policy.Commitment, lastValidBlockHeight)
+		cancel()
+		if attempt.Err == nil {
+			c.stats.confirmed(attempt.Confirmation, submitSlots[attempt.Confirmation.Signature])
+		}
+
+		switch {
+		case attempt.Err != nil && ctx.Err() == nil && errors.Is(attempt.Err, context.DeadlineExceeded):
+			attempt.Err = ErrAttemptTimeout
+		case attempt.Err != nil:
+			c.events.failed(ctx, StageConfirm, attempt.Err)
+			result.Attempts = append(result.Attempts, attempt)
+			return result, attempt.Err
+		case attempt.Confirmation.Status == ConfirmationConfirmed:
+			result.Attempts = append(result.Attempts, attempt)
+			result.Signature = attempt.Confirmation.Signature
+			result.Confirmation = attempt.Confirmation
+			c.events.confirmed(ctx, attempt.Confirmation)
+			return result, nil
+		case attempt.Confirmation.Status == ConfirmationFailed:
+			attempt.Err = fmt.Errorf("%w: %s", ErrTransactionFailed, attempt.Confirmation.Err)
+			if attempt.Confirmation.Reason != nil {
+				attempt.Err = fmt.Errorf("%w: %w", ErrTransactionFailed, attempt.Confirmation.Reason)
+			}
+			c.events.failed(ctx, StageConfirm, attempt.Err)
+			result.Attempts = append(result.Attempts, attempt)
+			return result, attempt.Err
+		case attempt.Confirmation.Status == ConfirmationExpired:
+			attempt.Err = ErrTransactionExpired
+		}
+		result.Attempts = append(result.Attempts, attempt)
+
+		if errors.Is(attempt.Err, ErrTransactionExpired) && i+1 < policy.attempts() {
+			blockhash, height, err := LatestBlockhash(ctx, c.rpc, CommitmentConfirmed)
+			if err != nil {
+				return result, err
+			}
+			tx.Message.RecentBlockhash = blockhash
+			lastValidBlockHeight = height
+
+			// the earlier submissions are dropped only once a final status check shows them absent
+			statuses, err := getSignatureStatuses(ctx, c.rpc, pending)
+			if err != nil {
+				return result, err
+			}
+			var known []string
+			for j, status := range statuses {
+				if status != nil && j < len(pending) {
+					known = append(known, pending[j])
+				}
+			}
+			pending = known
+		}
+	}
+
+	last := &result.Attempts[len(result.Attempts)-1]
+	if errors.Is(last.Err, ErrAttemptTimeout) {
+		// the submissions over the last blockhash may still land until it expires
+		confirmation, err := waitForAnyConfirmation(ctx, c.rpc, pending, policy.Commitment, lastValidBlockHeight)
+		if err != nil {
+			err = &PendingTransactionError{Signatures: pending, Err: err}
+			c.events.failed(ctx, StageConfirm, err)
+			return result, err
+		}
+		last.Confirmation = confirmation
+		switch confirmation.Status {
+		case ConfirmationConfirmed:
+			last.Err = nil
+			c.stats.confirmed(confirmation, submitSlots[confirmation.Signature])
+			result.Signature = confirmation.Signature
+			result.Confirmation = confirmation
+			c.events.confirmed(ctx, confirmation)
+			return result, nil
+		case ConfirmationFailed:
+			last.Err = confirmationError(confirmation)
+			c.events.failed(ctx, StageConfirm, last.Err)
+			return result, last.Err
+		}
+		last.Err = ErrTransactionExpired
+	}
+
+	err := fmt.Errorf("transaction not landed after %d attempts: %w", len(result.Attempts), last.Err)
+	c.events.failed(ctx, StageConfirm, err)
+	return result, err
+}
+
+// submitWithPrice sets the compute unit price of the transaction, signs and submits it.
+func (c *JupagImpl) submitWithPrice(ctx context.Context, tx *Transaction, signers []Signer, price uint64, skipPreflight bool) (string, error) {
+	if price > 0 {
+		if err := tx.SetComputeUnitPrice(price); err != nil {
+			return "", err
+		}
+	}
+	if err := tx.Sign(signers...); err != nil {
+		return "", err
+	}
+	if err := tx.CheckSize(); err != nil {
+		return "", err
+	}
+	return SendTransaction(ctx, c.rpc, tx.Base64(), skipPreflight)
+}
+
+// computeUnitPrice returns the compute unit price set by the message, if any.
+func (m *Message) computeUnitPrice() (uint64, bool) {
+	for _, ix := range m.Instructions {
+		if int(ix.ProgramIDIndex) >= len(m.AccountKeys) || m.AccountKeys[ix.ProgramIDIndex] != ComputeBudgetProgramID {
+			continue
+		}
+		if len(ix.Data) == 9 && ix.Data[0] == computeBudgetSetComputeUnitPrice {
+			return binary.LittleEndian.Uint64(ix.Data[1:]), true
+		}
+	}
+	return 0, false
+}
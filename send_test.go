@@ -0,0 +1,80 @@
+package jupag
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSendWithPolicyLastAttemptTimeout(t *testing.T) {
+	defer func(interval time.Duration) { confirmationPollInterval = interval }(confirmationPollInterval)
+	confirmationPollInterval = time.Millisecond
+
+	tests := []struct {
+		name     string
+		landed   bool // whether the submission lands after its attempt timed out
+		expired  bool // whether the block height passes the last valid one
+		wantErr  error
+		notWant  error
+		wantSigs int
+	}{
+		{name: "landed after the timeout", landed: true},
+		{name: "expired", expired: true, wantErr: ErrTransactionExpired, notWant: ErrTransactionPending},
+		{name: "outcome unknown", wantErr: ErrTransactionPending, wantSigs: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer := NewKeypairSigner(ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize)))
+			tx := &Transaction{
+				Signatures: [][]byte{make([]byte, 64)},
+				Message: Message{
+					Header:      MessageHeader{NumRequiredSignatures: 1},
+					AccountKeys: []PublicKey{signer.PublicKey()},
+				},
+			}
+
+			var timedOut atomic.Bool
+			rpc := fakeRPC(func(method string, _ []any) any {
+				switch method {
+				case "sendTransaction":
+					return "sig"
+				case "getBlockHeight":
+					if tt.expired && timedOut.Load() {
+						return 201
+					}
+					return 100
+				}
+				if tt.landed && timedOut.Load() {
+					return statusesValue(&signatureStatus{Slot: 7, ConfirmationStatus: "confirmed"})
+				}
+				return statusesValue(nil)
+			})
+			c := &JupagImpl{rpc: rpc}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			go func() {
+				time.Sleep(10 * time.Millisecond)
+				timedOut.Store(true)
+			}()
+			result, err := c.SendWithPolicy(ctx, tx, 200, []Signer{signer}, SendPolicy{Attempts: 1, AttemptTimeouts: []time.Duration{5 * time.Millisecond}})
+
+			if tt.wantErr == nil {
+				if err != nil || result.Signature != "sig" || result.Confirmation.Status != ConfirmationConfirmed {
+					t.Fatalf("result = %+v, %v, want sig confirmed", result, err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) || (tt.notWant != nil && errors.Is(err, tt.notWant)) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			var pending *PendingTransactionError
+			if errors.As(err, &pending) != (tt.wantSigs > 0) || (pending != nil && len(pending.Signatures) != tt.wantSigs) {
+				t.Errorf("pending = %+v, want %d signatures", pending, tt.wantSigs)
+			}
+		})
+	}
+}
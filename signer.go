@@ -0,0 +1,93 @@
+package jupag
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Signer signs transaction messages.
+type Signer interface {
+	PublicKey() PublicKey
+	Sign(message []byte) ([]byte, error)
+}
+
+// KeypairSigner is a Signer backed by an ed25519 private key.
+type KeypairSigner struct {
+	key ed25519.PrivateKey
+}
+
+// NewKeypairSigner returns a Signer for the given private key.
+func NewKeypairSigner(key ed25519.PrivateKey) *KeypairSigner {
+	return &KeypairSigner{key: key}
+}
+
+// LoadKeypairFile loads a solana-keygen JSON keypair file (an array of 64 bytes).
+func LoadKeypairFile(path string) (*KeypairSigner, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keypair file: %w", err)
+	}
+
+	var key []byte
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, fmt.Errorf("failed to parse keypair file: %w", err)
+	}
+	if len(key) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid keypair length: expected %d bytes, got %d", ed25519.PrivateKeySize, len(key))
+	}
+
+	return NewKeypairSigner(ed25519.PrivateKey(key)), nil
+}
+
+// PublicKey returns the public key of the keypair.
+func (s *KeypairSigner) PublicKey() PublicKey {
+	var pk PublicKey
+	copy(pk[:], s.key.Public().(ed25519.PublicKey))
+	return pk
+}
+
+// Sign signs the message with the private key.
+func (s *KeypairSigner) Sign(message []byte) ([]byte, error) {
+	return ed25519.Sign(s.key, message), nil
+}
+
+// Sign signs the transaction message with the given signers, placing each signature
+// in the slot of the matching signer account. Signers that are not required are rejected.
+func (tx *Transaction) Sign(signers ...Signer) error {
+	numSigners := int(tx.Message.Header.NumRequiredSignatures)
+	if len(tx.Signatures) != numSigners {
+		sigs := make([][]byte, numSigners)
+		copy(sigs, tx.Signatures)
+		tx.Signatures = sigs
+	}
+
+	message := tx.Message.Marshal()
+	for _, signer := range signers {
+		idx := -1
+		for i := 0; i < numSigners && i < len(tx.Message.AccountKeys); i++ {
+			if tx.Message.AccountKeys[i] == signer.PublicKey() {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			return fmt.Errorf("signer %s is not required by the transaction", signer.PublicKey())
+		}
+
+		sig, err := signer.Sign(message)
+		if err != nil {
+			return fmt.Errorf("failed to sign transaction: %w", err)
+		}
+		tx.Signatures[idx] = sig
+	}
+
+	for i, sig := range tx.Signatures {
+		if sig == nil {
+			tx.Signatures[i] = make([]byte, ed25519.SignatureSize)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package jupag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrSlippageExceeded  = errors.New("slippage tolerance exceeded")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrAccountNotFound   = errors.New("account not found")
+)
+
+// Jupiter aggregator program custom error codes.
+const (
+	jupiterErrSlippageToleranceExceeded = 6001
+	tokenErrInsufficientFunds           = 1
+)
+
+// SimulationResult is the result of a swap transaction simulation.
+type SimulationResult struct {
+	Slot          uint64          `json:"slot"`
+	Err           json.RawMessage `json:"err,omitempty"` // raw transaction error, empty when the simulation succeeded
+	Logs          []string        `json:"logs"`
+	UnitsConsumed uint64          `json:"unitsConsumed"` // compute units consumed by the transaction
+}
+
+// SimulationError is returned when a simulated transaction fails.
+// It wraps one of ErrSlippageExceeded, ErrInsufficientFunds or ErrAccountNotFound when the failure is recognized.
+type SimulationError struct {
+	Raw    json.RawMessage // raw transaction error
+	Logs   []string
+	Reason error
+}
+
+func (e *SimulationError) Error() string {
+	if e.Reason != nil {
+		return fmt.Sprintf("simulation failed: %s: %s", e.Reason, e.Raw)
+	}
+	return fmt.Sprintf("simulation failed: %s", e.Raw)
+}
+
+func (e *SimulationError) Unwrap() error {
+	return e.Reason
+}
+
+type simulateTransactionResult struct {
+	Context struct {
+		Slot uint64 `json:"slot"`
+	} `json:"context"`
+	Value struct {
+		Err           json.RawMessage `json:"err"`
+		Logs          []string        `json:"logs"`
+		UnitsConsumed uint64          `json:"unitsConsumed"`
+	} `json:"value"`
+}
+
+// SimulateSwap runs simulateTransaction for a base64 encoded swap transaction.
+// The recent blockhash is replaced and signatures are not verified, so unsigned transactions can be simulated.
+// When the simulation fails, the result is returned alongside a *SimulationError.
+func SimulateSwap(ctx context.Context, tx string, rpc RPCClient) (SimulationResult, error) {
+	var out simulateTransactionResult
+	err := rpc.Call(ctx, "simulateTransaction", []any{
+		tx,
+		map[string]any{
+			"encoding":               "base64",
+			"sigVerify":              false,
+			"replaceRecentBlockhash": true,
+			"commitment":             "processed",
+		},
+	}, &out)
+	if err != nil {
+		return SimulationResult{}, fmt.Errorf("failed to simulate swap: %w", err)
+	}
+
+	result := SimulationResult{
+		Slot:          out.Context.Slot,
+		Logs:          out.Value.Logs,
+		UnitsConsumed: out.Value.UnitsConsumed,
+	}
+	if len(out.Value.Err) == 0 || string(out.Value.Err) == "null" {
+		return result, nil
+	}
+
+	result.Err = out.Value.Err
+	return result, &SimulationError{
+		Raw:    out.Value.Err,
+		Logs:   out.Value.Logs,
+		Reason: decodeTransactionError(out.Value.Err, out.Value.Logs),
+	}
+}
+
+// decodeTransactionError maps a raw transaction error to one of the typed errors.
+// It returns nil when the error is not recognized.
+func decodeTransactionError(raw json.RawMessage, logs []string) error {
+	var name string
+	if err := json.Unmarshal(raw, &name); err == nil {
+		return transactionErrorByName(name)
+	}
+
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil
+	}
+
+	for name, val := range obj {
+		if name != "InstructionError" {
+			return transactionErrorByName(name)
+		}
+
+		// InstructionError is [index, "Name"] or [index, {"Custom": code}].
+		var ie []json.RawMessage
+		if err := json.Unmarshal(val, &ie); err != nil || len(ie) != 2 {
+			return nil
+		}
+		var custom struct {
+			Custom *int64 `json:"Custom"`
+		}
+		if err := json.Unmarshal(ie[1], &custom); err == nil && custom.Custom != nil {
+			return customErrorByCode(*custom.Custom, logs)
+		}
+		var instrName string
+		if err := json.Unmarshal(ie[1], &instrName); err == nil {
+			return transactionErrorByName(instrName)
+		}
+	}
+
+	return nil
+}
+
+func transactionErrorByName(name string) error {
+	switch name {
+	case "AccountNotFound", "ProgramAccountNotFound", "InvalidAccountForFee", "UninitializedAccount":
+		return ErrAccountNotFound
+	case "InsufficientFundsForFee", "InsufficientFundsForRent", "InsufficientFunds":
+		return ErrInsufficientFunds
+	}
+	return nil
+}
+
+func customErrorByCode(code int64, logs []string) error {
+	switch code {
+	case jupiterErrSlippageToleranceExceeded:
+		return ErrSlippageExceeded
+	case tokenErrInsufficientFunds:
+		// Custom error 1 is ambiguous across programs, confirm with the token program log.
+		for _, log := range logs {
+			if strings.Contains(log, "insufficient funds") || strings.Contains(log, "insufficient lamports") {
+				return ErrInsufficientFunds
+			}
+		}
+	}
+
+	for _, log := range logs {
+		if strings.Contains(log, "SlippageToleranceExceeded") {
+			return ErrSlippageExceeded
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package jupag
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// SlippageEngineConfig configures a SlippageEngine.
+type SlippageEngineConfig struct {
+	DefaultBps uint64  // recommendation when there is no history, default: 50
+	MinBps     uint64  // lower bound of recommendations, default: 10
+	MaxBps     uint64  // upper bound of recommendations, default: 300
+	Percentile float64 // percentile of realized slippage to cover, default: 0.95
+	MarginBps  uint64  // added on top of the percentile, default: 5
+	Window     int     // observations kept per pair and size bucket, default: 200
+	MinSamples int     // observations required before a bucket is trusted, default: 5
+}
+
+// SlippageEngine learns realized slippage per pair and trade size and recommends slippageBps values.
+type SlippageEngine struct {
+	cfg SlippageEngineConfig
+
+	mu      sync.RWMutex
+	history map[Pair]map[int][]float64 // pair -> size bucket -> realized slippage bps
+}
+
+// NewSlippageEngine returns a SlippageEngine, zero config values are replaced with defaults.
+func NewSlippageEngine(cfg SlippageEngineConfig) *SlippageEngine {
+	if cfg.DefaultBps == 0 {
+		cfg.DefaultBps = 50
+	}
+	if cfg.MinBps == 0 {
+		cfg.MinBps = 10
+	}
+	if cfg.MaxBps == 0 {
+		cfg.MaxBps = 300
+	}
+	if cfg.Percentile <= 0 || cfg.Percentile > 1 {
+		cfg.Percentile = 0.95
+	}
+	if cfg.MarginBps == 0 {
+		cfg.MarginBps = 5
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = 200
+	}
+	if cfg.MinSamples <= 0 {
+		cfg.MinSamples = 5
+	}
+
+	return &SlippageEngine{
+		cfg:     cfg,
+		history: make(map[Pair]map[int][]float64),
+	}
+}
+
+// Observe records the realized slippage of a trade of the given raw input size.
+func (e *SlippageEngine) Observe(pair Pair, size uint64, realizedBps float64) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	buckets, ok := e.history[pair]
+	if !ok {
+		buckets = make(map[int][]float64)
+		e.history[pair] = buckets
+	}
+
+	b := sizeBucket(size)
+	samples := append(buckets[b], realizedBps)
+	if len(samples) > e.cfg.Window {
+		samples = samples[len(samples)-e.cfg.Window:]
+	}
+	buckets[b] = samples
+}
+
+// ObserveExecution records the realized slippage of a journal entry, entries without a quoted amount are ignored.
+func (e *SlippageEngine) ObserveExecution(entry JournalEntry) {
+	bps, ok := entry.RealizedSlippageBps()
+	if !ok {
+		return
+	}
+	e.Observe(Pair{InputMint: entry.InputMint, OutputMint: entry.OutputMint}, entry.InAmount, bps)
+}
+
+// RecommendSlippage returns the recommended slippageBps for a trade of the given raw input size.
+// It uses the size bucket when it has enough history, then the whole pair, then the default.
+func (e *SlippageEngine) RecommendSlippage(pair Pair, size uint64) uint64 {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	buckets := e.history[pair]
+	samples := buckets[sizeBucket(size)]
+	if len(samples) < e.cfg.MinSamples {
+		samples = nil
+		for _, s := range buckets {
+			samples = append(samples, s...)
+		}
+	}
+	if len(samples) < e.cfg.MinSamples {
+		return e.cfg.DefaultBps
+	}
+
+	bps := percentile(samples, e.cfg.Percentile)
+	if bps < 0 {
+		bps = 0
+	}
+	rec := uint64(math.Ceil(bps)) + e.cfg.MarginBps
+
+	return min(max(rec, e.cfg.MinBps), e.cfg.MaxBps)
+}
+
+// Apply sets params.SlippageBps to the recommendation when it is not specified.
+func (e *SlippageEngine) Apply(params *QuoteParams) {
+	if params.SlippageBps != 0 {
+		return
+	}
+	params.SlippageBps = e.RecommendSlippage(Pair{InputMint: params.InputMint, OutputMint: params.OutputMint}, params.Amount)
+}
+
+// sizeBucket groups trade sizes by order of magnitude.
+func sizeBucket(size uint64) int {
+	if size == 0 {
+		return 0
+	}
+	return int(math.Log10(float64(size)))
+}
+
+func percentile(samples []float64, p float64) float64 {
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	idx := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[min(max(idx, 0), len(sorted)-1)]
+}
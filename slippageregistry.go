@@ -0,0 +1,105 @@
+package jupag
+
+import "sync"
+
+// SlippageLimits are the default slippage and price impact limit of swaps involving a token.
+type SlippageLimits struct {
+	SlippageBps       uint64  // slippage tolerance used when the caller doesn't set one, 0: unset
+	MaxPriceImpactPct float64 // maximum route price impact in percent, e.g. 1 for 1%, 0: unset
+}
+
+// SlippageRegistry maps mints, or tags assigned to mints such as "stable" or "meme", to default slippage
+// and price impact limits. It is consulted by BestSwap, SwapAndSend and SimpleSwap when configured with
+// WithSlippageRegistry. It is safe for concurrent use.
+type SlippageRegistry struct {
+	mu       sync.RWMutex
+	fallback *SlippageLimits
+	mints    map[string]SlippageLimits
+	tags     map[string]SlippageLimits
+	mintTags map[string][]string
+}
+
+// NewSlippageRegistry returns an empty registry.
+func NewSlippageRegistry() *SlippageRegistry {
+	return &SlippageRegistry{
+		mints:    make(map[string]SlippageLimits),
+		tags:     make(map[string]SlippageLimits),
+		mintTags: make(map[string][]string),
+	}
+}
+
+// SetDefault sets the limits of the mints without limits of their own or of their tags.
+func (r *SlippageRegistry) SetDefault(limits SlippageLimits) *SlippageRegistry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallback = &limits
+	return r
+}
+
+// SetMint sets the limits of a mint, they take precedence over the limits of its tags.
+func (r *SlippageRegistry) SetMint(mint string, limits SlippageLimits) *SlippageRegistry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mints[mint] = limits
+	return r
+}
+
+// SetTag sets the limits of a tag.
+func (r *SlippageRegistry) SetTag(tag string, limits SlippageLimits) *SlippageRegistry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tags[tag] = limits
+	return r
+}
+
+// TagMint assigns tags to a mint, the first tag having limits applies.
+func (r *SlippageRegistry) TagMint(mint string, tags ...string) *SlippageRegistry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mintTags[mint] = append(r.mintTags[mint], tags...)
+	return r
+}
+
+// Limits returns the limits of a swap between two mints, the loosest of the limits of both mints,
+// so the riskier token of the pair prevails. ok is false when neither mint nor the default has limits.
+func (r *SlippageRegistry) Limits(inputMint, outputMint string) (limits SlippageLimits, ok bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	in, inOk := r.mintLimits(inputMint)
+	out, outOk := r.mintLimits(outputMint)
+	switch {
+	case inOk && outOk:
+		return SlippageLimits{
+			SlippageBps:       max(in.SlippageBps, out.SlippageBps),
+			MaxPriceImpactPct: max(in.MaxPriceImpactPct, out.MaxPriceImpactPct),
+		}, true
+	case inOk:
+		return in, true
+	case outOk:
+		return out, true
+	case r.fallback != nil:
+		return *r.fallback, true
+	}
+	return SlippageLimits{}, false
+}
+
+func (r *SlippageRegistry) mintLimits(mint string) (SlippageLimits, bool) {
+	if limits, ok := r.mints[mint]; ok {
+		return limits, true
+	}
+	for _, tag := range r.mintTags[mint] {
+		if limits, ok := r.tags[tag]; ok {
+			return limits, true
+		}
+	}
+	return SlippageLimits{}, false
+}
+
+// slippageLimits returns the registry limits of a swap between two mints, if any.
+func (c *JupagImpl) slippageLimits(inputMint, outputMint string) (SlippageLimits, bool) {
+	if c.slippageRegistry == nil {
+		return SlippageLimits{}, false
+	}
+	return c.slippageRegistry.Limits(inputMint, outputMint)
+}
@@ -0,0 +1,253 @@
+// Package sqlstore implements jupag.TradeStore on a Postgres or SQLite database through database/sql, so bots get
+// an audit trail of their quotes, swaps, submissions, confirmations and results. The database driver is
+// registered by the application, e.g. github.com/jackc/pgx/v5/stdlib or modernc.org/sqlite.
+//
+//	db, _ := sql.Open("pgx", dsn)
+//	store := sqlstore.New(db, sqlstore.Postgres)
+//	if err := store.Migrate(ctx); err != nil { ... }
+//	client := jupag.NewJupag(jupag.WithTradeStore(store))
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	jupag "github.com/ipanardian/go-jup-ag"
+)
+
+var _ jupag.TradeStore = (*Store)(nil)
+
+// Dialect is the SQL dialect of a database.
+type Dialect int
+
+const (
+	Postgres Dialect = iota
+	SQLite
+)
+
+// Store is a jupag.TradeStore on a SQL database. Amounts are stored as decimal strings, as they may not fit
+// in a signed 64-bit column, and the full quotes and routes as JSON.
+type Store struct {
+	db      *sql.DB
+	dialect Dialect
+	prefix  string
+}
+
+// New returns a store on db, its tables are created by Migrate.
+func New(db *sql.DB, dialect Dialect) *Store {
+	return &Store{db: db, dialect: dialect, prefix: "jupag_"}
+}
+
+// WithTablePrefix returns the store using tables prefixed with prefix instead of "jupag_".
+func (s *Store) WithTablePrefix(prefix string) *Store {
+	t := *s
+	t.prefix = prefix
+	return &t
+}
+
+// Migrate creates the tables and indexes of the store if they don't exist.
+func (s *Store) Migrate(ctx context.Context) error {
+	id, ts, js := "BIGSERIAL PRIMARY KEY", "TIMESTAMPTZ", "JSONB"
+	if s.dialect == SQLite {
+		id, ts, js = "INTEGER PRIMARY KEY AUTOINCREMENT", "TEXT", "TEXT"
+	}
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS {quotes} (
+			id {id},
+			created_at {ts} NOT NULL,
+			input_mint TEXT NOT NULL,
+			output_mint TEXT NOT NULL,
+			amount TEXT NOT NULL,
+			swap_mode TEXT NOT NULL,
+			slippage_bps BIGINT NOT NULL,
+			context_slot BIGINT NOT NULL,
+			out_amount TEXT NOT NULL,
+			quote {json} NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS {swaps} (
+			id {id},
+			created_at {ts} NOT NULL,
+			user_public_key TEXT NOT NULL,
+			input_mint TEXT NOT NULL,
+			output_mint TEXT NOT NULL,
+			in_amount TEXT NOT NULL,
+			out_amount TEXT NOT NULL,
+			last_valid_block_height BIGINT NOT NULL,
+			prioritization_fee_lamports BIGINT NOT NULL,
+			transaction_base64 TEXT NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS {submissions} (
+			id {id},
+			created_at {ts} NOT NULL,
+			signature TEXT NOT NULL,
+			input_mint TEXT NOT NULL,
+			output_mint TEXT NOT NULL,
+			in_amount TEXT NOT NULL,
+			out_amount TEXT NOT NULL,
+			route {json} NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS {confirmations} (
+			id {id},
+			created_at {ts} NOT NULL,
+			signature TEXT NOT NULL,
+			status TEXT NOT NULL,
+			slot BIGINT NOT NULL,
+			error TEXT NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS {results} (
+			id {id},
+			created_at {ts} NOT NULL,
+			signature TEXT NOT NULL,
+			status TEXT NOT NULL,
+			attempts BIGINT NOT NULL,
+			input_mint TEXT NOT NULL,
+			output_mint TEXT NOT NULL,
+			in_amount TEXT NOT NULL,
+			quoted_out_amount TEXT NOT NULL,
+			received_amount TEXT NOT NULL,
+			error TEXT NOT NULL
+		)`,
+		`CREATE INDEX IF NOT EXISTS {submissions}_signature ON {submissions} (signature)`,
+		`CREATE INDEX IF NOT EXISTS {confirmations}_signature ON {confirmations} (signature)`,
+		`CREATE INDEX IF NOT EXISTS {results}_signature ON {results} (signature)`,
+	}
+	for _, statement := range statements {
+		statement = strings.NewReplacer("{id}", id, "{ts}", ts, "{json}", js).Replace(s.tables(statement))
+		if _, err := s.db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to migrate trade store: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *Store) SaveQuote(ctx context.Context, params jupag.QuoteParams, quote jupag.QuoteResponse) error {
+	data, err := json.Marshal(quote)
+	if err != nil {
+		return fmt.Errorf("failed to encode quote: %w", err)
+	}
+	var best jupag.Route
+	if len(quote) > 0 {
+		best = quote[0]
+	}
+	swapMode := params.SwapMode
+	if swapMode == "" {
+		swapMode = jupag.SwapModeExactIn
+	}
+	return s.insert(ctx, "{quotes}",
+		[]string{"input_mint", "output_mint", "amount", "swap_mode", "slippage_bps", "context_slot", "out_amount", "quote"},
+		params.InputMint, params.OutputMint, strconv.FormatUint(params.Amount, 10), swapMode,
+		best.SlippageBps, int64(quote.ContextSlot()), best.OutAmount.String(), string(data),
+	)
+}
+
+func (s *Store) SaveSwap(ctx context.Context, params jupag.SwapParams, swap jupag.SwapResponse) error {
+	input, output := routeMints(params.Route)
+	return s.insert(ctx, "{swaps}",
+		[]string{"user_public_key", "input_mint", "output_mint", "in_amount", "out_amount", "last_valid_block_height", "prioritization_fee_lamports", "transaction_base64"},
+		params.UserPublicKey, input, output, params.Route.InAmount.String(), params.Route.OutAmount.String(),
+		int64(swap.LastValidBlockHeight), int64(swap.PrioritizationFeeLamports), swap.SwapTransaction,
+	)
+}
+
+func (s *Store) SaveSubmission(ctx context.Context, signature string, route jupag.Route) error {
+	data, err := json.Marshal(route)
+	if err != nil {
+		return fmt.Errorf("failed to encode route: %w", err)
+	}
+	input, output := routeMints(route)
+	return s.insert(ctx, "{submissions}",
+		[]string{"signature", "input_mint", "output_mint", "in_amount", "out_amount", "route"},
+		signature, input, output, route.InAmount.String(), route.OutAmount.String(), string(data),
+	)
+}
+
+func (s *Store) SaveConfirmation(ctx context.Context, result jupag.ConfirmationResult) error {
+	return s.insert(ctx, "{confirmations}",
+		[]string{"signature", "status", "slot", "error"},
+		result.Signature, string(result.Status), int64(result.Slot), confirmationError(result),
+	)
+}
+
+// SaveResult saves the landed or last attempt of the result, with the error of SwapAndSend.
+func (s *Store) SaveResult(ctx context.Context, result jupag.SwapResult, err error) error {
+	signature, route, confirmation := result.Signature, result.Route, result.Confirmation
+	if signature == "" && len(result.Attempts) > 0 {
+		last := result.Attempts[len(result.Attempts)-1]
+		signature, route, confirmation = last.Signature, last.Route, last.Confirmation
+	}
+	status := string(confirmation.Status)
+	if status == "" {
+		status = "unconfirmed"
+	}
+	received := ""
+	if !result.Received.IsZero() {
+		received = result.Received.String()
+	}
+	errText := ""
+	if err != nil {
+		errText = err.Error()
+	}
+	input, output := routeMints(route)
+	return s.insert(ctx, "{results}",
+		[]string{"signature", "status", "attempts", "input_mint", "output_mint", "in_amount", "quoted_out_amount", "received_amount", "error"},
+		signature, status, int64(len(result.Attempts)), input, output, route.InAmount.String(), route.OutAmount.String(), received, errText,
+	)
+}
+
+// insert inserts a row with the current time as created_at.
+func (s *Store) insert(ctx context.Context, table string, columns []string, values ...any) error {
+	placeholders := make([]string, len(values)+1)
+	for i := range placeholders {
+		placeholders[i] = "?"
+		if s.dialect == Postgres {
+			placeholders[i] = "$" + strconv.Itoa(i+1)
+		}
+	}
+	query := fmt.Sprintf("INSERT INTO %s (created_at, %s) VALUES (%s)",
+		s.tables(table), strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+
+	if _, err := s.db.ExecContext(ctx, query, append([]any{s.now()}, values...)...); err != nil {
+		return fmt.Errorf("failed to insert into %s: %w", s.tables(table), err)
+	}
+	return nil
+}
+
+// now returns the current time as stored in the created_at columns, RFC 3339 text in SQLite.
+func (s *Store) now() any {
+	now := time.Now().UTC()
+	if s.dialect == SQLite {
+		return now.Format(time.RFC3339Nano)
+	}
+	return now
+}
+
+// tables replaces the {table} placeholders of a statement by the prefixed table names.
+func (s *Store) tables(statement string) string {
+	for _, table := range []string{"quotes", "swaps", "submissions", "confirmations", "results"} {
+		statement = strings.ReplaceAll(statement, "{"+table+"}", s.prefix+table)
+	}
+	return statement
+}
+
+// routeMints returns the input mint of the first market and the output mint of the last one.
+func routeMints(route jupag.Route) (string, string) {
+	if len(route.MarketInfos) == 0 {
+		return "", ""
+	}
+	return route.MarketInfos[0].InputMint, route.MarketInfos[len(route.MarketInfos)-1].OutputMint
+}
+
+func confirmationError(result jupag.ConfirmationResult) string {
+	if result.Reason != nil {
+		return result.Reason.Error()
+	}
+	if len(result.Err) > 0 && string(result.Err) != "null" {
+		return string(result.Err)
+	}
+	return ""
+}
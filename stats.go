@@ -0,0 +1,190 @@
+package jupag
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// statsWindow is the number of latest requests per endpoint the latency percentiles are computed on.
+const statsWindow = 1024
+
+// Stats are the request and send statistics of the client, collected when WithStats is set.
+type Stats struct {
+	Endpoints map[Endpoint]LatencyStats
+	Sends     SendStats
+}
+
+// LatencyStats are the statistics of the requests to an endpoint. The percentiles cover the latest requests.
+type LatencyStats struct {
+	Requests uint64
+	Failures uint64 // transport errors and error statuses
+	P50      time.Duration
+	P90      time.Duration
+	P99      time.Duration
+	Max      time.Duration
+}
+
+// SendStats are the statistics of the submitted transactions, each resubmission counting as a submission.
+type SendStats struct {
+	Submitted uint64
+	Landed    uint64 // confirmed without error
+	Failed    uint64 // landed with an on-chain error
+	Expired   uint64 // blockhash expired before landing
+	// LandingRate is Landed over Submitted.
+	LandingRate float64
+	// AvgSlotsToConfirmation is the average number of slots between the submission and the landing slot.
+	AvgSlotsToConfirmation float64
+}
+
+type stats struct {
+	mu        sync.Mutex
+	endpoints map[Endpoint]*endpointLatency
+	sends     SendStats
+	slots     uint64 // total slots to confirmation of the landed transactions with a known submission slot
+	timed     uint64 // landed transactions with a known submission slot
+}
+
+type endpointLatency struct {
+	requests uint64
+	failures uint64
+	max      time.Duration
+	samples  []time.Duration // ring buffer of the latest latencies
+	next     int
+}
+
+func newStats() *stats {
+	return &stats{endpoints: make(map[Endpoint]*endpointLatency)}
+}
+
+func (s *stats) recordRequest(e Endpoint, latency time.Duration, resp *http.Response, err error) {
+	if s == nil || e == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	l, ok := s.endpoints[e]
+	if !ok {
+		l = &endpointLatency{}
+		s.endpoints[e] = l
+	}
+	l.requests++
+	if err != nil || resp == nil || resp.StatusCode >= http.StatusBadRequest {
+		l.failures++
+	}
+	l.max = max(l.max, latency)
+	if len(l.samples) < statsWindow {
+		l.samples = append(l.samples, latency)
+	} else {
+		l.samples[l.next] = latency
+		l.next = (l.next + 1) % statsWindow
+	}
+}
+
+func (s *stats) submitted() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sends.Submitted++
+}
+
+// confirmed records the outcome of a submission, submitSlot is 0 when unknown.
+func (s *stats) confirmed(result ConfirmationResult, submitSlot uint64) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch result.Status {
+	case ConfirmationConfirmed:
+		s.sends.Landed++
+		if submitSlot > 0 && result.Slot >= submitSlot {
+			s.slots += result.Slot - submitSlot
+			s.timed++
+		}
+	case ConfirmationFailed:
+		s.sends.Failed++
+	case ConfirmationExpired:
+		s.sends.Expired++
+	}
+}
+
+func (s *stats) snapshot() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := Stats{Endpoints: make(map[Endpoint]LatencyStats, len(s.endpoints)), Sends: s.sends}
+	for e, l := range s.endpoints {
+		samples := slices.Clone(l.samples)
+		slices.Sort(samples)
+		out.Endpoints[e] = LatencyStats{
+			Requests: l.requests,
+			Failures: l.failures,
+			P50:      latencyPercentile(samples, 0.5),
+			P90:      latencyPercentile(samples, 0.9),
+			P99:      latencyPercentile(samples, 0.99),
+			Max:      l.max,
+		}
+	}
+	if s.sends.Submitted > 0 {
+		out.Sends.LandingRate = float64(s.sends.Landed) / float64(s.sends.Submitted)
+	}
+	if s.timed > 0 {
+		out.Sends.AvgSlotsToConfirmation = float64(s.slots) / float64(s.timed)
+	}
+	return out
+}
+
+// latencyPercentile returns the nearest rank p percentile of sorted latencies.
+func latencyPercentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[min(max(idx, 0), len(sorted)-1)]
+}
+
+// Stats returns the request latencies per endpoint and the landing statistics of the submitted transactions,
+// e.g. to tune slippage and priority fees. It returns empty statistics when WithStats isn't set.
+func (c *JupagImpl) Stats() Stats {
+	if c.stats == nil {
+		return Stats{Endpoints: map[Endpoint]LatencyStats{}}
+	}
+	return c.stats.snapshot()
+}
+
+// endpointOf returns the endpoint of a request URL, the one with the longest matching URL, or "" if none.
+func (c *JupagImpl) endpointOf(completeUrl string) Endpoint {
+	var (
+		found   Endpoint
+		longest int
+	)
+	for e := range defaultEndpoints {
+		u := c.endpoint(e)
+		if len(u) > longest && strings.HasPrefix(completeUrl, u) {
+			found, longest = e, len(u)
+		}
+	}
+	return found
+}
+
+// submitSlot returns the current slot to measure the slots to confirmation of a submission,
+// or 0 when the statistics aren't collected or the slot is unknown.
+func (c *JupagImpl) submitSlot(ctx context.Context) uint64 {
+	if c.stats == nil {
+		return 0
+	}
+	var slot uint64
+	if err := c.rpc.Call(ctx, "getSlot", []any{map[string]any{"commitment": CommitmentProcessed}}, &slot); err != nil {
+		return 0
+	}
+	return slot
+}
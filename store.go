@@ -0,0 +1,52 @@
+package jupag
+
+import (
+	"context"
+	"log/slog"
+)
+
+// TradeStore persists the trade history of the client, e.g. as an audit trail of a bot. The sqlstore package
+// implements it on Postgres and SQLite. Pass it to WithTradeStore.
+type TradeStore interface {
+	SaveQuote(ctx context.Context, params QuoteParams, quote QuoteResponse) error
+	SaveSwap(ctx context.Context, params SwapParams, swap SwapResponse) error
+	SaveSubmission(ctx context.Context, signature string, route Route) error
+	SaveConfirmation(ctx context.Context, result ConfirmationResult) error
+	// SaveResult saves the realized result of SwapAndSend, including the failed ones.
+	SaveResult(ctx context.Context, result SwapResult, err error) error
+}
+
+// storeEvents returns the events saving the trade history to the store.
+func (c *JupagImpl) storeEvents() Events {
+	return Events{
+		OnQuote: func(ctx context.Context, params QuoteParams, quote QuoteResponse) {
+			c.stored(ctx, "quote", c.tradeStore.SaveQuote(ctx, params, quote))
+		},
+		OnSwapBuilt: func(ctx context.Context, params SwapParams, swap SwapResponse) {
+			c.stored(ctx, "swap", c.tradeStore.SaveSwap(ctx, params, swap))
+		},
+		OnSubmitted: func(ctx context.Context, signature string, route Route) {
+			c.stored(ctx, "submission", c.tradeStore.SaveSubmission(ctx, signature, route))
+		},
+		OnConfirmed: func(ctx context.Context, result ConfirmationResult) {
+			c.stored(ctx, "confirmation", c.tradeStore.SaveConfirmation(ctx, result))
+		},
+	}
+}
+
+// storeResult saves the result of SwapAndSend to the store if any, unless nothing was attempted.
+func (c *JupagImpl) storeResult(ctx context.Context, result SwapResult, err error) {
+	if c.tradeStore != nil && len(result.Attempts) > 0 {
+		c.stored(ctx, "result", c.tradeStore.SaveResult(ctx, result, err))
+	}
+}
+
+// stored logs the store failures, they don't affect the trade.
+func (c *JupagImpl) stored(ctx context.Context, kind string, err error) {
+	if err != nil && c.logger != nil {
+		c.logger.LogAttrs(ctx, slog.LevelWarn, "jupiter api trade history not stored",
+			slog.String("kind", kind),
+			slog.String("error", err.Error()),
+		)
+	}
+}
@@ -0,0 +1,103 @@
+package jupag
+
+import (
+	"context"
+	"math/big"
+	"time"
+)
+
+// PriceUpdate is an event of a price subscription.
+// Either Err is set, or Price holds the new price of Mint.
+type PriceUpdate struct {
+	Time      time.Time
+	Mint      string
+	Price     Price
+	Previous  *Price  // nil for the first price of the mint
+	ChangePct float64 // change from the previous price, in percent
+	Err       error
+}
+
+// SubscribePrices polls the prices of the mints every interval and sends an update for each first or changed price.
+// Polling errors are sent as updates with Err set. The channel is closed when ctx is done. The interval defaults
+// to 10s when not positive.
+func (c *JupagImpl) SubscribePrices(ctx context.Context, mints []string, interval time.Duration) <-chan PriceUpdate {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	updates := make(chan PriceUpdate, len(mints))
+
+	go func() {
+		defer close(updates)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		last := make(map[string]Price, len(mints))
+		for {
+			if !c.pollPrices(ctx, mints, last, updates) {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return updates
+}
+
+// pollPrices fetches the prices and sends the changes, it returns false when ctx is done.
+func (c *JupagImpl) pollPrices(ctx context.Context, mints []string, last map[string]Price, updates chan<- PriceUpdate) bool {
+	send := func(u PriceUpdate) bool {
+		select {
+		case updates <- u:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	prices, err := c.Prices(ctx, mints)
+	now := time.Now()
+	if err != nil {
+		if ctx.Err() != nil {
+			return false
+		}
+		return send(PriceUpdate{Time: now, Err: err})
+	}
+
+	for _, mint := range mints {
+		p, ok := prices[mint]
+		if !ok {
+			continue
+		}
+
+		update := PriceUpdate{Time: now, Mint: mint, Price: p}
+		if prev, ok := last[mint]; ok {
+			if prev.decimal().Cmp(p.decimal()) == 0 {
+				continue
+			}
+			update.Previous = &prev
+			update.ChangePct = priceChangePct(prev.decimal(), p.decimal())
+		}
+		last[mint] = p
+
+		if !send(update) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func priceChangePct(from, to Decimal) float64 {
+	a := from.Rat()
+	if a.Sign() == 0 {
+		return 0
+	}
+	change, _ := new(big.Rat).Quo(new(big.Rat).Sub(to.Rat(), a), a).Float64()
+	return change * 100
+}
@@ -0,0 +1,80 @@
+package jupag
+
+import (
+	"context"
+	"fmt"
+)
+
+// SwapInstructions are the instructions of a swap, to compose into a custom transaction.
+type SwapInstructions struct {
+	TokenLedgerInstruction      *Instruction  `json:"tokenLedgerInstruction,omitempty"`
+	ComputeBudgetInstructions   []Instruction `json:"computeBudgetInstructions"`
+	SetupInstructions           []Instruction `json:"setupInstructions"` // e.g. creating the token accounts of the user
+	SwapInstruction             Instruction   `json:"swapInstruction"`
+	CleanupInstruction          *Instruction  `json:"cleanupInstruction,omitempty"` // e.g. unwrapping SOL
+	OtherInstructions           []Instruction `json:"otherInstructions,omitempty"`
+	AddressLookupTableAddresses []PublicKey   `json:"addressLookupTableAddresses"`
+}
+
+// ComposeOptions are the options composing swap instructions into a transaction.
+type ComposeOptions struct {
+	// NativeSOL handles native SOL when the swap was built with WrapUnwrapSol disabled (optional).
+	NativeSOL *NativeSOLOptions
+}
+
+// NativeSOLOptions wrap native SOL into the wSOL account of the user before the swap and unwrap it after.
+type NativeSOLOptions struct {
+	Owner        PublicKey // user wallet
+	Account      PublicKey // wSOL token account of the user, it must exist before the wrap
+	WrapLamports uint64    // lamports to wrap before the swap, 0 when SOL is not the input
+	Unwrap       bool      // close the wSOL account after the swap, e.g. when SOL is the output
+}
+
+// Instructions returns the swap instructions in execution order: compute budget, setup, token ledger, swap,
+// cleanup and the other instructions. The native SOL wrap runs after the setup and the unwrap after the cleanup.
+func (s SwapInstructions) Instructions(opts ComposeOptions) []Instruction {
+	ixs := make([]Instruction, 0, len(s.ComputeBudgetInstructions)+len(s.SetupInstructions)+len(s.OtherInstructions)+6)
+	ixs = append(ixs, s.ComputeBudgetInstructions...)
+	ixs = append(ixs, s.SetupInstructions...)
+	if opts.NativeSOL != nil && opts.NativeSOL.WrapLamports > 0 {
+		ixs = append(ixs, WrapSOLInstructions(opts.NativeSOL.Owner, opts.NativeSOL.Account, opts.NativeSOL.WrapLamports)...)
+	}
+	if s.TokenLedgerInstruction != nil {
+		ixs = append(ixs, *s.TokenLedgerInstruction)
+	}
+	ixs = append(ixs, s.SwapInstruction)
+	if s.CleanupInstruction != nil {
+		ixs = append(ixs, *s.CleanupInstruction)
+	}
+	if opts.NativeSOL != nil && opts.NativeSOL.Unwrap {
+		ixs = append(ixs, UnwrapSOLInstruction(opts.NativeSOL.Owner, opts.NativeSOL.Account))
+	}
+	return append(ixs, s.OtherInstructions...)
+}
+
+// SwapInstructions returns the instructions of a swap instead of a serialized transaction.
+func (c *JupagImpl) SwapInstructions(ctx context.Context, params SwapParams) (SwapInstructions, error) {
+	if err := params.Validate(); err != nil {
+		return SwapInstructions{}, err
+	}
+	if err := c.CheckQuoteFreshness(ctx, params.Route); err != nil {
+		return SwapInstructions{}, err
+	}
+	if err := c.checkTransferFee(ctx, params.Route); err != nil {
+		return SwapInstructions{}, err
+	}
+	if err := c.checkNotional(ctx, params.Route); err != nil {
+		return SwapInstructions{}, err
+	}
+	if err := applyPriorityFee(ctx, &params); err != nil {
+		return SwapInstructions{}, err
+	}
+	if err := c.applyFeeAccount(ctx, &params); err != nil {
+		return SwapInstructions{}, err
+	}
+	instructions, err := PostJSON[SwapInstructions](ctx, c, c.endpoint(EndpointSwapInstructions), c.swapPayload(params))
+	if err != nil {
+		return SwapInstructions{}, fmt.Errorf("failed to get swap instructions: %w", err)
+	}
+	return instructions, nil
+}
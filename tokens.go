@@ -0,0 +1,47 @@
+package jupag
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Token returns the token info of the given mint from the token API.
+func (c *JupagImpl) Token(ctx context.Context, mint string) (TokenInfo, error) {
+	token, err := GetJSON[TokenInfo](ctx, c, fmt.Sprintf("%s/%s", c.endpoint(EndpointToken), url.PathEscape(mint)), nil)
+	if err != nil {
+		return TokenInfo{}, fmt.Errorf("failed to get token: %w", err)
+	}
+	return token, nil
+}
+
+// TaggedTokens returns the tokens having the given tag, e.g. "verified", "lst" or "strict".
+func (c *JupagImpl) TaggedTokens(ctx context.Context, tag string) ([]TokenInfo, error) {
+	tokens, err := GetJSON[[]TokenInfo](ctx, c, fmt.Sprintf("%s/%s", c.endpoint(EndpointTaggedTokens), url.PathEscape(tag)), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tagged tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// SearchTokens searches tokens by symbol, name or mint with the token API, at most 20 results.
+func (c *JupagImpl) SearchTokens(ctx context.Context, query string) ([]TokenSearchResult, error) {
+	tokens, err := GetJSON[[]TokenSearchResult](ctx, c, c.endpoint(EndpointSearchTokens), url.Values{"query": {query}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search tokens: %w", err)
+	}
+	return tokens, nil
+}
+
+// Shield returns the warnings of the Shield API for the given mints, e.g. freeze authority or low liquidity.
+// Mints without warnings are absent from the result.
+func (c *JupagImpl) Shield(ctx context.Context, mints ...string) (map[string][]ShieldWarning, error) {
+	response, err := GetJSON[struct {
+		Warnings map[string][]ShieldWarning `json:"warnings"`
+	}](ctx, c, c.endpoint(EndpointShield), url.Values{"mints": {strings.Join(mints, ",")}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shield warnings: %w", err)
+	}
+	return response.Warnings, nil
+}
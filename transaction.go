@@ -0,0 +1,373 @@
+package jupag
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ipanardian/go-jup-ag/utils"
+)
+
+var errShortTransaction = errors.New("transaction data is too short")
+
+// Transaction is a decoded Solana legacy or versioned transaction.
+type Transaction struct {
+	Signatures [][]byte
+	Message    Message
+}
+
+// MessageHeader describes how many of the account keys are signers and read-only.
+type MessageHeader struct {
+	NumRequiredSignatures       uint8
+	NumReadonlySignedAccounts   uint8
+	NumReadonlyUnsignedAccounts uint8
+}
+
+// Message is the signed part of a transaction.
+type Message struct {
+	Versioned           bool  // false for legacy messages
+	Version             uint8 // only meaningful when Versioned is true
+	Header              MessageHeader
+	AccountKeys         []PublicKey // static account keys
+	RecentBlockhash     PublicKey
+	Instructions        []CompiledInstruction
+	AddressTableLookups []AddressTableLookup
+}
+
+// CompiledInstruction is an instruction referencing accounts by index.
+type CompiledInstruction struct {
+	ProgramIDIndex uint8
+	Accounts       []uint8
+	Data           []byte
+}
+
+// AddressTableLookup references accounts loaded from an address lookup table.
+type AddressTableLookup struct {
+	AccountKey      PublicKey
+	WritableIndexes []uint8
+	ReadonlyIndexes []uint8
+}
+
+// DecodeTransaction decodes a base64 encoded transaction, as returned by Swap.
+func DecodeTransaction(tx string) (*Transaction, error) {
+	data, err := base64.StdEncoding.DecodeString(tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode base64 transaction: %w", err)
+	}
+
+	return UnmarshalTransaction(data)
+}
+
+// UnmarshalTransaction decodes a transaction from its wire format.
+func UnmarshalTransaction(data []byte) (*Transaction, error) {
+	d := &txDecoder{data: data}
+
+	numSigs, err := d.compactU16()
+	if err != nil {
+		return nil, err
+	}
+	tx := &Transaction{Signatures: make([][]byte, numSigs)}
+	for i := range tx.Signatures {
+		sig, err := d.bytes(64)
+		if err != nil {
+			return nil, err
+		}
+		tx.Signatures[i] = sig
+	}
+
+	msg, err := unmarshalMessage(d)
+	if err != nil {
+		return nil, err
+	}
+	tx.Message = *msg
+
+	if d.pos != len(d.data) {
+		return nil, fmt.Errorf("unexpected %d trailing bytes in transaction", len(d.data)-d.pos)
+	}
+
+	return tx, nil
+}
+
+// UnmarshalMessage decodes a message from its wire format.
+func UnmarshalMessage(data []byte) (*Message, error) {
+	return unmarshalMessage(&txDecoder{data: data})
+}
+
+func unmarshalMessage(d *txDecoder) (*Message, error) {
+	msg := &Message{}
+
+	prefix, err := d.byte()
+	if err != nil {
+		return nil, err
+	}
+	if prefix&0x80 != 0 {
+		msg.Versioned = true
+		msg.Version = prefix & 0x7f
+		if msg.Version != 0 {
+			return nil, fmt.Errorf("unsupported transaction version %d", msg.Version)
+		}
+		if msg.Header.NumRequiredSignatures, err = d.byte(); err != nil {
+			return nil, err
+		}
+	} else {
+		msg.Header.NumRequiredSignatures = prefix
+	}
+	if msg.Header.NumReadonlySignedAccounts, err = d.byte(); err != nil {
+		return nil, err
+	}
+	if msg.Header.NumReadonlyUnsignedAccounts, err = d.byte(); err != nil {
+		return nil, err
+	}
+
+	numKeys, err := d.compactU16()
+	if err != nil {
+		return nil, err
+	}
+	msg.AccountKeys = make([]PublicKey, numKeys)
+	for i := range msg.AccountKeys {
+		if msg.AccountKeys[i], err = d.publicKey(); err != nil {
+			return nil, err
+		}
+	}
+	if msg.RecentBlockhash, err = d.publicKey(); err != nil {
+		return nil, err
+	}
+
+	numInstructions, err := d.compactU16()
+	if err != nil {
+		return nil, err
+	}
+	msg.Instructions = make([]CompiledInstruction, numInstructions)
+	for i := range msg.Instructions {
+		ix := &msg.Instructions[i]
+		if ix.ProgramIDIndex, err = d.byte(); err != nil {
+			return nil, err
+		}
+		if ix.Accounts, err = d.compactBytes(); err != nil {
+			return nil, err
+		}
+		if ix.Data, err = d.compactBytes(); err != nil {
+			return nil, err
+		}
+	}
+
+	if !msg.Versioned {
+		return msg, nil
+	}
+
+	numLookups, err := d.compactU16()
+	if err != nil {
+		return nil, err
+	}
+	msg.AddressTableLookups = make([]AddressTableLookup, numLookups)
+	for i := range msg.AddressTableLookups {
+		lookup := &msg.AddressTableLookups[i]
+		if lookup.AccountKey, err = d.publicKey(); err != nil {
+			return nil, err
+		}
+		if lookup.WritableIndexes, err = d.compactBytes(); err != nil {
+			return nil, err
+		}
+		if lookup.ReadonlyIndexes, err = d.compactBytes(); err != nil {
+			return nil, err
+		}
+	}
+
+	return msg, nil
+}
+
+// Marshal encodes the transaction into its wire format.
+func (tx *Transaction) Marshal() []byte {
+	out := appendCompactU16(nil, len(tx.Signatures))
+	for _, sig := range tx.Signatures {
+		out = append(out, sig...)
+	}
+	return append(out, tx.Message.Marshal()...)
+}
+
+// Base64 encodes the transaction into a base64 string, as accepted by sendTransaction.
+func (tx *Transaction) Base64() string {
+	return base64.StdEncoding.EncodeToString(tx.Marshal())
+}
+
+// Signature returns the base58 encoded first signature, which identifies the transaction.
+func (tx *Transaction) Signature() string {
+	if len(tx.Signatures) == 0 {
+		return ""
+	}
+	return utils.Base58Encode(tx.Signatures[0])
+}
+
+// Marshal encodes the message into its wire format, this is the payload that gets signed.
+func (m *Message) Marshal() []byte {
+	var out []byte
+	if m.Versioned {
+		out = append(out, 0x80|m.Version)
+	}
+	out = append(out, m.Header.NumRequiredSignatures, m.Header.NumReadonlySignedAccounts, m.Header.NumReadonlyUnsignedAccounts)
+
+	out = appendCompactU16(out, len(m.AccountKeys))
+	for _, key := range m.AccountKeys {
+		out = append(out, key[:]...)
+	}
+	out = append(out, m.RecentBlockhash[:]...)
+
+	out = appendCompactU16(out, len(m.Instructions))
+	for _, ix := range m.Instructions {
+		out = append(out, ix.ProgramIDIndex)
+		out = appendCompactBytes(out, ix.Accounts)
+		out = appendCompactBytes(out, ix.Data)
+	}
+
+	if !m.Versioned {
+		return out
+	}
+
+	out = appendCompactU16(out, len(m.AddressTableLookups))
+	for _, lookup := range m.AddressTableLookups {
+		out = append(out, lookup.AccountKey[:]...)
+		out = appendCompactBytes(out, lookup.WritableIndexes)
+		out = appendCompactBytes(out, lookup.ReadonlyIndexes)
+	}
+
+	return out
+}
+
+// ProgramID returns the program id invoked by the instruction.
+func (m *Message) ProgramID(ix CompiledInstruction) (PublicKey, error) {
+	if int(ix.ProgramIDIndex) >= len(m.AccountKeys) {
+		return PublicKey{}, fmt.Errorf("program id index %d out of range", ix.ProgramIDIndex)
+	}
+	return m.AccountKeys[ix.ProgramIDIndex], nil
+}
+
+// Programs returns the distinct program ids invoked by the message, in instruction order.
+func (m *Message) Programs() []PublicKey {
+	seen := make(map[PublicKey]bool)
+	programs := make([]PublicKey, 0, len(m.Instructions))
+	for _, ix := range m.Instructions {
+		id, err := m.ProgramID(ix)
+		if err != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		programs = append(programs, id)
+	}
+	return programs
+}
+
+// IsSigner reports whether the static account at index i must sign the message.
+func (m *Message) IsSigner(i int) bool {
+	return i < int(m.Header.NumRequiredSignatures)
+}
+
+// IsWritable reports whether the static account at index i is writable.
+func (m *Message) IsWritable(i int) bool {
+	numSigners := int(m.Header.NumRequiredSignatures)
+	if i < numSigners {
+		return i < numSigners-int(m.Header.NumReadonlySignedAccounts)
+	}
+	return i < len(m.AccountKeys)-int(m.Header.NumReadonlyUnsignedAccounts)
+}
+
+type txDecoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *txDecoder) byte() (byte, error) {
+	if d.pos >= len(d.data) {
+		return 0, errShortTransaction
+	}
+	b := d.data[d.pos]
+	d.pos++
+	return b, nil
+}
+
+func (d *txDecoder) bytes(n int) ([]byte, error) {
+	if d.pos+n > len(d.data) {
+		return nil, errShortTransaction
+	}
+	b := make([]byte, n)
+	copy(b, d.data[d.pos:d.pos+n])
+	d.pos += n
+	return b, nil
+}
+
+func (d *txDecoder) publicKey() (PublicKey, error) {
+	var pk PublicKey
+	if d.pos+32 > len(d.data) {
+		return pk, errShortTransaction
+	}
+	copy(pk[:], d.data[d.pos:d.pos+32])
+	d.pos += 32
+	return pk, nil
+}
+
+func (d *txDecoder) compactU16() (int, error) {
+	var val int
+	for i := 0; i < 3; i++ {
+		b, err := d.byte()
+		if err != nil {
+			return 0, err
+		}
+		val |= int(b&0x7f) << (7 * i)
+		if b&0x80 == 0 {
+			return val, nil
+		}
+	}
+	return 0, errors.New("invalid compact-u16 encoding")
+}
+
+func (d *txDecoder) compactBytes() ([]byte, error) {
+	n, err := d.compactU16()
+	if err != nil {
+		return nil, err
+	}
+	return d.bytes(n)
+}
+
+func appendCompactU16(out []byte, n int) []byte {
+	for {
+		b := byte(n & 0x7f)
+		n >>= 7
+		if n == 0 {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
+
+func appendCompactBytes(out []byte, b []byte) []byte {
+	out = appendCompactU16(out, len(b))
+	return append(out, b...)
+}
+
+// Compute budget program instruction discriminators.
+const (
+	computeBudgetSetComputeUnitLimit = 2
+	computeBudgetSetComputeUnitPrice = 3
+)
+
+// ComputeBudget returns the compute unit limit and price (in micro lamports) set by the message.
+// A zero value means the corresponding instruction is absent.
+func (m *Message) ComputeBudget() (limit uint32, price uint64) {
+	for _, ix := range m.Instructions {
+		id, err := m.ProgramID(ix)
+		if err != nil || id != ComputeBudgetProgramID || len(ix.Data) == 0 {
+			continue
+		}
+		switch ix.Data[0] {
+		case computeBudgetSetComputeUnitLimit:
+			if len(ix.Data) >= 5 {
+				limit = binary.LittleEndian.Uint32(ix.Data[1:5])
+			}
+		case computeBudgetSetComputeUnitPrice:
+			if len(ix.Data) >= 9 {
+				price = binary.LittleEndian.Uint64(ix.Data[1:9])
+			}
+		}
+	}
+	return limit, price
+}
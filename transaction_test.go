@@ -0,0 +1,156 @@
+package jupag
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) PublicKey {
+	var pk PublicKey
+	for i := range pk {
+		pk[i] = b
+	}
+	return pk
+}
+
+func TestTransactionWireFormat(t *testing.T) {
+	tx := &Transaction{
+		Signatures: [][]byte{bytes.Repeat([]byte{0xaa}, 64)},
+		Message: Message{
+			Header:          MessageHeader{NumRequiredSignatures: 1, NumReadonlyUnsignedAccounts: 1},
+			AccountKeys:     []PublicKey{testKey(1), testKey(2)},
+			RecentBlockhash: testKey(3),
+			Instructions:    []CompiledInstruction{{ProgramIDIndex: 1, Accounts: []uint8{0}, Data: []byte{3, 4}}},
+		},
+	}
+
+	var want []byte
+	want = append(want, 1)
+	want = append(want, bytes.Repeat([]byte{0xaa}, 64)...)
+	want = append(want, 1, 0, 1, 2)
+	want = append(want, bytes.Repeat([]byte{1}, 32)...)
+	want = append(want, bytes.Repeat([]byte{2}, 32)...)
+	want = append(want, bytes.Repeat([]byte{3}, 32)...)
+	want = append(want, 1, 1, 1, 0, 2, 3, 4)
+
+	if got := tx.Marshal(); !bytes.Equal(got, want) {
+		t.Fatalf("Marshal() = %x, want %x", got, want)
+	}
+	decoded, err := DecodeTransaction(tx.Base64())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, tx) {
+		t.Errorf("decoded = %+v, want %+v", decoded, tx)
+	}
+}
+
+func TestTransactionRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		tx   Transaction
+	}{
+		{
+			name: "legacy without signatures",
+			tx: Transaction{
+				Signatures: [][]byte{},
+				Message: Message{
+					Header:          MessageHeader{NumRequiredSignatures: 1},
+					AccountKeys:     []PublicKey{testKey(9)},
+					RecentBlockhash: testKey(8),
+					Instructions:    []CompiledInstruction{},
+				},
+			},
+		},
+		{
+			name: "v0 with lookups",
+			tx: Transaction{
+				Signatures: [][]byte{bytes.Repeat([]byte{1}, 64), bytes.Repeat([]byte{2}, 64)},
+				Message: Message{
+					Versioned:       true,
+					Header:          MessageHeader{NumRequiredSignatures: 2, NumReadonlySignedAccounts: 1, NumReadonlyUnsignedAccounts: 1},
+					AccountKeys:     []PublicKey{testKey(1), testKey(2), ComputeBudgetProgramID},
+					RecentBlockhash: testKey(4),
+					Instructions: []CompiledInstruction{
+						{ProgramIDIndex: 2, Accounts: []uint8{}, Data: []byte{3, 1, 0, 0, 0, 0, 0, 0, 0}},
+						{ProgramIDIndex: 2, Accounts: []uint8{0, 3, 4}, Data: bytes.Repeat([]byte{7}, 200)},
+					},
+					AddressTableLookups: []AddressTableLookup{
+						{AccountKey: testKey(5), WritableIndexes: []uint8{0}, ReadonlyIndexes: []uint8{1, 2}},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoded, err := UnmarshalTransaction(tt.tx.Marshal())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(*decoded, tt.tx) {
+				t.Errorf("decoded = %+v, want %+v", *decoded, tt.tx)
+			}
+			if !bytes.Equal(decoded.Marshal(), tt.tx.Marshal()) {
+				t.Error("re-encoded transaction differs")
+			}
+		})
+	}
+}
+
+func TestCompactU16(t *testing.T) {
+	tests := []struct {
+		n    int
+		want []byte
+	}{
+		{0, []byte{0}},
+		{127, []byte{0x7f}},
+		{128, []byte{0x80, 0x01}},
+		{200, []byte{0xc8, 0x01}},
+		{16383, []byte{0xff, 0x7f}},
+		{16384, []byte{0x80, 0x80, 0x01}},
+		{65535, []byte{0xff, 0xff, 0x03}},
+	}
+	for _, tt := range tests {
+		got := appendCompactU16(nil, tt.n)
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("appendCompactU16(%d) = %x, want %x", tt.n, got, tt.want)
+		}
+		n, err := (&txDecoder{data: got}).compactU16()
+		if err != nil || n != tt.n {
+			t.Errorf("compactU16(%x) = %d, %v, want %d", got, n, err, tt.n)
+		}
+	}
+	if _, err := (&txDecoder{data: []byte{0x80, 0x80, 0x80}}).compactU16(); err == nil {
+		t.Error("compactU16 of 4 bytes: want an error")
+	}
+}
+
+func TestUnmarshalTransactionErrors(t *testing.T) {
+	valid := (&Transaction{
+		Signatures: [][]byte{bytes.Repeat([]byte{1}, 64)},
+		Message:    Message{Header: MessageHeader{NumRequiredSignatures: 1}, AccountKeys: []PublicKey{testKey(1)}},
+	}).Marshal()
+	v1 := append([]byte{0}, 0x81, 1, 0, 0)
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"empty", nil, "too short"},
+		{"truncated", valid[:len(valid)-1], "too short"},
+		{"trailing bytes", append(append([]byte(nil), valid...), 0), "trailing"},
+		{"unsupported version", v1, "version 1"},
+	}
+	for _, tt := range tests {
+		if _, err := UnmarshalTransaction(tt.data); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.want)
+		}
+	}
+	if _, err := DecodeTransaction("not base64!"); err == nil {
+		t.Error("DecodeTransaction of invalid base64: want an error")
+	}
+}
@@ -0,0 +1,174 @@
+package jupag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var (
+	ErrTransferHook       = errors.New("token has a transfer hook")
+	ErrTransferFeeTooHigh = errors.New("token transfer fee is too high")
+)
+
+// TransferFee is a Token-2022 transfer fee, withheld from the received amount of each transfer.
+type TransferFee struct {
+	Epoch      uint64 `json:"epoch"` // first epoch of the fee
+	Bps        uint16 `json:"transferFeeBasisPoints"`
+	MaximumFee Amount `json:"maximumFee"` // raw amount
+}
+
+// Fee returns the fee withheld on a transfer of amount, rounded up as the token program does.
+func (f TransferFee) Fee(amount Amount) Amount {
+	if f.Bps == 0 || amount.IsZero() {
+		return Amount{}
+	}
+	fee := new(big.Int).Mul(amount.BigInt(), big.NewInt(int64(f.Bps)))
+	fee.Add(fee, big.NewInt(9999))
+	fee.Quo(fee, big.NewInt(10000))
+	if maximum := f.MaximumFee.BigInt(); fee.Cmp(maximum) > 0 {
+		fee = maximum
+	}
+	return NewAmountFromBig(fee)
+}
+
+// TokenExtensions are the Token-2022 extensions of a mint affecting swaps, zero for Token program mints.
+type TokenExtensions struct {
+	Mint         string
+	Program      PublicKey
+	TransferFee  *TransferFee // fee of the current epoch
+	TransferHook *PublicKey   // program invoked on each transfer
+}
+
+// TokenExtensions returns the transfer fee and hook of a mint from its account on the RPC node.
+func (c *JupagImpl) TokenExtensions(ctx context.Context, mint string) (TokenExtensions, error) {
+	program, err := c.TokenProgram(ctx, mint)
+	if err != nil {
+		return TokenExtensions{}, err
+	}
+	ext := TokenExtensions{Mint: mint, Program: program}
+	if program != Token2022ProgramID {
+		return ext, nil
+	}
+
+	var info struct {
+		Value *struct {
+			Data struct {
+				Parsed struct {
+					Info struct {
+						Extensions []struct {
+							Extension string `json:"extension"`
+							State     struct {
+								NewerTransferFee *TransferFee `json:"newerTransferFee"`
+								OlderTransferFee *TransferFee `json:"olderTransferFee"`
+								ProgramID        *PublicKey   `json:"programId"`
+							} `json:"state"`
+						} `json:"extensions"`
+					} `json:"info"`
+				} `json:"parsed"`
+			} `json:"data"`
+		} `json:"value"`
+	}
+	if err := c.rpc.Call(ctx, "getAccountInfo", []any{mint, map[string]any{"encoding": "jsonParsed"}}, &info); err != nil {
+		return TokenExtensions{}, fmt.Errorf("failed to get mint account: %w", err)
+	}
+	if info.Value == nil {
+		return TokenExtensions{}, fmt.Errorf("mint account %s not found", mint)
+	}
+
+	for _, e := range info.Value.Data.Parsed.Info.Extensions {
+		switch e.Extension {
+		case "transferFeeConfig":
+			fee := e.State.NewerTransferFee
+			if fee != nil && e.State.OlderTransferFee != nil {
+				epoch, err := c.epoch(ctx)
+				if err != nil {
+					return TokenExtensions{}, err
+				}
+				if epoch < fee.Epoch {
+					fee = e.State.OlderTransferFee
+				}
+			}
+			if fee != nil && fee.Bps > 0 {
+				ext.TransferFee = fee
+			}
+		case "transferHook":
+			if e.State.ProgramID != nil && !e.State.ProgramID.IsZero() {
+				ext.TransferHook = e.State.ProgramID
+			}
+		}
+	}
+	return ext, nil
+}
+
+func (c *JupagImpl) epoch(ctx context.Context) (uint64, error) {
+	var info struct {
+		Epoch uint64 `json:"epoch"`
+	}
+	if err := c.rpc.Call(ctx, "getEpochInfo", nil, &info); err != nil {
+		return 0, fmt.Errorf("failed to get epoch: %w", err)
+	}
+	return info.Epoch, nil
+}
+
+// ReceivedAfterTransferFee returns the out amount of the route minus the transfer fee of the output mint,
+// i.e. the amount expected to land in the wallet.
+func (c *JupagImpl) ReceivedAfterTransferFee(ctx context.Context, route Route) (Amount, error) {
+	if len(route.MarketInfos) == 0 {
+		return route.OutAmount, nil
+	}
+	ext, err := c.TokenExtensions(ctx, route.MarketInfos[len(route.MarketInfos)-1].OutputMint)
+	if err != nil {
+		return Amount{}, err
+	}
+	if ext.TransferFee == nil {
+		return route.OutAmount, nil
+	}
+	return route.OutAmount.Sub(ext.TransferFee.Fee(route.OutAmount)), nil
+}
+
+// transferFeeGuard rejects swaps into tokens with a transfer hook or a transfer fee above maxBps.
+type transferFeeGuard struct {
+	maxBps     uint16
+	allowHooks bool
+}
+
+// checkTransferFee returns ErrTransferHook or ErrTransferFeeTooHigh when the output mint of the route is rejected
+// by the guard. Without RPC client, the Shield warnings of the mint are used instead.
+func (c *JupagImpl) checkTransferFee(ctx context.Context, route Route) error {
+	if c.transferFeeGuard == nil || len(route.MarketInfos) == 0 {
+		return nil
+	}
+	mint := route.MarketInfos[len(route.MarketInfos)-1].OutputMint
+
+	if c.rpc == nil {
+		warnings, err := c.Shield(ctx, mint)
+		if err != nil {
+			return fmt.Errorf("failed to check transfer fee of %s: %w", mint, err)
+		}
+		for _, w := range warnings[mint] {
+			switch w.Type {
+			case "HAS_TRANSFER_HOOK":
+				if !c.transferFeeGuard.allowHooks {
+					return fmt.Errorf("%w: %s", ErrTransferHook, mint)
+				}
+			case "HAS_TRANSFER_FEE", "HIGH_TRANSFER_FEE":
+				return fmt.Errorf("%w: %s: %s", ErrTransferFeeTooHigh, mint, w.Message)
+			}
+		}
+		return nil
+	}
+
+	ext, err := c.TokenExtensions(ctx, mint)
+	if err != nil {
+		return fmt.Errorf("failed to check transfer fee of %s: %w", mint, err)
+	}
+	if ext.TransferHook != nil && !c.transferFeeGuard.allowHooks {
+		return fmt.Errorf("%w: %s invokes %s", ErrTransferHook, mint, ext.TransferHook)
+	}
+	if ext.TransferFee != nil && ext.TransferFee.Bps > c.transferFeeGuard.maxBps {
+		return fmt.Errorf("%w: %s charges %d bps, max %d", ErrTransferFeeTooHigh, mint, ext.TransferFee.Bps, c.transferFeeGuard.maxBps)
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+package jupag
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OrderState selects the trigger or recurring orders of a request.
+type OrderState string
+
+const (
+	OrdersActive  OrderState = "active"  // open orders
+	OrdersHistory OrderState = "history" // closed orders, most recent first
+)
+
+// TriggerOrderStatus is the status of a trigger order.
+type TriggerOrderStatus string
+
+const (
+	TriggerOrderOpen      TriggerOrderStatus = "Open"
+	TriggerOrderCompleted TriggerOrderStatus = "Completed"
+	TriggerOrderCancelled TriggerOrderStatus = "Cancelled"
+)
+
+// TriggerOrdersParams are the parameters of a trigger orders request.
+type TriggerOrdersParams struct {
+	User        string     `url:"user"`                 // required; wallet of the orders
+	OrderStatus OrderState `url:"orderStatus"`          // required; OrdersActive or OrdersHistory
+	Page        int        `url:"page,omitempty"`       // optional; Default to 1.
+	InputMint   string     `url:"inputMint,omitempty"`  // optional; Only the orders selling this mint.
+	OutputMint  string     `url:"outputMint,omitempty"` // optional; Only the orders buying this mint.
+}
+
+// TriggerOrdersPage is a page of trigger orders.
+type TriggerOrdersPage struct {
+	Orders     []TriggerOrder `json:"orders"`
+	Page       int            `json:"page"`
+	TotalPages int            `json:"totalPages"`
+}
+
+// TriggerOrder is a limit order of the trigger API, selling MakingAmount of InputMint for TakingAmount of OutputMint.
+type TriggerOrder struct {
+	OrderKey              string             `json:"orderKey"`
+	UserPubkey            string             `json:"userPubkey"`
+	InputMint             string             `json:"inputMint"`
+	OutputMint            string             `json:"outputMint"`
+	MakingAmount          Amount             `json:"rawMakingAmount"`
+	TakingAmount          Amount             `json:"rawTakingAmount"`
+	RemainingMakingAmount Amount             `json:"rawRemainingMakingAmount"` // making amount not filled yet
+	RemainingTakingAmount Amount             `json:"rawRemainingTakingAmount"`
+	SlippageBps           Int64              `json:"slippageBps"`
+	ExpiredAt             *time.Time         `json:"expiredAt"` // nil when the order doesn't expire
+	CreatedAt             time.Time          `json:"createdAt"`
+	UpdatedAt             time.Time          `json:"updatedAt"`
+	Status                TriggerOrderStatus `json:"status"`
+	OpenTx                string             `json:"openTx"`
+	CloseTx               string             `json:"closeTx"`
+}
+
+// TriggerOrders returns a page of the trigger orders of a wallet.
+func (c *JupagImpl) TriggerOrders(ctx context.Context, params TriggerOrdersParams) (TriggerOrdersPage, error) {
+	if _, err := ParsePublicKey(params.User); err != nil {
+		return TriggerOrdersPage{}, fmt.Errorf("%w: invalid user: %w", ErrInvalidParams, err)
+	}
+	page, err := GetJSON[TriggerOrdersPage](ctx, c, c.endpoint(EndpointTriggerOrders), params)
+	if err != nil {
+		return TriggerOrdersPage{}, fmt.Errorf("failed to get trigger orders: %w", err)
+	}
+	return page, nil
+}
+
+// openTriggerOrders returns the open trigger orders of a wallet, of all the pages.
+func (c *JupagImpl) openTriggerOrders(ctx context.Context, wallet string) ([]TriggerOrder, error) {
+	var orders []TriggerOrder
+	for page := 1; ; page++ {
+		p, err := c.TriggerOrders(ctx, TriggerOrdersParams{User: wallet, OrderStatus: OrdersActive, Page: page})
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, p.Orders...)
+		if page >= p.TotalPages || len(p.Orders) == 0 {
+			return orders, nil
+		}
+	}
+}
+
+// TriggerOrderEventType is the type of a trigger order event.
+type TriggerOrderEventType string
+
+const (
+	TriggerEventCreated         TriggerOrderEventType = "created"
+	TriggerEventPartiallyFilled TriggerOrderEventType = "partially_filled"
+	TriggerEventFilled          TriggerOrderEventType = "filled"
+	TriggerEventCancelled       TriggerOrderEventType = "cancelled"
+	TriggerEventExpired         TriggerOrderEventType = "expired"
+)
+
+// TriggerOrderEvent is an event of WatchTriggerOrders. Either Err is set, or Type and Order.
+type TriggerOrderEvent struct {
+	Time   time.Time
+	Type   TriggerOrderEventType
+	Order  TriggerOrder
+	Filled Amount // making amount filled since the previous event of the order, for the fill events
+	Err    error
+}
+
+// WatchTriggerOrders polls the trigger orders of a wallet every interval and sends their changes on the returned
+// channel, as Jupiter has no push API for the order states. The orders open at the first poll are the baseline,
+// only the orders created afterwards are reported as created. Closed orders are looked up in the order history
+// to report them as filled, cancelled or expired. The channel is closed when ctx is done. The interval defaults
+// to 30s when not positive.
+func (c *JupagImpl) WatchTriggerOrders(ctx context.Context, wallet string, interval time.Duration) <-chan TriggerOrderEvent {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	events := make(chan TriggerOrderEvent, 16)
+
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		w := triggerOrderWatcher{c: c, wallet: wallet, orders: make(map[string]TriggerOrder)}
+		for {
+			changes, err := w.poll(ctx)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				changes = append(changes, TriggerOrderEvent{Time: time.Now(), Err: err})
+			}
+			for _, event := range changes {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return events
+}
+
+type triggerOrderWatcher struct {
+	c      *JupagImpl
+	wallet string
+	orders map[string]TriggerOrder // last state of the open orders, by order key
+	seeded bool
+}
+
+// poll compares the open orders to the previous poll, closed orders stay tracked until found in the history.
+func (w *triggerOrderWatcher) poll(ctx context.Context) ([]TriggerOrderEvent, error) {
+	open, err := w.c.openTriggerOrders(ctx, w.wallet)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var events []TriggerOrderEvent
+	seen := make(map[string]bool, len(open))
+	for _, order := range open {
+		seen[order.OrderKey] = true
+		prev, ok := w.orders[order.OrderKey]
+		switch {
+		case !ok && w.seeded:
+			events = append(events, TriggerOrderEvent{Time: now, Type: TriggerEventCreated, Order: order})
+		case ok && order.RemainingMakingAmount.Cmp(prev.RemainingMakingAmount) < 0:
+			events = append(events, TriggerOrderEvent{
+				Time:   now,
+				Type:   TriggerEventPartiallyFilled,
+				Order:  order,
+				Filled: prev.RemainingMakingAmount.Sub(order.RemainingMakingAmount),
+			})
+		}
+		w.orders[order.OrderKey] = order
+	}
+	w.seeded = true
+
+	var closed []TriggerOrder
+	for key, order := range w.orders {
+		if !seen[key] {
+			closed = append(closed, order)
+		}
+	}
+	if len(closed) == 0 {
+		return events, nil
+	}
+	sort.Slice(closed, func(i, j int) bool { return closed[i].OrderKey < closed[j].OrderKey })
+
+	history, err := w.c.TriggerOrders(ctx, TriggerOrdersParams{User: w.wallet, OrderStatus: OrdersHistory})
+	if err != nil {
+		return events, err
+	}
+	final := make(map[string]TriggerOrder, len(history.Orders))
+	for _, order := range history.Orders {
+		final[order.OrderKey] = order
+	}
+	for _, prev := range closed {
+		order, ok := final[prev.OrderKey]
+		if !ok {
+			if prev.ExpiredAt == nil || now.Before(*prev.ExpiredAt) {
+				continue // not in the history yet
+			}
+			order = prev
+		}
+		events = append(events, closedOrderEvent(now, prev, order))
+		delete(w.orders, prev.OrderKey)
+	}
+	return events, nil
+}
+
+// closedOrderEvent returns the event of an order no longer open, given its previous and final states.
+func closedOrderEvent(now time.Time, prev, order TriggerOrder) TriggerOrderEvent {
+	event := TriggerOrderEvent{Time: now, Order: order}
+	switch {
+	case strings.EqualFold(string(order.Status), string(TriggerOrderCompleted)):
+		event.Type = TriggerEventFilled
+		event.Filled = prev.RemainingMakingAmount
+	case order.ExpiredAt != nil && !order.UpdatedAt.Before(*order.ExpiredAt):
+		event.Type = TriggerEventExpired
+	case strings.EqualFold(string(order.Status), string(TriggerOrderCancelled)):
+		event.Type = TriggerEventCancelled
+	default:
+		event.Type = TriggerEventExpired
+	}
+	return event
+}
@@ -0,0 +1,150 @@
+package jupag
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TransactionDiff reports the differences between two swap transactions.
+type TransactionDiff struct {
+	VersionChanged      bool
+	BlockhashChanged    bool
+	SignersChanged      bool
+	AccountsAdded       []string // static accounts only present in the second transaction
+	AccountsRemoved     []string // static accounts only present in the first transaction
+	LookupTablesAdded   []string
+	LookupTablesRemoved []string
+	ProgramsAdded       []string
+	ProgramsRemoved     []string
+	ComputeUnitLimit    [2]uint32 // before, after
+	ComputeUnitPrice    [2]uint64 // before, after, in micro lamports
+	InstructionCount    [2]int    // before, after
+}
+
+// DiffTransactions decodes two base64 encoded swap transactions (e.g. before and after a re-quote)
+// and reports the differences in accounts, compute budget and route programs.
+func DiffTransactions(before, after string) (TransactionDiff, error) {
+	a, err := DecodeTransaction(before)
+	if err != nil {
+		return TransactionDiff{}, fmt.Errorf("failed to decode first transaction: %w", err)
+	}
+	b, err := DecodeTransaction(after)
+	if err != nil {
+		return TransactionDiff{}, fmt.Errorf("failed to decode second transaction: %w", err)
+	}
+
+	return DiffMessages(&a.Message, &b.Message), nil
+}
+
+// DiffMessages reports the differences between two decoded messages.
+func DiffMessages(a, b *Message) TransactionDiff {
+	diff := TransactionDiff{
+		VersionChanged:   a.Versioned != b.Versioned || a.Version != b.Version,
+		BlockhashChanged: a.RecentBlockhash != b.RecentBlockhash,
+		InstructionCount: [2]int{len(a.Instructions), len(b.Instructions)},
+	}
+
+	diff.SignersChanged = a.Header.NumRequiredSignatures != b.Header.NumRequiredSignatures
+	for i := 0; !diff.SignersChanged && i < int(a.Header.NumRequiredSignatures) && i < len(a.AccountKeys) && i < len(b.AccountKeys); i++ {
+		diff.SignersChanged = a.AccountKeys[i] != b.AccountKeys[i]
+	}
+
+	diff.AccountsAdded, diff.AccountsRemoved = diffKeys(a.AccountKeys, b.AccountKeys)
+	diff.LookupTablesAdded, diff.LookupTablesRemoved = diffKeys(lookupTableKeys(a), lookupTableKeys(b))
+	diff.ProgramsAdded, diff.ProgramsRemoved = diffKeys(a.Programs(), b.Programs())
+
+	diff.ComputeUnitLimit[0], diff.ComputeUnitPrice[0] = a.ComputeBudget()
+	diff.ComputeUnitLimit[1], diff.ComputeUnitPrice[1] = b.ComputeBudget()
+
+	return diff
+}
+
+// Equal reports whether no difference was found, ignoring the blockhash.
+func (d TransactionDiff) Equal() bool {
+	return !d.VersionChanged && !d.SignersChanged &&
+		len(d.AccountsAdded) == 0 && len(d.AccountsRemoved) == 0 &&
+		len(d.LookupTablesAdded) == 0 && len(d.LookupTablesRemoved) == 0 &&
+		len(d.ProgramsAdded) == 0 && len(d.ProgramsRemoved) == 0 &&
+		d.ComputeUnitLimit[0] == d.ComputeUnitLimit[1] &&
+		d.ComputeUnitPrice[0] == d.ComputeUnitPrice[1] &&
+		d.InstructionCount[0] == d.InstructionCount[1]
+}
+
+// String returns a human readable summary of the differences.
+func (d TransactionDiff) String() string {
+	var sb strings.Builder
+	line := func(format string, args ...any) {
+		fmt.Fprintf(&sb, format+"\n", args...)
+	}
+
+	if d.VersionChanged {
+		line("version changed")
+	}
+	if d.BlockhashChanged {
+		line("recent blockhash changed")
+	}
+	if d.SignersChanged {
+		line("signers changed")
+	}
+	if d.InstructionCount[0] != d.InstructionCount[1] {
+		line("instructions: %d -> %d", d.InstructionCount[0], d.InstructionCount[1])
+	}
+	if d.ComputeUnitLimit[0] != d.ComputeUnitLimit[1] {
+		line("compute unit limit: %d -> %d", d.ComputeUnitLimit[0], d.ComputeUnitLimit[1])
+	}
+	if d.ComputeUnitPrice[0] != d.ComputeUnitPrice[1] {
+		line("compute unit price: %d -> %d micro lamports", d.ComputeUnitPrice[0], d.ComputeUnitPrice[1])
+	}
+	for _, p := range d.ProgramsAdded {
+		line("+ program %s", p)
+	}
+	for _, p := range d.ProgramsRemoved {
+		line("- program %s", p)
+	}
+	for _, t := range d.LookupTablesAdded {
+		line("+ lookup table %s", t)
+	}
+	for _, t := range d.LookupTablesRemoved {
+		line("- lookup table %s", t)
+	}
+	for _, a := range d.AccountsAdded {
+		line("+ account %s", a)
+	}
+	for _, a := range d.AccountsRemoved {
+		line("- account %s", a)
+	}
+
+	if sb.Len() == 0 {
+		return "no differences"
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func lookupTableKeys(m *Message) []PublicKey {
+	keys := make([]PublicKey, 0, len(m.AddressTableLookups))
+	for _, lookup := range m.AddressTableLookups {
+		keys = append(keys, lookup.AccountKey)
+	}
+	return keys
+}
+
+// diffKeys returns the labels of keys only in b (added) and only in a (removed).
+func diffKeys(a, b []PublicKey) (added, removed []string) {
+	inA := make(map[PublicKey]bool, len(a))
+	for _, k := range a {
+		inA[k] = true
+	}
+	inB := make(map[PublicKey]bool, len(b))
+	for _, k := range b {
+		inB[k] = true
+		if !inA[k] {
+			added = append(added, k.Label())
+		}
+	}
+	for _, k := range a {
+		if !inB[k] {
+			removed = append(removed, k.Label())
+		}
+	}
+	return added, removed
+}
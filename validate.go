@@ -0,0 +1,125 @@
+package jupag
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidParams is the error of the params rejected by their Validate method, before any request.
+var ErrInvalidParams = errors.New("invalid params")
+
+// maxBps is the maximum of the basis points fields, 100%.
+const maxBps = 10000
+
+// MaxBlockhashSlotsToExpiry is the lifetime of a blockhash in slots, the maximum of
+// SwapParams.BlockhashSlotsToExpiry.
+const MaxBlockhashSlotsToExpiry = 150
+
+// Validate checks the required fields, the mint and public key formats and the options of the quote params,
+// all the validation errors are joined.
+func (p QuoteParams) Validate() error {
+	var errs []error
+	errs = appendKeyError(errs, "input mint", p.InputMint, true)
+	errs = appendKeyError(errs, "output mint", p.OutputMint, true)
+	if p.InputMint != "" && p.InputMint == p.OutputMint {
+		errs = append(errs, errors.New("input and output mints are the same"))
+	}
+	if p.Amount == 0 {
+		errs = append(errs, errors.New("amount is required"))
+	}
+	if p.SwapMode != "" && p.SwapMode != SwapModeExactIn && p.SwapMode != SwapModeExactOut {
+		errs = append(errs, fmt.Errorf("invalid swap mode %q, expected %s or %s", p.SwapMode, SwapModeExactIn, SwapModeExactOut))
+	}
+	if p.SlippageBps > maxBps {
+		errs = append(errs, fmt.Errorf("slippage of %d bps exceeds %d bps", p.SlippageBps, maxBps))
+	}
+	if p.FeeBps > maxBps {
+		errs = append(errs, fmt.Errorf("fee of %d bps exceeds %d bps", p.FeeBps, maxBps))
+	}
+	errs = appendKeyError(errs, "user public key", p.UserPublicKey, false)
+	return invalidParams(errs)
+}
+
+// Validate checks the route, the public keys and the options of the swap params, all the validation errors
+// are joined.
+func (p SwapParams) Validate() error {
+	var errs []error
+	if len(p.Route.MarketInfos) == 0 && p.Route.raw == nil {
+		errs = append(errs, errors.New("route is required"))
+	} else if p.Route.InAmount.IsZero() && p.Route.raw == nil {
+		errs = append(errs, errors.New("route has no input amount"))
+	}
+	errs = appendKeyError(errs, "user public key", p.UserPublicKey, true)
+	errs = appendKeyError(errs, "fee account", p.FeeAccount, false)
+	errs = appendKeyError(errs, "destination wallet", p.DestinationWallet, false)
+	errs = appendKeyError(errs, "destination token account", p.DestinationTokenAccount, false)
+	errs = appendKeyError(errs, "tracking account", p.TrackingAccount, false)
+	if p.BlockhashSlotsToExpiry > MaxBlockhashSlotsToExpiry {
+		errs = append(errs, fmt.Errorf("blockhash slots to expiry %d exceed %d", p.BlockhashSlotsToExpiry, MaxBlockhashSlotsToExpiry))
+	}
+	if d := p.DynamicSlippage; d != nil {
+		if d.MaxBps > maxBps {
+			errs = append(errs, fmt.Errorf("dynamic slippage maximum of %d bps exceeds %d bps", d.MaxBps, maxBps))
+		}
+		if d.MaxBps > 0 && d.MinBps > d.MaxBps {
+			errs = append(errs, fmt.Errorf("dynamic slippage minimum of %d bps exceeds the maximum of %d bps", d.MinBps, d.MaxBps))
+		}
+		if p.Route.SwapMode == SwapModeExactOut {
+			errs = append(errs, errors.New("dynamic slippage is not supported with ExactOut routes"))
+		}
+	}
+	if p.DestinationWallet != "" && p.DestinationTokenAccount != "" {
+		errs = append(errs, errors.New("destination wallet and destination token account are mutually exclusive"))
+	}
+	if err := p.ComputeUnitPriceMicroLamports.validate(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid compute unit price: %w", err))
+	}
+	if err := p.PrioritizationFeeLamports.validate(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid prioritization fee: %w", err))
+	}
+	if p.ComputeUnitPriceMicroLamports.IsSet() && p.PrioritizationFeeLamports.IsSet() {
+		errs = append(errs, errors.New("compute unit price and prioritization fee are mutually exclusive"))
+	}
+	return invalidParams(errs)
+}
+
+// Validate checks the ids and the amount of the price params. Ids may be symbols, their format isn't checked.
+func (p PriceParams) Validate() error {
+	var errs []error
+	if strings.TrimSpace(p.IDs) == "" {
+		errs = append(errs, errors.New("ids are required"))
+	} else {
+		for _, id := range strings.Split(p.IDs, ",") {
+			if strings.TrimSpace(id) == "" {
+				errs = append(errs, fmt.Errorf("empty id in %q", p.IDs))
+				break
+			}
+		}
+	}
+	if p.VsAmount < 0 {
+		errs = append(errs, fmt.Errorf("negative vs amount %v", p.VsAmount))
+	}
+	return invalidParams(errs)
+}
+
+// appendKeyError appends the error of an invalid public key field, or of a missing one when required.
+func appendKeyError(errs []error, name, key string, required bool) []error {
+	if key == "" {
+		if required {
+			errs = append(errs, fmt.Errorf("%s is required", name))
+		}
+		return errs
+	}
+	if _, err := ParsePublicKey(key); err != nil {
+		errs = append(errs, fmt.Errorf("invalid %s: %w", name, err))
+	}
+	return errs
+}
+
+func invalidParams(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %w", ErrInvalidParams, errors.Join(errs...))
+}
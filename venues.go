@@ -0,0 +1,110 @@
+package jupag
+
+import "sort"
+
+// LegSplit is a leg of a route with the share of its hop input it swaps.
+type LegSplit struct {
+	Label      string // DEX of the market
+	InputMint  string
+	OutputMint string
+	Hop        int     // index of the hop, legs splitting the same input share it
+	Pct        float64 // percentage of the hop input swapped by the leg
+}
+
+// Splits returns the legs of the route grouped into hops by input mint, with the percentage split of each hop.
+func (r Route) Splits() []LegSplit {
+	hops := make(map[string]int)
+	totals := make(map[string]float64)
+	for _, m := range r.MarketInfos {
+		if _, ok := hops[m.InputMint]; !ok {
+			hops[m.InputMint] = len(hops)
+		}
+		totals[m.InputMint] += m.InAmount.Float64()
+	}
+
+	splits := make([]LegSplit, len(r.MarketInfos))
+	for i, m := range r.MarketInfos {
+		splits[i] = LegSplit{Label: m.Label, InputMint: m.InputMint, OutputMint: m.OutputMint, Hop: hops[m.InputMint], Pct: 100}
+		if total := totals[m.InputMint]; total > 0 {
+			splits[i].Pct = m.InAmount.Float64() / total * 100
+		}
+	}
+	return splits
+}
+
+// Hops returns the number of sequential swaps of the route, legs splitting the same input count once.
+func (r Route) Hops() int {
+	hops := make(map[string]bool)
+	for _, m := range r.MarketInfos {
+		hops[m.InputMint] = true
+	}
+	return len(hops)
+}
+
+// VenueUsage is the usage of a DEX in a VenueReport.
+type VenueUsage struct {
+	Label    string  `json:"label"`
+	Routes   int     `json:"routes"`   // routes using the venue
+	Legs     int     `json:"legs"`     // legs on the venue
+	SharePct float64 `json:"sharePct"` // share of the routed volume, each hop of a route weighting the same
+}
+
+// VenueReport summarizes the DEXes and hop depth of the best routes of quotes, e.g. to monitor routing concentration.
+type VenueReport struct {
+	Routes  int          `json:"routes"`
+	Venues  []VenueUsage `json:"venues"`  // sorted by share, highest first
+	HopHist map[int]int  `json:"hopHist"` // number of routes by hop count
+	AvgHops float64      `json:"avgHops"`
+	// HHI is the Herfindahl-Hirschman index of the venue shares, from 0 to 10000 when a single venue routes everything.
+	HHI float64 `json:"hhi"`
+}
+
+// NewVenueReport analyzes the best route of each quote, quotes without route are skipped.
+func NewVenueReport(quotes ...QuoteResponse) VenueReport {
+	report := VenueReport{HopHist: make(map[int]int)}
+	venues := make(map[string]*VenueUsage)
+	var hops int
+
+	for _, q := range quotes {
+		route, err := q.GetBestRoute()
+		if err != nil || len(route.MarketInfos) == 0 {
+			continue
+		}
+		report.Routes++
+		n := route.Hops()
+		hops += n
+		report.HopHist[n]++
+
+		used := make(map[string]bool)
+		for _, leg := range route.Splits() {
+			v, ok := venues[leg.Label]
+			if !ok {
+				v = &VenueUsage{Label: leg.Label}
+				venues[leg.Label] = v
+			}
+			v.Legs++
+			v.SharePct += leg.Pct / float64(n)
+			if !used[leg.Label] {
+				used[leg.Label] = true
+				v.Routes++
+			}
+		}
+	}
+	if report.Routes == 0 {
+		return report
+	}
+
+	report.AvgHops = float64(hops) / float64(report.Routes)
+	for _, v := range venues {
+		v.SharePct /= float64(report.Routes)
+		report.HHI += v.SharePct * v.SharePct
+		report.Venues = append(report.Venues, *v)
+	}
+	sort.Slice(report.Venues, func(i, j int) bool {
+		if report.Venues[i].SharePct != report.Venues[j].SharePct {
+			return report.Venues[i].SharePct > report.Venues[j].SharePct
+		}
+		return report.Venues[i].Label < report.Venues[j].Label
+	})
+	return report
+}
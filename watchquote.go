@@ -0,0 +1,120 @@
+package jupag
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// QuoteThresholds configures the alerts of WatchQuote, zero values are ignored.
+type QuoteThresholds struct {
+	OutAmountAbove   uint64  // alert when the best out amount rises above this value
+	OutAmountBelow   uint64  // alert when the best out amount falls below this value
+	PriceImpactPct   float64 // alert when the price impact rises above this percentage, e.g. 1 for 1%
+	OutAmountMoveBps float64 // alert when the out amount moved by at least this many bps since the last alert
+}
+
+// QuoteAlert identifies a threshold crossed by a watched quote.
+type QuoteAlert string
+
+const (
+	QuoteAlertOutAmountAbove QuoteAlert = "out_amount_above"
+	QuoteAlertOutAmountBelow QuoteAlert = "out_amount_below"
+	QuoteAlertPriceImpact    QuoteAlert = "price_impact"
+	QuoteAlertOutAmountMove  QuoteAlert = "out_amount_move"
+)
+
+// QuoteUpdate is an event of WatchQuote. Either Err is set, or Route holds the refreshed best route.
+type QuoteUpdate struct {
+	Time   time.Time
+	Quotes QuoteResponse
+	Route  Route
+	Alerts []QuoteAlert // thresholds crossed since the previous update
+	Err    error
+}
+
+// WatchQuote refreshes the quote every interval and sends the best route on the returned channel,
+// flagging the thresholds crossed. Alerts fire on crossing only, not while the value stays beyond the threshold.
+// The channel is closed when ctx is done. The interval defaults to 10s when not positive.
+func (c *JupagImpl) WatchQuote(ctx context.Context, params QuoteParams, interval time.Duration, thresholds QuoteThresholds) <-chan QuoteUpdate {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	updates := make(chan QuoteUpdate, 1)
+
+	go func() {
+		defer close(updates)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		w := quoteWatcher{thresholds: thresholds}
+		for {
+			update := QuoteUpdate{Time: time.Now()}
+			update.Quotes, update.Err = c.quote(ctx, params)
+			if update.Err == nil {
+				update.Route, update.Err = update.Quotes.GetBestRoute()
+			}
+			if update.Err == nil {
+				update.Alerts = w.check(update.Route)
+			}
+			if update.Err != nil && ctx.Err() != nil {
+				return
+			}
+
+			select {
+			case updates <- update:
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return updates
+}
+
+type quoteWatcher struct {
+	thresholds QuoteThresholds
+
+	above, below, impact bool
+	reference            float64 // out amount at the last move alert
+}
+
+func (w *quoteWatcher) check(route Route) []QuoteAlert {
+	var alerts []QuoteAlert
+	out := route.OutAmount.Float64()
+	t := w.thresholds
+
+	crossed := func(state *bool, now bool, alert QuoteAlert) {
+		if now && !*state {
+			alerts = append(alerts, alert)
+		}
+		*state = now
+	}
+	if t.OutAmountAbove > 0 {
+		crossed(&w.above, out > float64(t.OutAmountAbove), QuoteAlertOutAmountAbove)
+	}
+	if t.OutAmountBelow > 0 {
+		crossed(&w.below, out < float64(t.OutAmountBelow), QuoteAlertOutAmountBelow)
+	}
+	if t.PriceImpactPct > 0 {
+		crossed(&w.impact, route.PriceImpactPct*100 > t.PriceImpactPct, QuoteAlertPriceImpact)
+	}
+
+	if t.OutAmountMoveBps > 0 {
+		if w.reference == 0 {
+			w.reference = out
+		} else if math.Abs(out-w.reference)/w.reference*10000 >= t.OutAmountMoveBps {
+			alerts = append(alerts, QuoteAlertOutAmountMove)
+			w.reference = out
+		}
+	}
+
+	return alerts
+}
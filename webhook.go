@@ -0,0 +1,188 @@
+package jupag
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// WebhookSignatureHeader carries the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the webhook secret.
+	WebhookSignatureHeader = "X-Jupag-Signature"
+	// WebhookTimestampHeader carries the unix time of the delivery, to reject replayed payloads.
+	WebhookTimestampHeader = "X-Jupag-Timestamp"
+)
+
+// webhookAttempts is the number of deliveries of an event before it is dropped.
+const webhookAttempts = 3
+
+// WebhookEventType is the type of a webhook event.
+type WebhookEventType string
+
+const (
+	WebhookSwapSubmitted WebhookEventType = "swap.submitted"
+	WebhookSwapConfirmed WebhookEventType = "swap.confirmed"
+	WebhookSwapFailed    WebhookEventType = "swap.failed"
+	WebhookOrderFilled   WebhookEventType = "order.filled"
+)
+
+// WebhookEvent is the JSON payload posted by a WebhookNotifier.
+type WebhookEvent struct {
+	Type      WebhookEventType `json:"type"`
+	Time      time.Time        `json:"time"`
+	Signature string           `json:"signature,omitempty"`
+	Status    string           `json:"status,omitempty"` // confirmation status
+	Slot      uint64           `json:"slot,omitempty"`
+	Stage     EventStage       `json:"stage,omitempty"` // stage of a failure
+	Error     string           `json:"error,omitempty"`
+	Route     *Route           `json:"route,omitempty"`
+	Data      any              `json:"data,omitempty"` // e.g. the filled order
+}
+
+// WebhookConfig configures a WebhookNotifier.
+type WebhookConfig struct {
+	URL     string                              // required
+	Secret  string                              // HMAC key of the signature header, unsigned when empty
+	Client  *http.Client                        // default: 10s timeout
+	Buffer  int                                 // events queued for delivery before new ones are dropped, default: 256
+	OnError func(event WebhookEvent, err error) // called when an event couldn't be delivered
+}
+
+// WebhookNotifier posts execution events to a URL in the background, e.g. for external systems to react to
+// swaps without polling. Failed deliveries are retried twice. Pass Events to WithEvents.
+type WebhookNotifier struct {
+	cfg   WebhookConfig
+	queue chan WebhookEvent
+	done  chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewWebhookNotifier starts a notifier, Close stops it.
+func NewWebhookNotifier(cfg WebhookConfig) *WebhookNotifier {
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if cfg.Buffer <= 0 {
+		cfg.Buffer = 256
+	}
+	n := &WebhookNotifier{cfg: cfg, queue: make(chan WebhookEvent, cfg.Buffer), done: make(chan struct{})}
+	go n.run()
+	return n
+}
+
+// Events returns the client events posting the submissions, confirmations and failures.
+func (n *WebhookNotifier) Events() Events {
+	return Events{
+		OnSubmitted: func(_ context.Context, signature string, route Route) {
+			n.Notify(WebhookEvent{Type: WebhookSwapSubmitted, Signature: signature, Route: &route})
+		},
+		OnConfirmed: func(_ context.Context, result ConfirmationResult) {
+			e := WebhookEvent{Type: WebhookSwapConfirmed, Signature: result.Signature, Status: string(result.Status), Slot: result.Slot}
+			if result.Status != ConfirmationConfirmed {
+				e.Type = WebhookSwapFailed
+				e.Stage = StageConfirm
+				e.Error = string(result.Err)
+				if result.Reason != nil {
+					e.Error = result.Reason.Error()
+				}
+			}
+			n.Notify(e)
+		},
+		OnFailed: func(_ context.Context, stage EventStage, err error) {
+			n.Notify(WebhookEvent{Type: WebhookSwapFailed, Stage: stage, Error: err.Error()})
+		},
+	}
+}
+
+// Notify queues an event for delivery, e.g. an order fill, and reports false when the queue is full or closed.
+func (n *WebhookNotifier) Notify(event WebhookEvent) bool {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if n.closed {
+		return false
+	}
+	select {
+	case n.queue <- event:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close delivers the queued events and stops the notifier.
+func (n *WebhookNotifier) Close() {
+	n.mu.Lock()
+	if !n.closed {
+		n.closed = true
+		close(n.queue)
+	}
+	n.mu.Unlock()
+	<-n.done
+}
+
+func (n *WebhookNotifier) run() {
+	defer close(n.done)
+	for event := range n.queue {
+		var err error
+		for i := 0; i < webhookAttempts; i++ {
+			if i > 0 {
+				time.Sleep(time.Duration(i) * time.Second)
+			}
+			if err = n.deliver(event); err == nil {
+				break
+			}
+		}
+		if err != nil && n.cfg.OnError != nil {
+			n.cfg.OnError(event, err)
+		}
+	}
+}
+
+func (n *WebhookNotifier) deliver(event WebhookEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, n.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	if n.cfg.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhook(n.cfg.Secret, timestamp, body))
+	}
+
+	resp, err := n.cfg.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post webhook event: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// SignWebhook returns the signature of a webhook payload, e.g. for the receiver to compare with hmac.Equal.
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}